@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Deletion policies of a RuntimeApplication
+	RuntimeApplicationDeletionPolicyOrphan = "Orphan"
+	RuntimeApplicationDeletionPolicyDelete = "Delete"
+)
+
+// Defines the desired state of RuntimeApplication
+type RuntimeApplicationSpec struct {
+	// The application name of the RuntimeComponents that are part of the application. Defaults to the name of the RuntimeApplication.
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Application Name",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	ApplicationName *string `json:"applicationName,omitempty"`
+
+	// Pause reconciliation of all the components of the application. Defaults to false.
+	// +operator-sdk:csv:customresourcedefinitions:order=2,type=spec,displayName="Paused",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Paused *bool `json:"paused,omitempty"`
+
+	// Restart the pods of all the components of the application when this value changes. A timestamp is recommended.
+	// +operator-sdk:csv:customresourcedefinitions:order=3,type=spec,displayName="Restarted At",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	RestartedAt *string `json:"restartedAt,omitempty"`
+
+	// Whether the components are deleted with the application. Defaults to Orphan.
+	// +kubebuilder:validation:Enum=Orphan;Delete
+	// +operator-sdk:csv:customresourcedefinitions:order=4,type=spec,displayName="Deletion Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Orphan", "urn:alm:descriptor:com.tectonic.ui:select:Delete"}
+	DeletionPolicy *string `json:"deletionPolicy,omitempty"`
+}
+
+// Defines the observed state of RuntimeApplication
+type RuntimeApplicationStatus struct {
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Status Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []StatusCondition `json:"conditions,omitempty"`
+
+	// The components of the application, ordered by name.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Components"
+	Components []RuntimeApplicationComponentStatus `json:"components,omitempty"`
+
+	// The number of ready components out of the total, such as 2/3.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Ready Components"
+	ReadyComponents string `json:"readyComponents,omitempty"`
+
+	// The last value of .spec.restartedAt applied to the components.
+	RestartedAt string `json:"restartedAt,omitempty"`
+
+	// The generation identifier of this RuntimeApplication instance completely reconciled by the Operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Reports the state of a component of the application.
+type RuntimeApplicationComponentStatus struct {
+	Name    string                 `json:"name"`
+	Version string                 `json:"version,omitempty"`
+	Ready   corev1.ConditionStatus `json:"ready,omitempty"`
+	Paused  bool                   `json:"paused,omitempty"`
+	// +listType=atomic
+	Endpoints []StatusEndpoint `json:"endpoints,omitempty"`
+}
+
+// +kubebuilder:resource:path=runtimeapplications,scope=Namespaced,shortName=runtimeapp;runtimeapps
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Application",type="string",JSONPath=".spec.applicationName",priority=0,description="Application name of the components"
+// +kubebuilder:printcolumn:name="Components",type="string",JSONPath=".status.readyComponents",priority=0,description="Ready components out of the total"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status",priority=0,description="Status of the application ready condition"
+// +kubebuilder:printcolumn:name="ReadyMessage",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].message",priority=1,description="Message of the application ready condition"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
+// +operator-sdk:csv:customresourcedefinitions:order=5,displayName="RuntimeApplication"
+
+// Groups the runtime components that share an application name
+type RuntimeApplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RuntimeApplicationSpec   `json:"spec,omitempty"`
+	Status RuntimeApplicationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuntimeApplicationList contains a list of RuntimeApplication.
+type RuntimeApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RuntimeApplication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RuntimeApplication{}, &RuntimeApplicationList{})
+}
+
+// GetApplicationName returns the application name of the components of the application
+func (app *RuntimeApplication) GetApplicationName() string {
+	if app.Spec.ApplicationName == nil || *app.Spec.ApplicationName == "" {
+		return app.Name
+	}
+	return *app.Spec.ApplicationName
+}
+
+// IsPaused returns true if reconciliation of the components is paused
+func (app *RuntimeApplication) IsPaused() bool {
+	return app.Spec.Paused != nil && *app.Spec.Paused
+}
+
+// GetDeletionPolicy returns whether the components are deleted with the application
+func (app *RuntimeApplication) GetDeletionPolicy() string {
+	if app.Spec.DeletionPolicy == nil {
+		return RuntimeApplicationDeletionPolicyOrphan
+	}
+	return *app.Spec.DeletionPolicy
+}
+
+// GetCondition returns the status condition with the given type, or nil
+func (s *RuntimeApplicationStatus) GetCondition(t common.StatusConditionType) *StatusCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].GetType() == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition sets a status condition, keeping its transition time when the status does not change
+func (s *RuntimeApplicationStatus) SetCondition(c StatusCondition) {
+	condition := s.GetCondition(c.GetType())
+	if condition == nil {
+		s.Conditions = append(s.Conditions, StatusCondition{})
+		condition = &s.Conditions[len(s.Conditions)-1]
+	}
+	if condition.Status != c.Status || condition.LastTransitionTime == nil {
+		now := metav1.Now()
+		c.LastTransitionTime = &now
+	} else {
+		c.LastTransitionTime = condition.LastTransitionTime
+	}
+	*condition = c
+}
@@ -28,7 +28,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -172,6 +174,137 @@ type RuntimeComponentSpec struct {
 	// Name of the PriorityClass for the application pods.
 	// +operator-sdk:csv:customresourcedefinitions:order=31,type=spec,displayName="Priority Class Name"
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// Patches applied to the resources generated by the operator, in order, before they are created or updated.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:order=33,type=spec,displayName="Overrides"
+	Overrides []RuntimeComponentOverride `json:"overrides,omitempty"`
+
+	// Additional resources created in the namespace of the instance and owned by it. String values can use
+	// Go templates referencing .Name, .Namespace, .Labels and .References. Only kinds allowed in the operator ConfigMap can be created.
+	// +listType=atomic
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	// +operator-sdk:csv:customresourcedefinitions:order=34,type=spec,displayName="Extra Resources"
+	ExtraResources []runtime.RawExtension `json:"extraResources,omitempty"`
+
+	// The profile whose settings are merged under this spec. Values set in this spec take precedence.
+	// +operator-sdk:csv:customresourcedefinitions:order=35,type=spec,displayName="Profile"
+	Profile *RuntimeComponentProfileReference `json:"profile,omitempty"`
+
+	// Configures the certificate authorities trusted by the application.
+	// +operator-sdk:csv:customresourcedefinitions:order=36,type=spec,displayName="Trust"
+	Trust *RuntimeComponentTrust `json:"trust,omitempty"`
+
+	// Configures the observability of the application.
+	// +operator-sdk:csv:customresourcedefinitions:order=42,type=spec,displayName="Observability"
+	Observability *RuntimeComponentObservability `json:"observability,omitempty"`
+}
+
+// Configures the observability of the application.
+type RuntimeComponentObservability struct {
+	// Configures the OpenTelemetry tracing of the application.
+	// +operator-sdk:csv:customresourcedefinitions:order=43,type=spec,displayName="Tracing"
+	Tracing *RuntimeComponentTracing `json:"tracing,omitempty"`
+}
+
+// Configures the OpenTelemetry tracing of the application with the standard OTEL_* environment variables.
+type RuntimeComponentTracing struct {
+	// Endpoint of the OTLP exporter, such as http://otel-collector.observability:4317. When the collector sidecar is enabled, the application exports to the sidecar, which exports to this endpoint.
+	// +operator-sdk:csv:customresourcedefinitions:order=44,type=spec,displayName="Endpoint",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Endpoint *string `json:"endpoint,omitempty"`
+
+	// Protocol of the OTLP exporter. Defaults to the default of the OpenTelemetry SDK, or to grpc when the collector sidecar is enabled.
+	// +kubebuilder:validation:Enum=grpc;http/protobuf
+	// +operator-sdk:csv:customresourcedefinitions:order=45,type=spec,displayName="Protocol",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:grpc", "urn:alm:descriptor:com.tectonic.ui:select:http/protobuf"}
+	Protocol *string `json:"protocol,omitempty"`
+
+	// Sampler of the traces. Defaults to parentbased_always_on, so that the sampling decision of the caller is followed.
+	// +kubebuilder:validation:Enum=always_on;always_off;traceidratio;parentbased_always_on;parentbased_always_off;parentbased_traceidratio
+	// +operator-sdk:csv:customresourcedefinitions:order=46,type=spec,displayName="Sampler",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Sampler *string `json:"sampler,omitempty"`
+
+	// Argument of the sampler, such as the ratio 0.25 of the traceidratio samplers.
+	// +operator-sdk:csv:customresourcedefinitions:order=47,type=spec,displayName="Sampler Argument",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	SamplerArgument *string `json:"samplerArgument,omitempty"`
+
+	// Propagators of the trace context. Defaults to tracecontext and baggage.
+	// +listType=atomic
+	// +kubebuilder:validation:items:Enum=tracecontext;baggage;b3;b3multi;jaeger;xray;ottrace;none
+	// +operator-sdk:csv:customresourcedefinitions:order=48,type=spec,displayName="Propagators"
+	Propagators []string `json:"propagators,omitempty"`
+
+	// Resource attributes added to the traces, which override the attributes set by the operator.
+	// +operator-sdk:csv:customresourcedefinitions:order=49,type=spec,displayName="Resource Attributes"
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+
+	// Configures the automatic instrumentation of the application by the OpenTelemetry Operator.
+	// +operator-sdk:csv:customresourcedefinitions:order=50,type=spec,displayName="Instrumentation"
+	Instrumentation *RuntimeComponentTracingInstrumentation `json:"instrumentation,omitempty"`
+
+	// Configures an OpenTelemetry Collector sidecar that receives the traces of the application.
+	// +operator-sdk:csv:customresourcedefinitions:order=51,type=spec,displayName="Collector"
+	Collector *RuntimeComponentTracingCollector `json:"collector,omitempty"`
+}
+
+// Configures the automatic instrumentation of the application by the OpenTelemetry Operator.
+type RuntimeComponentTracingInstrumentation struct {
+	// Language of the instrumentation injected in the application container.
+	// +kubebuilder:validation:Enum=java;nodejs;python;dotnet;go;apache-httpd;nginx
+	// +operator-sdk:csv:customresourcedefinitions:order=52,type=spec,displayName="Language",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Language string `json:"language"`
+
+	// Name of the Instrumentation resource, in the form name or namespace/name. Defaults to the Instrumentation resource of the namespace.
+	// +operator-sdk:csv:customresourcedefinitions:order=53,type=spec,displayName="Instrumentation Name",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Name *string `json:"name,omitempty"`
+}
+
+// Configures an OpenTelemetry Collector sidecar that receives the traces of the application.
+type RuntimeComponentTracingCollector struct {
+	// Image of the collector. Defaults to the openTelemetryCollectorImage of the operator ConfigMap.
+	// +operator-sdk:csv:customresourcedefinitions:order=54,type=spec,displayName="Image",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Image *string `json:"image,omitempty"`
+
+	// Resource requests and limits for the collector container.
+	// +operator-sdk:csv:customresourcedefinitions:order=55,type=spec,displayName="Resource Requirements",xDescriptors="urn:alm:descriptor:com.tectonic.ui:resourceRequirements"
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// Configures the certificate authorities trusted by the application.
+type RuntimeComponentTrust struct {
+	// Mount the CA bundle of the operator in the namespace and set SSL_CERT_FILE and NODE_EXTRA_CA_CERTS to it,
+	// so that the application trusts the service certificates of other components. Defaults to false.
+	// +operator-sdk:csv:customresourcedefinitions:order=37,type=spec,displayName="Inject CA Bundle",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	InjectCABundle *bool `json:"injectCABundle,omitempty"`
+}
+
+// References a RuntimeComponentProfile or ClusterRuntimeComponentProfile.
+type RuntimeComponentProfileReference struct {
+	// Name of the profile.
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Name",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Name string `json:"name"`
+
+	// Kind of the profile. A RuntimeComponentProfile must be in the namespace of the RuntimeComponent. Defaults to RuntimeComponentProfile.
+	// +kubebuilder:validation:Enum=RuntimeComponentProfile;ClusterRuntimeComponentProfile
+	// +operator-sdk:csv:customresourcedefinitions:order=2,type=spec,displayName="Kind",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:RuntimeComponentProfile", "urn:alm:descriptor:com.tectonic.ui:select:ClusterRuntimeComponentProfile"}
+	Kind *string `json:"kind,omitempty"`
+}
+
+// Defines a patch applied to a resource generated by the operator.
+type RuntimeComponentOverride struct {
+	// Kind of the generated resource to patch. KnativeService refers to the Knative Service.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;Service;ServiceAccount;NetworkPolicy;HorizontalPodAutoscaler;Route;Ingress;ServiceMonitor;PodMonitor;PrometheusRule;KnativeService
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Kind",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Kind string `json:"kind"`
+
+	// Type of the patch. Can be one of strategic, merge and json. Defaults to strategic.
+	// +kubebuilder:validation:Enum=strategic;merge;json
+	// +operator-sdk:csv:customresourcedefinitions:order=2,type=spec,displayName="Patch Type",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:strategic", "urn:alm:descriptor:com.tectonic.ui:select:merge", "urn:alm:descriptor:com.tectonic.ui:select:json"}
+	PatchType *string `json:"patchType,omitempty"`
+
+	// The patch in YAML or JSON. A strategic merge or merge patch is an object, a JSON patch is a list of operations.
+	// +operator-sdk:csv:customresourcedefinitions:order=3,type=spec,displayName="Patch",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Patch string `json:"patch"`
 }
 
 // Defines the DNS
@@ -273,6 +406,30 @@ type RuntimeComponentAutoScaling struct {
 
 	// Scaling behavior of the target. If not set, the default HPAScalingRules for scale up and scale down are used.
 	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+
+	// Metrics computed by Prometheus queries. The operator generates the rules of the Prometheus Adapter that serve them as external metrics, and the metrics of the HorizontalPodAutoscaler.
+	// +listType=map
+	// +listMapKey=name
+	Prometheus []RuntimeComponentPrometheusMetric `json:"prometheus,omitempty"`
+}
+
+// Configures a metric of the autoscaler computed by a Prometheus query.
+type RuntimeComponentPrometheusMetric struct {
+	// Name of the metric. The external metric served by the Prometheus Adapter is prefixed with the name of the component.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// PromQL query that returns a single value. $(NAME), $(NAMESPACE) and $(POD_REGEX) are replaced with the name and namespace of the component and a regular expression that matches its pods.
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+
+	// Target value of the metric.
+	Target resource.Quantity `json:"target"`
+
+	// Whether the target is the value of the metric divided by the number of pods, or the value of the metric. Defaults to AverageValue.
+	// +kubebuilder:validation:Enum=AverageValue;Value
+	TargetType *string `json:"targetType,omitempty"`
 }
 
 // Configures parameters for the network service of pods.
@@ -348,6 +505,94 @@ type RuntimeComponentCertificate struct {
 	// Annotations to be added to the service certificate.
 	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Annotations",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// The cert-manager issuer of the service certificate. Defaults to the issuer of the operator CA in the namespace.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Issuer Reference"
+	IssuerRef *RuntimeComponentIssuerReference `json:"issuerRef,omitempty"`
+
+	// The requested duration of the service certificate, such as 2160h. Defaults to certManagerCertDuration in the operator ConfigMap.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Duration",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// How long before the expiry of the service certificate it is renewed, such as 360h. Must be less than the duration.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Renew Before",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+
+	// The private key of the service certificate.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Private Key"
+	PrivateKey *RuntimeComponentCertificatePrivateKey `json:"privateKey,omitempty"`
+
+	// DNS names to be added to the service certificate, in addition to the names of the service.
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="DNS Names"
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IP addresses to be added to the service certificate.
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="IP Addresses"
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// Key usages of the service certificate, such as server auth and client auth. Defaults to digital signature, key encipherment and server auth.
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Usages"
+	Usages []string `json:"usages,omitempty"`
+
+	// Keystores to be generated by cert-manager from the service certificate. They are added to the certificate secret mounted at TLS_DIR.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Keystores"
+	Keystores *RuntimeComponentKeystores `json:"keystores,omitempty"`
+
+	// Configures mutual TLS with client certificates issued by the issuer of the service certificate.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Client Authentication"
+	ClientAuth *RuntimeComponentClientAuth `json:"clientAuth,omitempty"`
+}
+
+// Configures mutual TLS with client certificates.
+type RuntimeComponentClientAuth struct {
+	// Request a client certificate for the application, mounted at CLIENT_TLS_DIR. Defaults to true.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Client Certificate",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Certificate *bool `json:"certificate,omitempty"`
+
+	// Whether clients of the application must present a client certificate. It is advertised in the binding secret. Defaults to false.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Required",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Required *bool `json:"required,omitempty"`
+}
+
+// Configures the keystore and truststore generated from the service certificate.
+type RuntimeComponentKeystores struct {
+	// Format of the keystore and truststore. The keystore contains the key and certificate of the service, and the truststore its CA. Defaults to PKCS12.
+	// +kubebuilder:validation:Enum=PKCS12;JKS
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Format",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Format *string `json:"format,omitempty"`
+
+	// Name of the secret with the password of the keystore and truststore.
+	// +kubebuilder:validation:MinLength=1
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Password Secret Reference",xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	PasswordSecretRef string `json:"passwordSecretRef"`
+
+	// Key of the password in the password secret. Defaults to password.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Password Secret Key",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	PasswordSecretKey *string `json:"passwordSecretKey,omitempty"`
+
+	// Alias of the key and certificate in a JKS keystore. Defaults to certificate. Not supported for PKCS12.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Alias",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Alias *string `json:"alias,omitempty"`
+}
+
+// Configures the private key of the service certificate.
+type RuntimeComponentCertificatePrivateKey struct {
+	// The algorithm of the private key. Defaults to RSA.
+	// +kubebuilder:validation:Enum=RSA;ECDSA;Ed25519
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Algorithm",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Algorithm *string `json:"algorithm,omitempty"`
+
+	// The size of the private key in bits. Can be 2048, 4096 or 8192 for RSA and 256, 384 or 521 for ECDSA. Ignored for Ed25519.
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Size",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	Size *int `json:"size,omitempty"`
+
+	// Whether the private key is regenerated when the certificate is renewed. Defaults to Always.
+	// +kubebuilder:validation:Enum=Never;Always
+	// +operator-sdk:csv:customresourcedefinitions:order=13,type=spec,displayName="Rotation Policy",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	RotationPolicy *string `json:"rotationPolicy,omitempty"`
 }
 
 // Defines the network policy
@@ -363,6 +608,38 @@ type RuntimeComponentNetworkPolicy struct {
 	// Specify the labels of pod(s) that incoming traffic is allowed from.
 	// +operator-sdk:csv:customresourcedefinitions:order=48,type=spec,displayName="From Labels",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	FromLabels *map[string]string `json:"fromLabels,omitempty"`
+
+	// Additional rules for incoming traffic, such as from CIDR blocks or the pods of other namespaces.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:order=49,type=spec,displayName="Ingress Rules"
+	Ingress []networkingv1.NetworkPolicyIngressRule `json:"ingress,omitempty"`
+
+	// Restrict the outgoing traffic of the pods. DNS and the pods of the dependencies are allowed.
+	// +operator-sdk:csv:customresourcedefinitions:order=50,type=spec,displayName="Egress"
+	Egress *RuntimeComponentNetworkPolicyEgress `json:"egress,omitempty"`
+}
+
+// Defines the outgoing traffic allowed by the network policy
+type RuntimeComponentNetworkPolicyEgress struct {
+	// The RuntimeComponent instances that the pods send traffic to. The outgoing traffic to their pods is allowed.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:order=51,type=spec,displayName="Dependencies"
+	Dependencies []RuntimeComponentDependency `json:"dependencies,omitempty"`
+
+	// Additional rules for outgoing traffic, such as to CIDR blocks or the pods of other namespaces.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:order=52,type=spec,displayName="Egress Rules"
+	Rules []networkingv1.NetworkPolicyEgressRule `json:"rules,omitempty"`
+}
+
+// Defines a RuntimeComponent instance that the pods send traffic to
+type RuntimeComponentDependency struct {
+	// The name of the RuntimeComponent instance.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The namespace of the RuntimeComponent instance. Defaults to the namespace of this instance.
+	Namespace *string `json:"namespace,omitempty"`
 }
 
 // Defines the desired state and cycle of applications.
@@ -423,6 +700,89 @@ type RuntimeComponentMonitoring struct {
 	// +listType=atomic
 	// +operator-sdk:csv:customresourcedefinitions:order=31,type=spec,displayName="Monitoring Endpoints",xDescriptors="urn:alm:descriptor:com.tectonic.ui:endpointList"
 	Endpoints []prometheusv1.Endpoint `json:"endpoints,omitempty"`
+
+	// Kind of the Prometheus Operator resource that scrapes the endpoints. A PodMonitor selects the pods directly, which also supports Knative services. Defaults to ServiceMonitor.
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+	// +operator-sdk:csv:customresourcedefinitions:order=32,type=spec,displayName="Monitoring Kind",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:ServiceMonitor", "urn:alm:descriptor:com.tectonic.ui:select:PodMonitor"}
+	Kind *string `json:"kind,omitempty"`
+
+	// Alerts rendered into a PrometheusRule when the Prometheus Operator is installed.
+	// +operator-sdk:csv:customresourcedefinitions:order=33,type=spec,displayName="Monitoring Alerts"
+	Alerts *RuntimeComponentMonitoringAlerts `json:"alerts,omitempty"`
+
+	// Grafana dashboard generated for the component, as a GrafanaDashboard when the Grafana Operator is installed or a ConfigMap for the Grafana dashboard sidecar otherwise.
+	// +operator-sdk:csv:customresourcedefinitions:order=39,type=spec,displayName="Monitoring Dashboard"
+	Dashboard *RuntimeComponentMonitoringDashboard `json:"dashboard,omitempty"`
+}
+
+// Configures the Grafana dashboard of the component.
+type RuntimeComponentMonitoringDashboard struct {
+	// The templates of the panels of the dashboard. Defaults to HTTP.
+	// +listType=set
+	// +kubebuilder:validation:items:Enum=HTTP;JVM;MicroProfile
+	// +operator-sdk:csv:customresourcedefinitions:order=40,type=spec,displayName="Dashboard Templates",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Templates []string `json:"templates,omitempty"`
+
+	// Labels of the Grafana instances that load the GrafanaDashboard. Defaults to all the instances of the Grafana Operator.
+	// +operator-sdk:csv:customresourcedefinitions:order=41,type=spec,displayName="Grafana Instance Selector"
+	InstanceSelector *metav1.LabelSelector `json:"instanceSelector,omitempty"`
+}
+
+// Configures the alerts of the component. The built-in alerts are enabled by default.
+type RuntimeComponentMonitoringAlerts struct {
+	// Alert when replicas are unavailable. The threshold is the number of unavailable replicas and defaults to 1. Not supported for Knative services.
+	// +operator-sdk:csv:customresourcedefinitions:order=34,type=spec,displayName="Replicas Unavailable Alert"
+	ReplicasUnavailable *RuntimeComponentAlert `json:"replicasUnavailable,omitempty"`
+
+	// Alert when containers restart repeatedly. The threshold is the number of restarts of a container in 15 minutes and defaults to 3.
+	// +operator-sdk:csv:customresourcedefinitions:order=35,type=spec,displayName="Restart Storm Alert"
+	RestartStorm *RuntimeComponentAlert `json:"restartStorm,omitempty"`
+
+	// Alert when the rate of HTTP 5xx responses is high. The threshold is the percentage of the requests in 5 minutes and defaults to 5.
+	// +operator-sdk:csv:customresourcedefinitions:order=36,type=spec,displayName="High Error Rate Alert"
+	HighErrorRate *RuntimeComponentErrorRateAlert `json:"highErrorRate,omitempty"`
+
+	// Alert when a certificate issued by cert-manager for the component expires soon. The threshold is the number of days before the expiry and defaults to 7.
+	// +operator-sdk:csv:customresourcedefinitions:order=37,type=spec,displayName="Certificate Expiry Alert"
+	CertificateExpiry *RuntimeComponentAlert `json:"certificateExpiry,omitempty"`
+
+	// Custom alerting and recording rules. $(NAME), $(NAMESPACE) and $(POD_REGEX) in the expressions, labels and annotations are replaced with the name and namespace of the component and a regular expression matching its pods.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:order=38,type=spec,displayName="Custom Rules"
+	Rules []prometheusv1.Rule `json:"rules,omitempty"`
+}
+
+// Configures a built-in alert.
+type RuntimeComponentAlert struct {
+	// Whether the alert is enabled. Defaults to true.
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Enabled",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// The threshold of the alert. Its unit depends on the alert.
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:order=2,type=spec,displayName="Threshold",xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	Threshold *int32 `json:"threshold,omitempty"`
+
+	// How long the condition must hold before the alert fires, such as 10m.
+	// +operator-sdk:csv:customresourcedefinitions:order=3,type=spec,displayName="For",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	For *prometheusv1.Duration `json:"for,omitempty"`
+
+	// The severity label of the alert. Defaults to warning.
+	// +operator-sdk:csv:customresourcedefinitions:order=4,type=spec,displayName="Severity",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Severity *string `json:"severity,omitempty"`
+}
+
+// Configures the alert on the rate of HTTP 5xx responses.
+type RuntimeComponentErrorRateAlert struct {
+	RuntimeComponentAlert `json:",inline"`
+
+	// The counter of the HTTP requests served by the component. Defaults to http_server_request_duration_seconds_count.
+	// +operator-sdk:csv:customresourcedefinitions:order=5,type=spec,displayName="Metric",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Metric *string `json:"metric,omitempty"`
+
+	// The label of the metric with the HTTP status code. Defaults to http_response_status_code.
+	// +operator-sdk:csv:customresourcedefinitions:order=6,type=spec,displayName="Status Code Label",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	StatusCodeLabel *string `json:"statusCodeLabel,omitempty"`
 }
 
 // Configures the ingress resource.
@@ -456,6 +816,37 @@ type RuntimeComponentRoute struct {
 	// HTTP traffic policy with TLS enabled. Can be one of Allow, Redirect and None.
 	// +operator-sdk:csv:customresourcedefinitions:order=43,type=spec,displayName="Insecure Edge Termination Policy",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Allow", "urn:alm:descriptor:com.tectonic.ui:select:Redirect", "urn:alm:descriptor:com.tectonic.ui:select:None"}
 	InsecureEdgeTerminationPolicy *routev1.InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty"`
+
+	// A cert-manager certificate to request for the host of the Route or Ingress. Ignored if certificateSecretRef is set.
+	// +operator-sdk:csv:customresourcedefinitions:order=44,type=spec,displayName="Certificate"
+	Certificate *RuntimeComponentRouteCertificate `json:"certificate,omitempty"`
+}
+
+// Configures the cert-manager certificate requested for the host of the Route or Ingress.
+type RuntimeComponentRouteCertificate struct {
+	// The cert-manager issuer of the certificate.
+	// +operator-sdk:csv:customresourcedefinitions:order=45,type=spec,displayName="Issuer Reference"
+	IssuerRef RuntimeComponentIssuerReference `json:"issuerRef"`
+
+	// The requested duration of the certificate, such as 2160h. Defaults to the duration set by the issuer.
+	// +operator-sdk:csv:customresourcedefinitions:order=46,type=spec,displayName="Duration",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// DNS names to be added to the certificate, in addition to the host.
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:order=47,type=spec,displayName="DNS Names"
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+// References a cert-manager Issuer or ClusterIssuer.
+type RuntimeComponentIssuerReference struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer. An Issuer must be in the namespace of the RuntimeComponent. Defaults to Issuer.
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	Kind *string `json:"kind,omitempty"`
 }
 
 // Defines the observed state of RuntimeComponent.
@@ -478,6 +869,50 @@ type RuntimeComponentStatus struct {
 
 	// The reconciliation interval in seconds.
 	ReconcileInterval *int32 `json:"reconcileInterval,omitempty"`
+
+	// The resources created from .spec.extraResources.
+	// +listType=atomic
+	ExtraResources []corev1.ObjectReference `json:"extraResources,omitempty"`
+
+	// The profile merged into the spec when the instance was last reconciled.
+	Profile *StatusProfile `json:"profile,omitempty"`
+
+	// The switch of the workload between Deployment, StatefulSet and Knative Service, while it is in progress.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Workload Migration"
+	Migration *StatusMigration `json:"migration,omitempty"`
+
+	// The certificate mounted or referenced by the instance that expires first.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Certificate Expiry"
+	CertificateExpiry *StatusCertificateExpiry `json:"certificateExpiry,omitempty"`
+}
+
+// Reports the certificate of the instance that expires first, among its service, client and route certificates and the custom CA.
+type StatusCertificateExpiry struct {
+	// The name of the secret of the certificate.
+	SecretName string `json:"secretName,omitempty"`
+	// The time when the certificate, or a certificate of its chain, expires.
+	NotAfter metav1.Time `json:"notAfter,omitempty"`
+	// The time of the last CertificateExpiring event recorded for the certificate.
+	LastWarningTime *metav1.Time `json:"lastWarningTime,omitempty"`
+}
+
+// Reports the switch of the workload to another kind. The previous workload keeps serving
+// traffic until the new one is ready.
+type StatusMigration struct {
+	// The kind of the previous workload. One of Deployment, StatefulSet and KnativeService.
+	From string `json:"from,omitempty"`
+	// The kind of the new workload. One of Deployment, StatefulSet and KnativeService.
+	To string `json:"to,omitempty"`
+	// WaitingForWorkload while the new workload is not ready, then SwitchingTraffic while the previous resources are removed.
+	Phase string `json:"phase,omitempty"`
+}
+
+// Reports the profile merged into the spec.
+type StatusProfile struct {
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+	// The generation of the profile merged into the spec.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // Defines possible status conditions.
@@ -511,6 +946,7 @@ const (
 	StatusConditionTypeResourcesReady StatusConditionType = "ResourcesReady"
 	StatusConditionTypeReady          StatusConditionType = "Ready"
 	StatusConditionTypeWarning        StatusConditionType = "Warning"
+	StatusConditionTypePaused         StatusConditionType = "Paused"
 
 	// Status Endpoint Scopes
 	StatusEndpointScopeExternal StatusEndpointScope = "External"
@@ -540,7 +976,7 @@ type StatusVersions struct {
 // +kubebuilder:printcolumn:name="WarningReason",type="string",JSONPath=".status.conditions[?(@.type=='Warning')].reason",priority=1,description="Reason for the failure of component warning condition"
 // +kubebuilder:printcolumn:name="WarningMessage",type="string",JSONPath=".status.conditions[?(@.type=='Warning')].message",priority=1,description="Failure message from component warning condition"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
-// +operator-sdk:csv:customresourcedefinitions:displayName="RuntimeComponent",resources={{Deployment,v1},{Service,v1},{StatefulSet,v1},{Route,v1},{HorizontalPodAutoscaler,v1},{ServiceAccount,v1},{Secret,v1},{NetworkPolicy,v1}}
+// +operator-sdk:csv:customresourcedefinitions:order=1,displayName="RuntimeComponent",resources={{Deployment,v1},{Service,v1},{StatefulSet,v1},{Route,v1},{HorizontalPodAutoscaler,v1},{ServiceAccount,v1},{Secret,v1},{NetworkPolicy,v1}}
 
 // Represents the deployment of a runtime component
 type RuntimeComponent struct {
@@ -743,6 +1179,17 @@ func (cr *RuntimeComponent) GetApplicationName() string {
 	return cr.Spec.ApplicationName
 }
 
+// GetApplicationNameOrDefault returns the application name of the component, as defaulted by Initialize
+func (cr *RuntimeComponent) GetApplicationNameOrDefault() string {
+	if cr.Spec.ApplicationName != "" {
+		return cr.Spec.ApplicationName
+	}
+	if cr.Labels["app.kubernetes.io/part-of"] != "" {
+		return cr.Labels["app.kubernetes.io/part-of"]
+	}
+	return cr.Name
+}
+
 // GetMonitoring returns monitoring settings
 func (cr *RuntimeComponent) GetMonitoring() common.BaseComponentMonitoring {
 	if cr.Spec.Monitoring == nil {
@@ -856,6 +1303,93 @@ func (s *RuntimeComponentStatus) SetBinding(r *corev1.LocalObjectReference) {
 	s.Binding = r
 }
 
+// GetExtraResources returns the resources created from .spec.extraResources
+func (s *RuntimeComponentStatus) GetExtraResources() []corev1.ObjectReference {
+	return s.ExtraResources
+}
+
+// SetExtraResources sets the resources created from .spec.extraResources
+func (s *RuntimeComponentStatus) SetExtraResources(refs []corev1.ObjectReference) {
+	s.ExtraResources = refs
+}
+
+// GetMigration returns the workload migration in progress, or nil
+func (s *RuntimeComponentStatus) GetMigration() common.StatusMigration {
+	if s.Migration == nil {
+		return nil
+	}
+	return s.Migration
+}
+
+// SetMigration sets the workload migration in progress
+func (s *RuntimeComponentStatus) SetMigration(from string, to string, phase string) {
+	s.Migration = &StatusMigration{From: from, To: to, Phase: phase}
+}
+
+// UnsetMigration removes the workload migration once it is complete
+func (s *RuntimeComponentStatus) UnsetMigration() {
+	s.Migration = nil
+}
+
+// GetCertificateExpiry returns the certificate of the instance that expires first, or nil
+func (s *RuntimeComponentStatus) GetCertificateExpiry() common.StatusCertificateExpiry {
+	if s.CertificateExpiry == nil {
+		return nil
+	}
+	return s.CertificateExpiry
+}
+
+// SetCertificateExpiry sets the certificate of the instance that expires first. The time of the last warning is kept
+// while the certificate does not change
+func (s *RuntimeComponentStatus) SetCertificateExpiry(secretName string, notAfter metav1.Time) {
+	if e := s.CertificateExpiry; e != nil && e.SecretName == secretName && e.NotAfter.Equal(&notAfter) {
+		return
+	}
+	s.CertificateExpiry = &StatusCertificateExpiry{SecretName: secretName, NotAfter: notAfter}
+}
+
+// SetCertificateExpiryWarningTime sets the time of the last warning on the certificate that expires first
+func (s *RuntimeComponentStatus) SetCertificateExpiryWarningTime(t metav1.Time) {
+	if s.CertificateExpiry != nil {
+		s.CertificateExpiry.LastWarningTime = &t
+	}
+}
+
+// UnsetCertificateExpiry removes the certificate expiry when the instance has no certificate
+func (s *RuntimeComponentStatus) UnsetCertificateExpiry() {
+	s.CertificateExpiry = nil
+}
+
+// GetSecretName returns the name of the secret of the certificate
+func (e *StatusCertificateExpiry) GetSecretName() string {
+	return e.SecretName
+}
+
+// GetNotAfter returns the expiry of the certificate
+func (e *StatusCertificateExpiry) GetNotAfter() metav1.Time {
+	return e.NotAfter
+}
+
+// GetLastWarningTime returns the time of the last warning on the expiry of the certificate, or nil
+func (e *StatusCertificateExpiry) GetLastWarningTime() *metav1.Time {
+	return e.LastWarningTime
+}
+
+// GetFrom returns the kind of the previous workload
+func (m *StatusMigration) GetFrom() string {
+	return m.From
+}
+
+// GetTo returns the kind of the new workload
+func (m *StatusMigration) GetTo() string {
+	return m.To
+}
+
+// GetPhase returns the phase of the migration
+func (m *StatusMigration) GetPhase() string {
+	return m.Phase
+}
+
 // GetMinReplicas returns minimum replicas
 func (a *RuntimeComponentAutoScaling) GetMinReplicas() *int32 {
 	return a.MinReplicas
@@ -886,6 +1420,38 @@ func (a *RuntimeComponentAutoScaling) GetHorizontalPodAutoscalerBehavior() *auto
 	return a.Behavior
 }
 
+// GetPrometheus returns the metrics computed by Prometheus queries
+func (a *RuntimeComponentAutoScaling) GetPrometheus() []common.BaseComponentPrometheusMetric {
+	metrics := make([]common.BaseComponentPrometheusMetric, len(a.Prometheus))
+	for i := range a.Prometheus {
+		metrics[i] = &a.Prometheus[i]
+	}
+	return metrics
+}
+
+// GetName returns the name of the metric
+func (m *RuntimeComponentPrometheusMetric) GetName() string {
+	return m.Name
+}
+
+// GetQuery returns the PromQL query of the metric
+func (m *RuntimeComponentPrometheusMetric) GetQuery() string {
+	return m.Query
+}
+
+// GetTarget returns the target value of the metric
+func (m *RuntimeComponentPrometheusMetric) GetTarget() resource.Quantity {
+	return m.Target
+}
+
+// GetTargetType returns the type of the target of the metric
+func (m *RuntimeComponentPrometheusMetric) GetTargetType() autoscalingv2.MetricTargetType {
+	if m.TargetType == nil {
+		return autoscalingv2.AverageValueMetricType
+	}
+	return autoscalingv2.MetricTargetType(*m.TargetType)
+}
+
 // GetSize returns persistent volume size
 func (s *RuntimeComponentStorage) GetSize() string {
 	return s.Size
@@ -998,6 +1564,46 @@ func (np *RuntimeComponentNetworkPolicy) IsDisabled() bool {
 	return np.Disable != nil && *np.Disable
 }
 
+// GetIngress returns the additional rules for incoming traffic
+func (np *RuntimeComponentNetworkPolicy) GetIngress() []networkingv1.NetworkPolicyIngressRule {
+	return np.Ingress
+}
+
+// GetEgress returns the outgoing traffic allowed by the network policy, or nil if it is not restricted
+func (np *RuntimeComponentNetworkPolicy) GetEgress() common.BaseComponentNetworkPolicyEgress {
+	if np.Egress == nil {
+		return nil
+	}
+	return np.Egress
+}
+
+// GetRules returns the additional rules for outgoing traffic
+func (e *RuntimeComponentNetworkPolicyEgress) GetRules() []networkingv1.NetworkPolicyEgressRule {
+	return e.Rules
+}
+
+// GetDependencies returns the components that the pods send traffic to
+func (e *RuntimeComponentNetworkPolicyEgress) GetDependencies() []common.BaseComponentDependency {
+	dependencies := make([]common.BaseComponentDependency, len(e.Dependencies))
+	for i := range e.Dependencies {
+		dependencies[i] = &e.Dependencies[i]
+	}
+	return dependencies
+}
+
+// GetName returns the name of the component
+func (d *RuntimeComponentDependency) GetName() string {
+	return d.Name
+}
+
+// GetNamespace returns the namespace of the component, or an empty string for the namespace of the instance
+func (d *RuntimeComponentDependency) GetNamespace() string {
+	if d.Namespace == nil {
+		return ""
+	}
+	return *d.Namespace
+}
+
 // GetLabels returns labels to be added on ServiceMonitor
 func (m *RuntimeComponentMonitoring) GetLabels() map[string]string {
 	return m.Labels
@@ -1008,6 +1614,119 @@ func (m *RuntimeComponentMonitoring) GetEndpoints() []prometheusv1.Endpoint {
 	return m.Endpoints
 }
 
+// GetKind returns the kind of the monitoring resource, ServiceMonitor or PodMonitor
+func (m *RuntimeComponentMonitoring) GetKind() string {
+	if m.Kind == nil || *m.Kind == "" {
+		return common.MonitoringKindServiceMonitor
+	}
+	return *m.Kind
+}
+
+// GetAlerts returns the alerts of the component, or nil if no alert is configured
+func (m *RuntimeComponentMonitoring) GetAlerts() common.BaseComponentMonitoringAlerts {
+	if m.Alerts == nil {
+		return nil
+	}
+	return m.Alerts
+}
+
+// GetDashboard returns the Grafana dashboard of the component, or nil if no dashboard is generated
+func (m *RuntimeComponentMonitoring) GetDashboard() common.BaseComponentMonitoringDashboard {
+	if m.Dashboard == nil {
+		return nil
+	}
+	return m.Dashboard
+}
+
+// GetTemplates returns the templates of the panels of the dashboard
+func (d *RuntimeComponentMonitoringDashboard) GetTemplates() []string {
+	if len(d.Templates) == 0 {
+		return []string{common.DashboardTemplateHTTP}
+	}
+	return d.Templates
+}
+
+// GetInstanceSelector returns the labels of the Grafana instances that load the dashboard
+func (d *RuntimeComponentMonitoringDashboard) GetInstanceSelector() *metav1.LabelSelector {
+	return d.InstanceSelector
+}
+
+// GetReplicasUnavailable returns the alert on unavailable replicas
+func (a *RuntimeComponentMonitoringAlerts) GetReplicasUnavailable() common.BaseComponentAlert {
+	if a.ReplicasUnavailable == nil {
+		return nil
+	}
+	return a.ReplicasUnavailable
+}
+
+// GetRestartStorm returns the alert on container restarts
+func (a *RuntimeComponentMonitoringAlerts) GetRestartStorm() common.BaseComponentAlert {
+	if a.RestartStorm == nil {
+		return nil
+	}
+	return a.RestartStorm
+}
+
+// GetHighErrorRate returns the alert on the rate of HTTP 5xx responses
+func (a *RuntimeComponentMonitoringAlerts) GetHighErrorRate() common.BaseComponentErrorRateAlert {
+	if a.HighErrorRate == nil {
+		return nil
+	}
+	return a.HighErrorRate
+}
+
+// GetCertificateExpiry returns the alert on certificate expiry
+func (a *RuntimeComponentMonitoringAlerts) GetCertificateExpiry() common.BaseComponentAlert {
+	if a.CertificateExpiry == nil {
+		return nil
+	}
+	return a.CertificateExpiry
+}
+
+// GetRules returns the custom rules
+func (a *RuntimeComponentMonitoringAlerts) GetRules() []prometheusv1.Rule {
+	return a.Rules
+}
+
+// IsEnabled returns true unless the alert is disabled
+func (a *RuntimeComponentAlert) IsEnabled() bool {
+	return a.Enabled == nil || *a.Enabled
+}
+
+// GetThreshold returns the threshold of the alert
+func (a *RuntimeComponentAlert) GetThreshold() *int32 {
+	return a.Threshold
+}
+
+// GetFor returns how long the condition must hold before the alert fires
+func (a *RuntimeComponentAlert) GetFor() *prometheusv1.Duration {
+	return a.For
+}
+
+// GetSeverity returns the severity label of the alert
+func (a *RuntimeComponentAlert) GetSeverity() string {
+	if a.Severity == nil || *a.Severity == "" {
+		return "warning"
+	}
+	return *a.Severity
+}
+
+// GetMetric returns the counter of the HTTP requests
+func (a *RuntimeComponentErrorRateAlert) GetMetric() string {
+	if a.Metric == nil || *a.Metric == "" {
+		return common.DefaultErrorRateMetric
+	}
+	return *a.Metric
+}
+
+// GetStatusCodeLabel returns the label of the HTTP status code
+func (a *RuntimeComponentErrorRateAlert) GetStatusCodeLabel() string {
+	if a.StatusCodeLabel == nil || *a.StatusCodeLabel == "" {
+		return common.DefaultErrorRateStatusCodeLabel
+	}
+	return *a.StatusCodeLabel
+}
+
 // GetAnnotations returns route annotations
 func (r *RuntimeComponentRoute) GetAnnotations() map[string]string {
 	return r.Annotations
@@ -1043,6 +1762,42 @@ func (r *RuntimeComponentRoute) GetPathType() networkingv1.PathType {
 	return r.PathType
 }
 
+// GetCertificate returns the cert-manager certificate to request for the route host
+func (r *RuntimeComponentRoute) GetCertificate() common.BaseComponentRouteCertificate {
+	if r.Certificate == nil {
+		return nil
+	}
+	return r.Certificate
+}
+
+// GetIssuerRef returns the issuer of the route certificate
+func (c *RuntimeComponentRouteCertificate) GetIssuerRef() common.BaseComponentIssuerReference {
+	return &c.IssuerRef
+}
+
+// GetDuration returns the requested duration of the route certificate
+func (c *RuntimeComponentRouteCertificate) GetDuration() *metav1.Duration {
+	return c.Duration
+}
+
+// GetDNSNames returns the DNS names added to the route certificate
+func (c *RuntimeComponentRouteCertificate) GetDNSNames() []string {
+	return c.DNSNames
+}
+
+// GetName returns the name of the issuer
+func (i *RuntimeComponentIssuerReference) GetName() string {
+	return i.Name
+}
+
+// GetKind returns the kind of the issuer, Issuer or ClusterIssuer
+func (i *RuntimeComponentIssuerReference) GetKind() string {
+	if i.Kind == nil || *i.Kind == "" {
+		return "Issuer"
+	}
+	return *i.Kind
+}
+
 // GetNodeAffinity returns node affinity
 func (a *RuntimeComponentAffinity) GetNodeAffinity() *corev1.NodeAffinity {
 	return a.NodeAffinity
@@ -1115,6 +1870,153 @@ func (cr *RuntimeComponent) GetPriorityClassName() *string {
 	return cr.Spec.PriorityClassName
 }
 
+// GetOverrides returns the patches applied to the generated resources
+func (cr *RuntimeComponent) GetOverrides() []common.BaseComponentOverride {
+	overrides := make([]common.BaseComponentOverride, len(cr.Spec.Overrides))
+	for i := range cr.Spec.Overrides {
+		overrides[i] = &cr.Spec.Overrides[i]
+	}
+	return overrides
+}
+
+// GetExtraResources returns the additional resources owned by the instance
+func (cr *RuntimeComponent) GetExtraResources() []runtime.RawExtension {
+	return cr.Spec.ExtraResources
+}
+
+// GetTrust returns the certificate authorities trusted by the application
+func (cr *RuntimeComponent) GetTrust() common.BaseComponentTrust {
+	if cr.Spec.Trust == nil {
+		return nil
+	}
+	return cr.Spec.Trust
+}
+
+// GetInjectCABundle returns whether the CA bundle of the operator is mounted in the application pods
+func (t *RuntimeComponentTrust) GetInjectCABundle() *bool {
+	return t.InjectCABundle
+}
+
+// GetObservability returns the observability settings of the application
+func (cr *RuntimeComponent) GetObservability() common.BaseComponentObservability {
+	if cr.Spec.Observability == nil {
+		return nil
+	}
+	return cr.Spec.Observability
+}
+
+// GetTracing returns the tracing settings of the application
+func (o *RuntimeComponentObservability) GetTracing() common.BaseComponentTracing {
+	if o.Tracing == nil {
+		return nil
+	}
+	return o.Tracing
+}
+
+// GetEndpoint returns the endpoint of the OTLP exporter
+func (t *RuntimeComponentTracing) GetEndpoint() *string {
+	return t.Endpoint
+}
+
+// GetProtocol returns the protocol of the OTLP exporter
+func (t *RuntimeComponentTracing) GetProtocol() *string {
+	return t.Protocol
+}
+
+// GetSampler returns the sampler of the traces
+func (t *RuntimeComponentTracing) GetSampler() string {
+	if t.Sampler == nil {
+		return common.DefaultTracingSampler
+	}
+	return *t.Sampler
+}
+
+// GetSamplerArgument returns the argument of the sampler
+func (t *RuntimeComponentTracing) GetSamplerArgument() *string {
+	return t.SamplerArgument
+}
+
+// GetPropagators returns the propagators of the trace context
+func (t *RuntimeComponentTracing) GetPropagators() []string {
+	if len(t.Propagators) == 0 {
+		return common.DefaultTracingPropagators
+	}
+	return t.Propagators
+}
+
+// GetResourceAttributes returns the resource attributes added to the traces
+func (t *RuntimeComponentTracing) GetResourceAttributes() map[string]string {
+	return t.ResourceAttributes
+}
+
+// GetInstrumentation returns the automatic instrumentation settings
+func (t *RuntimeComponentTracing) GetInstrumentation() common.BaseComponentTracingInstrumentation {
+	if t.Instrumentation == nil {
+		return nil
+	}
+	return t.Instrumentation
+}
+
+// GetCollector returns the collector sidecar settings
+func (t *RuntimeComponentTracing) GetCollector() common.BaseComponentTracingCollector {
+	if t.Collector == nil {
+		return nil
+	}
+	return t.Collector
+}
+
+// GetLanguage returns the language of the instrumentation
+func (i *RuntimeComponentTracingInstrumentation) GetLanguage() string {
+	return i.Language
+}
+
+// GetName returns the name of the Instrumentation resource, or true for the Instrumentation resource of the namespace
+func (i *RuntimeComponentTracingInstrumentation) GetName() string {
+	if i.Name == nil || *i.Name == "" {
+		return "true"
+	}
+	return *i.Name
+}
+
+// GetImage returns the image of the collector
+func (c *RuntimeComponentTracingCollector) GetImage() string {
+	if c.Image == nil || *c.Image == "" {
+		return common.LoadFromConfig(common.Config, common.OpConfigOpenTelemetryCollectorImage)
+	}
+	return *c.Image
+}
+
+// GetResources returns the resource requirements of the collector
+func (c *RuntimeComponentTracingCollector) GetResources() *corev1.ResourceRequirements {
+	return c.Resources
+}
+
+// GetKind returns the kind of the referenced profile
+func (p *RuntimeComponentProfileReference) GetKind() string {
+	if p.Kind == nil {
+		return RuntimeComponentProfileKind
+	}
+	return *p.Kind
+}
+
+// GetKind returns the kind of the generated resource to patch
+func (o *RuntimeComponentOverride) GetKind() string {
+	return o.Kind
+}
+
+// GetPatchType returns the type of the patch
+func (o *RuntimeComponentOverride) GetPatchType() string {
+	if o.PatchType == nil {
+		return common.OverridePatchTypeStrategic
+	}
+	return *o.PatchType
+}
+
+// GetPatch returns the patch
+func (o *RuntimeComponentOverride) GetPatch() string {
+	return o.Patch
+}
+
 // Initialize the RuntimeComponent instance
 func (cr *RuntimeComponent) Initialize() {
 	if cr.Spec.PullPolicy == nil {
@@ -1343,7 +2245,7 @@ func (s *RuntimeComponentStatus) UnsetCondition(c common.StatusCondition) {
 				s.Conditions = s.Conditions[:i]
 			} else {
 				// there are more elements after the match.
-				s.Conditions = append(s.Conditions[:i], s.Conditions[i+1])
+				s.Conditions = append(s.Conditions[:i], s.Conditions[i+1:]...)
 			}
 			return
 		}
@@ -1390,6 +2292,8 @@ func convertToCommonStatusConditionType(c StatusConditionType) common.StatusCond
 		return common.StatusConditionTypeReady
 	case StatusConditionTypeWarning:
 		return common.StatusConditionTypeWarning
+	case StatusConditionTypePaused:
+		return common.StatusConditionTypePaused
 	default:
 		panic(c)
 	}
@@ -1405,6 +2309,8 @@ func convertFromCommonStatusConditionType(c common.StatusConditionType) StatusCo
 		return StatusConditionTypeReady
 	case common.StatusConditionTypeWarning:
 		return StatusConditionTypeWarning
+	case common.StatusConditionTypePaused:
+		return StatusConditionTypePaused
 	default:
 		panic(c)
 	}
@@ -1512,6 +2418,114 @@ func (c *RuntimeComponentCertificate) GetAnnotations() map[string]string {
 	return c.Annotations
 }
 
+// GetIssuerRef returns the issuer of the service certificate
+func (c *RuntimeComponentCertificate) GetIssuerRef() common.BaseComponentIssuerReference {
+	if c.IssuerRef == nil {
+		return nil
+	}
+	return c.IssuerRef
+}
+
+// GetDuration returns the requested duration of the service certificate
+func (c *RuntimeComponentCertificate) GetDuration() *metav1.Duration {
+	return c.Duration
+}
+
+// GetRenewBefore returns how long before its expiry the service certificate is renewed
+func (c *RuntimeComponentCertificate) GetRenewBefore() *metav1.Duration {
+	return c.RenewBefore
+}
+
+// GetPrivateKey returns the private key configuration of the service certificate
+func (c *RuntimeComponentCertificate) GetPrivateKey() common.BaseComponentCertificatePrivateKey {
+	if c.PrivateKey == nil {
+		return nil
+	}
+	return c.PrivateKey
+}
+
+// GetDNSNames returns the DNS names added to the service certificate
+func (c *RuntimeComponentCertificate) GetDNSNames() []string {
+	return c.DNSNames
+}
+
+// GetIPAddresses returns the IP addresses added to the service certificate
+func (c *RuntimeComponentCertificate) GetIPAddresses() []string {
+	return c.IPAddresses
+}
+
+// GetUsages returns the key usages of the service certificate
+func (c *RuntimeComponentCertificate) GetUsages() []string {
+	return c.Usages
+}
+
+// GetKeystores returns the keystores generated from the service certificate
+func (c *RuntimeComponentCertificate) GetKeystores() common.BaseComponentKeystores {
+	if c.Keystores == nil {
+		return nil
+	}
+	return c.Keystores
+}
+
+// GetClientAuth returns the mutual TLS configuration of the service certificate
+func (c *RuntimeComponentCertificate) GetClientAuth() common.BaseComponentClientAuth {
+	if c.ClientAuth == nil {
+		return nil
+	}
+	return c.ClientAuth
+}
+
+// GetCertificate returns whether a client certificate is requested for the application
+func (a *RuntimeComponentClientAuth) GetCertificate() *bool {
+	return a.Certificate
+}
+
+// GetRequired returns whether clients of the application must present a client certificate
+func (a *RuntimeComponentClientAuth) GetRequired() *bool {
+	return a.Required
+}
+
+// GetFormat returns the format of the keystores, PKCS12 or JKS
+func (k *RuntimeComponentKeystores) GetFormat() string {
+	if k.Format == nil || *k.Format == "" {
+		return common.KeystoreFormatPKCS12
+	}
+	return *k.Format
+}
+
+// GetPasswordSecretRef returns the name of the secret with the password of the keystores
+func (k *RuntimeComponentKeystores) GetPasswordSecretRef() string {
+	return k.PasswordSecretRef
+}
+
+// GetPasswordSecretKey returns the key of the password in the password secret
+func (k *RuntimeComponentKeystores) GetPasswordSecretKey() string {
+	if k.PasswordSecretKey == nil || *k.PasswordSecretKey == "" {
+		return "password"
+	}
+	return *k.PasswordSecretKey
+}
+
+// GetAlias returns the alias of the key and certificate in a JKS keystore
+func (k *RuntimeComponentKeystores) GetAlias() *string {
+	return k.Alias
+}
+
+// GetAlgorithm returns the algorithm of the private key
+func (k *RuntimeComponentCertificatePrivateKey) GetAlgorithm() *string {
+	return k.Algorithm
+}
+
+// GetSize returns the size of the private key
+func (k *RuntimeComponentCertificatePrivateKey) GetSize() *int {
+	return k.Size
+}
+
+// GetRotationPolicy returns the rotation policy of the private key
+func (k *RuntimeComponentCertificatePrivateKey) GetRotationPolicy() *string {
+	return k.RotationPolicy
+}
+
 func convertToCommonStatusEndpointScope(c StatusEndpointScope) common.StatusEndpointScope {
 	switch c {
 	case StatusEndpointScopeExternal:
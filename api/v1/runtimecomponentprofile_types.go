@@ -0,0 +1,103 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Kinds of profile that a RuntimeComponent can reference
+	RuntimeComponentProfileKind        = "RuntimeComponentProfile"
+	ClusterRuntimeComponentProfileKind = "ClusterRuntimeComponentProfile"
+)
+
+// Defines the settings shared by the RuntimeComponents that reference the profile.
+type RuntimeComponentProfileSpec struct {
+	// Application container probes.
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Probes"
+	Probes *RuntimeComponentProbes `json:"probes,omitempty"`
+
+	// Resource requests and limits for the application container.
+	// +operator-sdk:csv:customresourcedefinitions:order=2,type=spec,displayName="Resource Requirements",xDescriptors="urn:alm:descriptor:com.tectonic.ui:resourceRequirements"
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Tolerations to be added to application pods. They are added to the tolerations of the RuntimeComponent.
+	// +listType=atomic
+	// +operator-sdk:csv:customresourcedefinitions:order=3,type=spec,displayName="Tolerations"
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Configures pods to run on particular Nodes.
+	// +operator-sdk:csv:customresourcedefinitions:order=4,type=spec,displayName="Affinity"
+	Affinity *RuntimeComponentAffinity `json:"affinity,omitempty"`
+
+	// Security context for the application container.
+	// +operator-sdk:csv:customresourcedefinitions:order=5,type=spec,displayName="Security Context"
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// Specifies parameters for Service Monitor.
+	// +operator-sdk:csv:customresourcedefinitions:order=6,type=spec,displayName="Monitoring"
+	Monitoring *RuntimeComponentMonitoring `json:"monitoring,omitempty"`
+
+	// The service account to use for application pods.
+	// +operator-sdk:csv:customresourcedefinitions:order=7,type=spec,displayName="Service Account"
+	ServiceAccount *RuntimeComponentServiceAccount `json:"serviceAccount,omitempty"`
+
+	// Configures the observability of the application, such as the tracing settings shared by its components.
+	// +operator-sdk:csv:customresourcedefinitions:order=8,type=spec,displayName="Observability"
+	Observability *RuntimeComponentObservability `json:"observability,omitempty"`
+}
+
+// Defines the observed state of a profile. Profiles are read by the RuntimeComponents that reference them, and have no status fields yet.
+type RuntimeComponentProfileStatus struct {
+}
+
+// +kubebuilder:resource:path=runtimecomponentprofiles,scope=Namespaced,shortName=compprofile;compprofiles
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
+// +operator-sdk:csv:customresourcedefinitions:order=6,displayName="RuntimeComponentProfile"
+
+// Settings shared by the RuntimeComponents of a namespace that reference the profile
+type RuntimeComponentProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RuntimeComponentProfileSpec   `json:"spec,omitempty"`
+	Status RuntimeComponentProfileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuntimeComponentProfileList contains a list of RuntimeComponentProfile.
+type RuntimeComponentProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RuntimeComponentProfile `json:"items"`
+}
+
+// +kubebuilder:resource:path=clusterruntimecomponentprofiles,scope=Cluster,shortName=clustercompprofile;clustercompprofiles
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
+// +operator-sdk:csv:customresourcedefinitions:order=7,displayName="ClusterRuntimeComponentProfile"
+
+// Settings shared by the RuntimeComponents of any namespace that reference the profile
+type ClusterRuntimeComponentProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RuntimeComponentProfileSpec   `json:"spec,omitempty"`
+	Status RuntimeComponentProfileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterRuntimeComponentProfileList contains a list of ClusterRuntimeComponentProfile.
+type ClusterRuntimeComponentProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterRuntimeComponentProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RuntimeComponentProfile{}, &RuntimeComponentProfileList{})
+	SchemeBuilder.Register(&ClusterRuntimeComponentProfile{}, &ClusterRuntimeComponentProfileList{})
+}
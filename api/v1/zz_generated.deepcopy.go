@@ -27,9 +27,70 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRuntimeComponentProfile) DeepCopyInto(out *ClusterRuntimeComponentProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRuntimeComponentProfile.
+func (in *ClusterRuntimeComponentProfile) DeepCopy() *ClusterRuntimeComponentProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRuntimeComponentProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRuntimeComponentProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRuntimeComponentProfileList) DeepCopyInto(out *ClusterRuntimeComponentProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterRuntimeComponentProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRuntimeComponentProfileList.
+func (in *ClusterRuntimeComponentProfileList) DeepCopy() *ClusterRuntimeComponentProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRuntimeComponentProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRuntimeComponentProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperationStatusCondition) DeepCopyInto(out *OperationStatusCondition) {
 	*out = *in
@@ -50,6 +111,149 @@ func (in *OperationStatusCondition) DeepCopy() *OperationStatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeApplication) DeepCopyInto(out *RuntimeApplication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeApplication.
+func (in *RuntimeApplication) DeepCopy() *RuntimeApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuntimeApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeApplicationComponentStatus) DeepCopyInto(out *RuntimeApplicationComponentStatus) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]StatusEndpoint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeApplicationComponentStatus.
+func (in *RuntimeApplicationComponentStatus) DeepCopy() *RuntimeApplicationComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeApplicationComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeApplicationList) DeepCopyInto(out *RuntimeApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RuntimeApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeApplicationList.
+func (in *RuntimeApplicationList) DeepCopy() *RuntimeApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuntimeApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeApplicationSpec) DeepCopyInto(out *RuntimeApplicationSpec) {
+	*out = *in
+	if in.ApplicationName != nil {
+		in, out := &in.ApplicationName, &out.ApplicationName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RestartedAt != nil {
+		in, out := &in.RestartedAt, &out.RestartedAt
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeApplicationSpec.
+func (in *RuntimeApplicationSpec) DeepCopy() *RuntimeApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeApplicationStatus) DeepCopyInto(out *RuntimeApplicationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]RuntimeApplicationComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeApplicationStatus.
+func (in *RuntimeApplicationStatus) DeepCopy() *RuntimeApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeComponent) DeepCopyInto(out *RuntimeComponent) {
 	*out = *in
@@ -119,6 +323,41 @@ func (in *RuntimeComponentAffinity) DeepCopy() *RuntimeComponentAffinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentAlert) DeepCopyInto(out *RuntimeComponentAlert) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.For != nil {
+		in, out := &in.For, &out.For
+		*out = new(monitoringv1.Duration)
+		**out = **in
+	}
+	if in.Severity != nil {
+		in, out := &in.Severity, &out.Severity
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentAlert.
+func (in *RuntimeComponentAlert) DeepCopy() *RuntimeComponentAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeComponentAutoScaling) DeepCopyInto(out *RuntimeComponentAutoScaling) {
 	*out = *in
@@ -149,6 +388,13 @@ func (in *RuntimeComponentAutoScaling) DeepCopyInto(out *RuntimeComponentAutoSca
 		*out = new(v2.HorizontalPodAutoscalerBehavior)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = make([]RuntimeComponentPrometheusMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentAutoScaling.
@@ -171,6 +417,51 @@ func (in *RuntimeComponentCertificate) DeepCopyInto(out *RuntimeComponentCertifi
 			(*out)[key] = val
 		}
 	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(RuntimeComponentIssuerReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PrivateKey != nil {
+		in, out := &in.PrivateKey, &out.PrivateKey
+		*out = new(RuntimeComponentCertificatePrivateKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Usages != nil {
+		in, out := &in.Usages, &out.Usages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keystores != nil {
+		in, out := &in.Keystores, &out.Keystores
+		*out = new(RuntimeComponentKeystores)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientAuth != nil {
+		in, out := &in.ClientAuth, &out.ClientAuth
+		*out = new(RuntimeComponentClientAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentCertificate.
@@ -183,6 +474,61 @@ func (in *RuntimeComponentCertificate) DeepCopy() *RuntimeComponentCertificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentCertificatePrivateKey) DeepCopyInto(out *RuntimeComponentCertificatePrivateKey) {
+	*out = *in
+	if in.Algorithm != nil {
+		in, out := &in.Algorithm, &out.Algorithm
+		*out = new(string)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(int)
+		**out = **in
+	}
+	if in.RotationPolicy != nil {
+		in, out := &in.RotationPolicy, &out.RotationPolicy
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentCertificatePrivateKey.
+func (in *RuntimeComponentCertificatePrivateKey) DeepCopy() *RuntimeComponentCertificatePrivateKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentCertificatePrivateKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentClientAuth) DeepCopyInto(out *RuntimeComponentClientAuth) {
+	*out = *in
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentClientAuth.
+func (in *RuntimeComponentClientAuth) DeepCopy() *RuntimeComponentClientAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentClientAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeComponentDNS) DeepCopyInto(out *RuntimeComponentDNS) {
 	*out = *in
@@ -208,6 +554,26 @@ func (in *RuntimeComponentDNS) DeepCopy() *RuntimeComponentDNS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentDependency) DeepCopyInto(out *RuntimeComponentDependency) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentDependency.
+func (in *RuntimeComponentDependency) DeepCopy() *RuntimeComponentDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeComponentDeployment) DeepCopyInto(out *RuntimeComponentDeployment) {
 	*out = *in
@@ -236,7 +602,83 @@ func (in *RuntimeComponentDeployment) DeepCopy() *RuntimeComponentDeployment {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuntimeComponentList) DeepCopyInto(out *RuntimeComponentList) {
+func (in *RuntimeComponentErrorRateAlert) DeepCopyInto(out *RuntimeComponentErrorRateAlert) {
+	*out = *in
+	in.RuntimeComponentAlert.DeepCopyInto(&out.RuntimeComponentAlert)
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(string)
+		**out = **in
+	}
+	if in.StatusCodeLabel != nil {
+		in, out := &in.StatusCodeLabel, &out.StatusCodeLabel
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentErrorRateAlert.
+func (in *RuntimeComponentErrorRateAlert) DeepCopy() *RuntimeComponentErrorRateAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentErrorRateAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentIssuerReference) DeepCopyInto(out *RuntimeComponentIssuerReference) {
+	*out = *in
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentIssuerReference.
+func (in *RuntimeComponentIssuerReference) DeepCopy() *RuntimeComponentIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentKeystores) DeepCopyInto(out *RuntimeComponentKeystores) {
+	*out = *in
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordSecretKey != nil {
+		in, out := &in.PasswordSecretKey, &out.PasswordSecretKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.Alias != nil {
+		in, out := &in.Alias, &out.Alias
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentKeystores.
+func (in *RuntimeComponentKeystores) DeepCopy() *RuntimeComponentKeystores {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentKeystores)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentList) DeepCopyInto(out *RuntimeComponentList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
@@ -284,86 +726,421 @@ func (in *RuntimeComponentMonitoring) DeepCopyInto(out *RuntimeComponentMonitori
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
+		*out = new(string)
+		**out = **in
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(RuntimeComponentMonitoringAlerts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dashboard != nil {
+		in, out := &in.Dashboard, &out.Dashboard
+		*out = new(RuntimeComponentMonitoringDashboard)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentMonitoring.
+func (in *RuntimeComponentMonitoring) DeepCopy() *RuntimeComponentMonitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentMonitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentMonitoringAlerts) DeepCopyInto(out *RuntimeComponentMonitoringAlerts) {
+	*out = *in
+	if in.ReplicasUnavailable != nil {
+		in, out := &in.ReplicasUnavailable, &out.ReplicasUnavailable
+		*out = new(RuntimeComponentAlert)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestartStorm != nil {
+		in, out := &in.RestartStorm, &out.RestartStorm
+		*out = new(RuntimeComponentAlert)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HighErrorRate != nil {
+		in, out := &in.HighErrorRate, &out.HighErrorRate
+		*out = new(RuntimeComponentErrorRateAlert)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = new(RuntimeComponentAlert)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]monitoringv1.Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentMonitoringAlerts.
+func (in *RuntimeComponentMonitoringAlerts) DeepCopy() *RuntimeComponentMonitoringAlerts {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentMonitoringAlerts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentMonitoringDashboard) DeepCopyInto(out *RuntimeComponentMonitoringDashboard) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstanceSelector != nil {
+		in, out := &in.InstanceSelector, &out.InstanceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentMonitoringDashboard.
+func (in *RuntimeComponentMonitoringDashboard) DeepCopy() *RuntimeComponentMonitoringDashboard {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentMonitoringDashboard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentNetworkPolicy) DeepCopyInto(out *RuntimeComponentNetworkPolicy) {
+	*out = *in
+	if in.Disable != nil {
+		in, out := &in.Disable, &out.Disable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = new(map[string]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]string, len(*in))
+			for key, val := range *in {
+				(*out)[key] = val
+			}
+		}
+	}
+	if in.FromLabels != nil {
+		in, out := &in.FromLabels, &out.FromLabels
+		*out = new(map[string]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]string, len(*in))
+			for key, val := range *in {
+				(*out)[key] = val
+			}
+		}
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]networkingv1.NetworkPolicyIngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(RuntimeComponentNetworkPolicyEgress)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentNetworkPolicy.
+func (in *RuntimeComponentNetworkPolicy) DeepCopy() *RuntimeComponentNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentNetworkPolicyEgress) DeepCopyInto(out *RuntimeComponentNetworkPolicyEgress) {
+	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]RuntimeComponentDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]networkingv1.NetworkPolicyEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentNetworkPolicyEgress.
+func (in *RuntimeComponentNetworkPolicyEgress) DeepCopy() *RuntimeComponentNetworkPolicyEgress {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentNetworkPolicyEgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentObservability) DeepCopyInto(out *RuntimeComponentObservability) {
+	*out = *in
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(RuntimeComponentTracing)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentObservability.
+func (in *RuntimeComponentObservability) DeepCopy() *RuntimeComponentObservability {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentObservability)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentOverride) DeepCopyInto(out *RuntimeComponentOverride) {
+	*out = *in
+	if in.PatchType != nil {
+		in, out := &in.PatchType, &out.PatchType
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentOverride.
+func (in *RuntimeComponentOverride) DeepCopy() *RuntimeComponentOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentProbes) DeepCopyInto(out *RuntimeComponentProbes) {
+	*out = *in
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProbes.
+func (in *RuntimeComponentProbes) DeepCopy() *RuntimeComponentProbes {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentProbes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentProfile) DeepCopyInto(out *RuntimeComponentProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProfile.
+func (in *RuntimeComponentProfile) DeepCopy() *RuntimeComponentProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuntimeComponentProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentProfileList) DeepCopyInto(out *RuntimeComponentProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RuntimeComponentProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProfileList.
+func (in *RuntimeComponentProfileList) DeepCopy() *RuntimeComponentProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuntimeComponentProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentProfileReference) DeepCopyInto(out *RuntimeComponentProfileReference) {
+	*out = *in
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentMonitoring.
-func (in *RuntimeComponentMonitoring) DeepCopy() *RuntimeComponentMonitoring {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProfileReference.
+func (in *RuntimeComponentProfileReference) DeepCopy() *RuntimeComponentProfileReference {
 	if in == nil {
 		return nil
 	}
-	out := new(RuntimeComponentMonitoring)
+	out := new(RuntimeComponentProfileReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuntimeComponentNetworkPolicy) DeepCopyInto(out *RuntimeComponentNetworkPolicy) {
+func (in *RuntimeComponentProfileSpec) DeepCopyInto(out *RuntimeComponentProfileSpec) {
 	*out = *in
-	if in.Disable != nil {
-		in, out := &in.Disable, &out.Disable
-		*out = new(bool)
-		**out = **in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(RuntimeComponentProbes)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.NamespaceLabels != nil {
-		in, out := &in.NamespaceLabels, &out.NamespaceLabels
-		*out = new(map[string]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make(map[string]string, len(*in))
-			for key, val := range *in {
-				(*out)[key] = val
-			}
-		}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.FromLabels != nil {
-		in, out := &in.FromLabels, &out.FromLabels
-		*out = new(map[string]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make(map[string]string, len(*in))
-			for key, val := range *in {
-				(*out)[key] = val
-			}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(RuntimeComponentAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(RuntimeComponentMonitoring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(RuntimeComponentServiceAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observability != nil {
+		in, out := &in.Observability, &out.Observability
+		*out = new(RuntimeComponentObservability)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentNetworkPolicy.
-func (in *RuntimeComponentNetworkPolicy) DeepCopy() *RuntimeComponentNetworkPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProfileSpec.
+func (in *RuntimeComponentProfileSpec) DeepCopy() *RuntimeComponentProfileSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RuntimeComponentNetworkPolicy)
+	out := new(RuntimeComponentProfileSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuntimeComponentProbes) DeepCopyInto(out *RuntimeComponentProbes) {
+func (in *RuntimeComponentProfileStatus) DeepCopyInto(out *RuntimeComponentProfileStatus) {
 	*out = *in
-	if in.Liveness != nil {
-		in, out := &in.Liveness, &out.Liveness
-		*out = new(corev1.Probe)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Readiness != nil {
-		in, out := &in.Readiness, &out.Readiness
-		*out = new(corev1.Probe)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProfileStatus.
+func (in *RuntimeComponentProfileStatus) DeepCopy() *RuntimeComponentProfileStatus {
+	if in == nil {
+		return nil
 	}
-	if in.Startup != nil {
-		in, out := &in.Startup, &out.Startup
-		*out = new(corev1.Probe)
-		(*in).DeepCopyInto(*out)
+	out := new(RuntimeComponentProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentPrometheusMetric) DeepCopyInto(out *RuntimeComponentPrometheusMetric) {
+	*out = *in
+	out.Target = in.Target.DeepCopy()
+	if in.TargetType != nil {
+		in, out := &in.TargetType, &out.TargetType
+		*out = new(string)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentProbes.
-func (in *RuntimeComponentProbes) DeepCopy() *RuntimeComponentProbes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentPrometheusMetric.
+func (in *RuntimeComponentPrometheusMetric) DeepCopy() *RuntimeComponentPrometheusMetric {
 	if in == nil {
 		return nil
 	}
-	out := new(RuntimeComponentProbes)
+	out := new(RuntimeComponentPrometheusMetric)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -393,6 +1170,11 @@ func (in *RuntimeComponentRoute) DeepCopyInto(out *RuntimeComponentRoute) {
 		*out = new(routev1.InsecureEdgeTerminationPolicyType)
 		**out = **in
 	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(RuntimeComponentRouteCertificate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentRoute.
@@ -405,6 +1187,32 @@ func (in *RuntimeComponentRoute) DeepCopy() *RuntimeComponentRoute {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentRouteCertificate) DeepCopyInto(out *RuntimeComponentRouteCertificate) {
+	*out = *in
+	in.IssuerRef.DeepCopyInto(&out.IssuerRef)
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentRouteCertificate.
+func (in *RuntimeComponentRouteCertificate) DeepCopy() *RuntimeComponentRouteCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentRouteCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeComponentService) DeepCopyInto(out *RuntimeComponentService) {
 	*out = *in
@@ -698,6 +1506,35 @@ func (in *RuntimeComponentSpec) DeepCopyInto(out *RuntimeComponentSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]RuntimeComponentOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraResources != nil {
+		in, out := &in.ExtraResources, &out.ExtraResources
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(RuntimeComponentProfileReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trust != nil {
+		in, out := &in.Trust, &out.Trust
+		*out = new(RuntimeComponentTrust)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Observability != nil {
+		in, out := &in.Observability, &out.Observability
+		*out = new(RuntimeComponentObservability)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentSpec.
@@ -775,6 +1612,26 @@ func (in *RuntimeComponentStatus) DeepCopyInto(out *RuntimeComponentStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ExtraResources != nil {
+		in, out := &in.ExtraResources, &out.ExtraResources
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(StatusProfile)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(StatusMigration)
+		**out = **in
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = new(StatusCertificateExpiry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentStatus.
@@ -838,6 +1695,128 @@ func (in *RuntimeComponentTopologySpreadConstraints) DeepCopy() *RuntimeComponen
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentTracing) DeepCopyInto(out *RuntimeComponentTracing) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.Sampler != nil {
+		in, out := &in.Sampler, &out.Sampler
+		*out = new(string)
+		**out = **in
+	}
+	if in.SamplerArgument != nil {
+		in, out := &in.SamplerArgument, &out.SamplerArgument
+		*out = new(string)
+		**out = **in
+	}
+	if in.Propagators != nil {
+		in, out := &in.Propagators, &out.Propagators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceAttributes != nil {
+		in, out := &in.ResourceAttributes, &out.ResourceAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Instrumentation != nil {
+		in, out := &in.Instrumentation, &out.Instrumentation
+		*out = new(RuntimeComponentTracingInstrumentation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Collector != nil {
+		in, out := &in.Collector, &out.Collector
+		*out = new(RuntimeComponentTracingCollector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentTracing.
+func (in *RuntimeComponentTracing) DeepCopy() *RuntimeComponentTracing {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentTracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentTracingCollector) DeepCopyInto(out *RuntimeComponentTracingCollector) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentTracingCollector.
+func (in *RuntimeComponentTracingCollector) DeepCopy() *RuntimeComponentTracingCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentTracingCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentTracingInstrumentation) DeepCopyInto(out *RuntimeComponentTracingInstrumentation) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentTracingInstrumentation.
+func (in *RuntimeComponentTracingInstrumentation) DeepCopy() *RuntimeComponentTracingInstrumentation {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentTracingInstrumentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeComponentTrust) DeepCopyInto(out *RuntimeComponentTrust) {
+	*out = *in
+	if in.InjectCABundle != nil {
+		in, out := &in.InjectCABundle, &out.InjectCABundle
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeComponentTrust.
+func (in *RuntimeComponentTrust) DeepCopy() *RuntimeComponentTrust {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeComponentTrust)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeOperation) DeepCopyInto(out *RuntimeOperation) {
 	*out = *in
@@ -940,6 +1919,26 @@ func (in *RuntimeOperationStatus) DeepCopy() *RuntimeOperationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusCertificateExpiry) DeepCopyInto(out *StatusCertificateExpiry) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	if in.LastWarningTime != nil {
+		in, out := &in.LastWarningTime, &out.LastWarningTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusCertificateExpiry.
+func (in *StatusCertificateExpiry) DeepCopy() *StatusCertificateExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusCertificateExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
 	*out = *in
@@ -974,6 +1973,36 @@ func (in *StatusEndpoint) DeepCopy() *StatusEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusMigration) DeepCopyInto(out *StatusMigration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusMigration.
+func (in *StatusMigration) DeepCopy() *StatusMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusProfile) DeepCopyInto(out *StatusProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusProfile.
+func (in *StatusProfile) DeepCopy() *StatusProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusVersions) DeepCopyInto(out *StatusVersions) {
 	*out = *in
@@ -432,7 +432,7 @@ const (
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Reconciled')].reason",priority=1,description="Reason for the failure of reconcile condition"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Reconciled')].message",priority=1,description="Failure message from reconcile condition"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",priority=0,description="Age of the resource"
-//+operator-sdk:csv:customresourcedefinitions:displayName="RuntimeComponent",resources={{Deployment,v1},{Service,v1},{StatefulSet,v1},{Route,v1},{HorizontalPodAutoscaler,v1},{ServiceAccount,v1},{Secret,v1}}
+//+operator-sdk:csv:customresourcedefinitions:order=2,displayName="RuntimeComponent",resources={{Deployment,v1},{Service,v1},{StatefulSet,v1},{Route,v1},{HorizontalPodAutoscaler,v1},{ServiceAccount,v1},{Secret,v1}}
 
 // Represents the deployment of a runtime component
 type RuntimeComponent struct {
@@ -702,6 +702,11 @@ func (a *RuntimeComponentAutoScaling) GetHorizontalPodAutoscalerBehavior() *auto
 	return nil
 }
 
+// GetPrometheus returns the metrics computed by Prometheus queries
+func (a *RuntimeComponentAutoScaling) GetPrometheus() []common.BaseComponentPrometheusMetric {
+	return nil
+}
+
 // GetSize returns persistent volume size
 func (s *RuntimeComponentStorage) GetSize() string {
 	return s.Size
@@ -795,6 +800,21 @@ func (m *RuntimeComponentMonitoring) GetEndpoints() []prometheusv1.Endpoint {
 	return m.Endpoints
 }
 
+// GetKind returns the kind of the monitoring resource
+func (m *RuntimeComponentMonitoring) GetKind() string {
+	return common.MonitoringKindServiceMonitor
+}
+
+// GetAlerts returns the alerts of the component
+func (m *RuntimeComponentMonitoring) GetAlerts() common.BaseComponentMonitoringAlerts {
+	return nil
+}
+
+// GetDashboard returns the Grafana dashboard of the component
+func (m *RuntimeComponentMonitoring) GetDashboard() common.BaseComponentMonitoringDashboard {
+	return nil
+}
+
 // GetAnnotations returns route annotations
 func (r *RuntimeComponentRoute) GetAnnotations() map[string]string {
 	return r.Annotations
@@ -830,6 +850,10 @@ func (r *RuntimeComponentRoute) GetPathType() networkingv1.PathType {
 	return r.PathType
 }
 
+func (r *RuntimeComponentRoute) GetCertificate() common.BaseComponentRouteCertificate {
+	return nil
+}
+
 // GetNodeAffinity returns node affinity
 func (a *RuntimeComponentAffinity) GetNodeAffinity() *corev1.NodeAffinity {
 	return a.NodeAffinity
@@ -952,6 +976,42 @@ func (s *RuntimeComponentStatus) GetLatestTransitionTime() *metav1.Time {
 	return nil
 }
 
+func (s *RuntimeComponentStatus) GetExtraResources() []corev1.ObjectReference {
+	return nil
+}
+
+func (s *RuntimeComponentStatus) SetExtraResources(refs []corev1.ObjectReference) {
+	return
+}
+
+func (s *RuntimeComponentStatus) GetMigration() common.StatusMigration {
+	return nil
+}
+
+func (s *RuntimeComponentStatus) SetMigration(from string, to string, phase string) {
+	return
+}
+
+func (s *RuntimeComponentStatus) UnsetMigration() {
+	return
+}
+
+func (s *RuntimeComponentStatus) GetCertificateExpiry() common.StatusCertificateExpiry {
+	return nil
+}
+
+func (s *RuntimeComponentStatus) SetCertificateExpiry(secretName string, notAfter metav1.Time) {
+	return
+}
+
+func (s *RuntimeComponentStatus) UnsetCertificateExpiry() {
+	return
+}
+
+func (s *RuntimeComponentStatus) SetCertificateExpiryWarningTime(t metav1.Time) {
+	return
+}
+
 // GetMessage return condition's message
 func (c *StatusCondition) GetMessage() string {
 	return c.Message
@@ -45,7 +45,7 @@ type RuntimeOperationStatus struct {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-//+operator-sdk:csv:customresourcedefinitions:displayName="RuntimeOperation"
+//+operator-sdk:csv:customresourcedefinitions:order=4,displayName="RuntimeOperation"
 
 // Day-2 operation to execute on an instance of runtime component
 type RuntimeOperation struct {
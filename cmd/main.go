@@ -209,6 +209,13 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "RuntimeComponent")
 		os.Exit(1)
 	}
+	if err = (&controller.RuntimeApplicationReconciler{
+		ReconcilerBase: utils.NewReconcilerBase(mgr.GetAPIReader(), mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor("runtime-component-operator")),
+		Log:            ctrl.Log.WithName("controller").WithName("RuntimeApplication"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RuntimeApplication")
+		os.Exit(1)
+	}
 	if err = (&controller.RuntimeOperationReconciler{
 		Client:     mgr.GetClient(),
 		Log:        ctrl.Log.WithName("controller").WithName("RuntimeOperation"),
@@ -0,0 +1,215 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command render prints the manifests that the operator creates for a RuntimeComponent
+// without contacting a cluster.
+//
+//	render -f my-app.yaml [-objects existing.yaml] [-config operator-configmap.yaml] [-profile profile.yaml] [-platform openshift] [-cert-manager] [-knative]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	"github.com/application-stacks/runtime-component-operator/utils"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	platformKubernetes = "kubernetes"
+	platformOpenShift  = "openshift"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(appstacksv1.AddToScheme(scheme))
+	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(prometheusv1.AddToScheme(scheme))
+	utilruntime.Must(servingv1.AddToScheme(scheme))
+	utilruntime.Must(certmanagerv1.AddToScheme(scheme))
+}
+
+func main() {
+	var file, objectsFile, configFile, profileFile, platform, namespace string
+	var certManager, knative, serviceMonitor, grafanaOperator, openTelemetryOperator bool
+
+	flag.StringVar(&file, "f", "-", "Path of the RuntimeComponent YAML to render. Use - to read from standard input.")
+	flag.StringVar(&objectsFile, "objects", "", "Optional path of a YAML with the objects that exist on the cluster, such as the Secrets and ServiceAccount referenced by the RuntimeComponent.")
+	flag.StringVar(&configFile, "config", "", "Optional path of the operator ConfigMap YAML. Defaults are used for missing keys.")
+	flag.StringVar(&profileFile, "profile", "", "Path of the RuntimeComponentProfile or ClusterRuntimeComponentProfile YAML referenced by .spec.profile.")
+	flag.StringVar(&platform, "platform", platformKubernetes, "Target platform, either "+platformKubernetes+" or "+platformOpenShift+".")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace to use when the RuntimeComponent does not specify one.")
+	flag.BoolVar(&certManager, "cert-manager", false, "Render for a cluster with cert-manager installed.")
+	flag.BoolVar(&knative, "knative", false, "Render for a cluster with Knative Serving installed.")
+	flag.BoolVar(&serviceMonitor, "service-monitor", true, "Render for a cluster with the Prometheus Operator ServiceMonitor and PodMonitor CRDs installed.")
+	flag.BoolVar(&grafanaOperator, "grafana-operator", false, "Render dashboards as GrafanaDashboard resources of the Grafana Operator instead of ConfigMaps.")
+	flag.BoolVar(&openTelemetryOperator, "opentelemetry-operator", false, "Render for a cluster with the OpenTelemetry Operator installed.")
+	flag.Parse()
+
+	if platform != platformKubernetes && platform != platformOpenShift {
+		exitOnError(fmt.Errorf("unknown platform %q, must be %s or %s", platform, platformKubernetes, platformOpenShift))
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if configFile != "" {
+		exitOnError(readYAML(configFile, configMap))
+	}
+	common.LoadFromConfigMap(common.Config, configMap)
+
+	instance := &appstacksv1.RuntimeComponent{}
+	exitOnError(readYAML(file, instance))
+	if instance.Kind != "" && instance.Kind != "RuntimeComponent" {
+		exitOnError(fmt.Errorf("expected a RuntimeComponent but found %s", instance.Kind))
+	}
+	if instance.Namespace == "" {
+		instance.Namespace = namespace
+	}
+
+	if instance.Spec.Profile != nil {
+		if profileFile == "" {
+			exitOnError(fmt.Errorf("the RuntimeComponent references %s %s, use -profile to provide it", instance.Spec.Profile.GetKind(), instance.Spec.Profile.Name))
+		}
+		// Both kinds of profile have the same spec
+		profile := &appstacksv1.RuntimeComponentProfile{}
+		exitOnError(readYAML(profileFile, profile))
+		exitOnError(utils.MergeProfile(&instance.Spec, &profile.Spec))
+	}
+
+	instance.Initialize()
+	if _, err := utils.Validate(instance); err != nil {
+		exitOnError(err)
+	}
+	if platform == platformOpenShift {
+		instance.Annotations = utils.MergeMaps(instance.Annotations, utils.GetOpenShiftAnnotations(instance))
+	}
+
+	var objects []client.Object
+	if objectsFile != "" {
+		var err error
+		objects, err = readObjects(objectsFile, instance.Namespace)
+		exitOnError(err)
+	}
+
+	resources, err := utils.RenderResources(instance, utils.RenderOptions{
+		OpenShift:             platform == platformOpenShift,
+		CertManager:           certManager,
+		Knative:               knative,
+		ServiceMonitor:        serviceMonitor,
+		GrafanaOperator:       grafanaOperator,
+		OpenTelemetryOperator: openTelemetryOperator,
+		Prefix:                "rco",
+		CACommonName:          "Runtime Component Operator",
+		OperatorName:          "runtime-component-operator",
+		Scheme:                scheme,
+		Objects:               objects,
+	})
+	exitOnError(err)
+
+	for _, obj := range resources {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		exitOnError(err)
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		out, err := yaml.Marshal(obj)
+		exitOnError(err)
+		fmt.Fprintf(os.Stdout, "---\n%s", out)
+	}
+}
+
+func readYAML(path string, obj interface{}) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, obj)
+}
+
+// readObjects reads the objects of the YAML documents in path. Objects of unknown kinds are read as unstructured objects
+func readObjects(path string, namespace string) ([]client.Object, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	objects := []client.Object{}
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		var obj client.Object
+		if decoded, _, err := decoder.Decode(doc, nil, nil); err == nil {
+			var ok bool
+			if obj, ok = decoded.(client.Object); !ok {
+				return nil, fmt.Errorf("%T is not an object", decoded)
+			}
+		} else if runtime.IsNotRegisteredError(err) {
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(doc, &u.Object); err != nil {
+				return nil, err
+			}
+			obj = u
+		} else {
+			return nil, err
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		objects = append(objects, obj)
+	}
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
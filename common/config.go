@@ -59,6 +59,45 @@ const (
 
 	// OpConfigShowReconcileInterval default whether reconcile interval will be visible in the instance's status field
 	OpConfigShowReconcileInterval = "showReconcileInterval"
+
+	// OpConfigPauseReconciliation whether reconciliation of all instances is paused. Child resources are left untouched while paused
+	OpConfigPauseReconciliation = "pauseReconciliation"
+
+	// OpConfigExtraResourcesAllowedKinds comma separated list of the kinds, in Kind.group form, that can be created from .spec.extraResources
+	OpConfigExtraResourcesAllowedKinds = "extraResourcesAllowedKinds"
+
+	// OpConfigDefaultHostnameTemplate a Go template for the hostname generated when an exposed instance does not set one
+	OpConfigDefaultHostnameTemplate = "defaultHostnameTemplate"
+
+	// OpConfigNamespaceDomains comma separated list of namespace=domain pairs, used instead of defaultHostname for the namespace
+	OpConfigNamespaceDomains = "namespaceDomains"
+
+	// OpConfigCSRSignerName the signer of the CertificateSigningRequests of service certificates when cert-manager and the OpenShift service CA are not available
+	OpConfigCSRSignerName = "csrSignerName"
+
+	// OpConfigCSRSignerCABundle the PEM CA certificates of the signer of csrSignerName, set in the ca.crt key of the secrets of the service certificates
+	OpConfigCSRSignerCABundle = "csrSignerCABundle"
+
+	// OpConfigCertificateExpiryWarningThreshold the duration before the expiry of a certificate of an instance when a Warning condition is reported. 0 disables the warning
+	OpConfigCertificateExpiryWarningThreshold = "certificateExpiryWarningThreshold"
+
+	// OpConfigCMCACleanupGracePeriod how long the cert-manager resources of the operator CA are kept in a namespace after the last instance stops using them
+	OpConfigCMCACleanupGracePeriod = "certManagerCACleanupGracePeriod"
+
+	// OpConfigMonitoringNamespaceLabels comma separated list of key=value labels of the namespaces that the NetworkPolicy of an instance allows monitoring traffic from on Kubernetes
+	OpConfigMonitoringNamespaceLabels = "monitoringNamespaceLabels"
+
+	// OpConfigGrafanaDashboardLabels comma separated list of key=value labels of the ConfigMaps of Grafana dashboards, which the Grafana dashboard sidecar loads
+	OpConfigGrafanaDashboardLabels = "grafanaDashboardLabels"
+
+	// OpConfigPrometheusAdapterConfigMapName name of the ConfigMap of the configuration of the Prometheus Adapter, which the external rules of the instances are added to
+	OpConfigPrometheusAdapterConfigMapName = "prometheusAdapterConfigMapName"
+
+	// OpConfigPrometheusAdapterConfigMapNamespace namespace of the ConfigMap of the configuration of the Prometheus Adapter
+	OpConfigPrometheusAdapterConfigMapNamespace = "prometheusAdapterConfigMapNamespace"
+
+	// OpConfigOpenTelemetryCollectorImage image of the OpenTelemetry Collector sidecar of the instances that do not set one
+	OpConfigOpenTelemetryCollectorImage = "openTelemetryCollectorImage"
 )
 
 // Config stores operator configuration
@@ -197,6 +236,19 @@ func DefaultOpConfig() *sync.Map {
 	cfg.Store(OpConfigReconcileIntervalFailureMaximum, "240")
 	cfg.Store(OpConfigReconcileIntervalSuccessMaximum, "120")
 	cfg.Store(OpConfigShowReconcileInterval, "false")
+	cfg.Store(OpConfigPauseReconciliation, "false")
+	cfg.Store(OpConfigExtraResourcesAllowedKinds, "ConfigMap,Secret,Certificate.cert-manager.io,PrometheusRule.monitoring.coreos.com")
+	cfg.Store(OpConfigDefaultHostnameTemplate, "{{ .Name }}-{{ .Namespace }}.{{ .Domain }}")
+	cfg.Store(OpConfigNamespaceDomains, "")
+	cfg.Store(OpConfigCSRSignerName, "")
+	cfg.Store(OpConfigCSRSignerCABundle, "")
+	cfg.Store(OpConfigCertificateExpiryWarningThreshold, "720h")
+	cfg.Store(OpConfigCMCACleanupGracePeriod, "24h")
+	cfg.Store(OpConfigMonitoringNamespaceLabels, "kubernetes.io/metadata.name=monitoring")
+	cfg.Store(OpConfigGrafanaDashboardLabels, "grafana_dashboard=1")
+	cfg.Store(OpConfigPrometheusAdapterConfigMapName, "adapter-config")
+	cfg.Store(OpConfigPrometheusAdapterConfigMapNamespace, "monitoring")
+	cfg.Store(OpConfigOpenTelemetryCollectorImage, "otel/opentelemetry-collector:0.111.0")
 	return cfg
 }
 
@@ -8,7 +8,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // StatusConditionType ...
@@ -20,10 +22,15 @@ type StatusEndpointScope string
 type StatusReferences map[string]string
 
 const (
-	StatusReferenceCertSecretName    = "svcCertSecretName"
-	StatusReferencePullSecretName    = "saPullSecretName"
-	StatusReferenceSAResourceVersion = "saResourceVersion"
-	StatusReferenceRouteHost         = "routeHost"
+	StatusReferenceCertSecretName       = "svcCertSecretName"
+	StatusReferencePullSecretName       = "saPullSecretName"
+	StatusReferenceSAResourceVersion    = "saResourceVersion"
+	StatusReferenceRouteHost            = "routeHost"
+	StatusReferenceHost                 = "host"
+	StatusReferenceCABundleName         = "caBundleName"
+	StatusReferenceClientCertSecretName = "clientCertSecretName"
+	StatusReferencePrometheusAdapter    = "prometheusAdapterConfigMap"
+	StatusReferenceOperatorCAIssuer     = "operatorCAIssuer"
 )
 
 // StatusCondition ...
@@ -91,6 +98,32 @@ type BaseComponentStatus interface {
 	UnsetReconcileInterval()
 
 	GetLatestTransitionTime() *metav1.Time
+
+	GetExtraResources() []corev1.ObjectReference
+	SetExtraResources([]corev1.ObjectReference)
+
+	GetMigration() StatusMigration
+	SetMigration(string, string, string)
+	UnsetMigration()
+
+	GetCertificateExpiry() StatusCertificateExpiry
+	SetCertificateExpiry(string, metav1.Time)
+	SetCertificateExpiryWarningTime(metav1.Time)
+	UnsetCertificateExpiry()
+}
+
+// StatusCertificateExpiry reports the certificate of an instance that expires first
+type StatusCertificateExpiry interface {
+	GetSecretName() string
+	GetNotAfter() metav1.Time
+	GetLastWarningTime() *metav1.Time
+}
+
+// StatusMigration reports the switch of the workload of an instance to another kind
+type StatusMigration interface {
+	GetFrom() string
+	GetTo() string
+	GetPhase() string
 }
 
 const (
@@ -99,10 +132,24 @@ const (
 	StatusConditionTypeResourcesReady StatusConditionType = "ResourcesReady"
 	StatusConditionTypeReady          StatusConditionType = "Ready"
 	StatusConditionTypeWarning        StatusConditionType = "Warning"
+	StatusConditionTypePaused         StatusConditionType = "Paused"
 
 	// Status Condition Type Messages
 	StatusConditionTypeReadyMessage string = "Application is reconciled and resources are ready."
 
+	// Workload kinds
+	WorkloadKindDeployment     string = "Deployment"
+	WorkloadKindStatefulSet    string = "StatefulSet"
+	WorkloadKindKnativeService string = "KnativeService"
+
+	// Workload migration phases
+	MigrationPhaseWaitingForWorkload string = "WaitingForWorkload"
+	MigrationPhaseSwitchingTraffic   string = "SwitchingTraffic"
+
+	// Keystore formats
+	KeystoreFormatPKCS12 string = "PKCS12"
+	KeystoreFormatJKS    string = "JKS"
+
 	// Status Endpoint Scopes
 	StatusEndpointScopeExternal StatusEndpointScope = "External"
 	StatusEndpointScopeInternal StatusEndpointScope = "Internal"
@@ -116,6 +163,15 @@ type BaseComponentAutoscaling interface {
 	GetTargetMemoryUtilizationPercentage() *int32
 	GetMetrics() []autoscalingv2.MetricSpec
 	GetHorizontalPodAutoscalerBehavior() *autoscalingv2.HorizontalPodAutoscalerBehavior
+	GetPrometheus() []BaseComponentPrometheusMetric
+}
+
+// BaseComponentPrometheusMetric represents a metric of the autoscaler computed by a Prometheus query
+type BaseComponentPrometheusMetric interface {
+	GetName() string
+	GetQuery() string
+	GetTarget() resource.Quantity
+	GetTargetType() autoscalingv2.MetricTargetType
 }
 
 // BaseComponentStorage represents basic PVC configuration
@@ -148,18 +204,96 @@ type BaseComponentServiceSessionAffinity interface {
 
 type BaseComponentCertificate interface {
 	GetAnnotations() map[string]string
+	GetIssuerRef() BaseComponentIssuerReference
+	GetDuration() *metav1.Duration
+	GetRenewBefore() *metav1.Duration
+	GetPrivateKey() BaseComponentCertificatePrivateKey
+	GetDNSNames() []string
+	GetIPAddresses() []string
+	GetUsages() []string
+	GetKeystores() BaseComponentKeystores
+	GetClientAuth() BaseComponentClientAuth
+}
+
+// BaseComponentClientAuth represents mutual TLS with client certificates
+type BaseComponentClientAuth interface {
+	GetCertificate() *bool
+	GetRequired() *bool
+}
+
+// BaseComponentKeystores represents the keystore and truststore generated from the service certificate
+type BaseComponentKeystores interface {
+	GetFormat() string
+	GetPasswordSecretRef() string
+	GetPasswordSecretKey() string
+	GetAlias() *string
+}
+
+// BaseComponentCertificatePrivateKey represents the private key of the service certificate
+type BaseComponentCertificatePrivateKey interface {
+	GetAlgorithm() *string
+	GetSize() *int
+	GetRotationPolicy() *string
 }
 
 // BaseComponentNetworkPolicy represents a basic network policy configuration
 type BaseComponentNetworkPolicy interface {
 	GetNamespaceLabels() map[string]string
 	GetFromLabels() map[string]string
+	IsDisabled() bool
+	GetIngress() []networkingv1.NetworkPolicyIngressRule
+	GetEgress() BaseComponentNetworkPolicyEgress
+}
+
+// BaseComponentNetworkPolicyEgress represents the outgoing traffic allowed by the network policy
+type BaseComponentNetworkPolicyEgress interface {
+	GetDependencies() []BaseComponentDependency
+	GetRules() []networkingv1.NetworkPolicyEgressRule
+}
+
+// BaseComponentDependency represents a component that the pods send traffic to
+type BaseComponentDependency interface {
+	GetName() string
+	GetNamespace() string
 }
 
 // BaseComponentMonitoring represents basic service monitoring configuration
 type BaseComponentMonitoring interface {
 	GetLabels() map[string]string
 	GetEndpoints() []prometheusv1.Endpoint
+	GetKind() string
+	GetAlerts() BaseComponentMonitoringAlerts
+	GetDashboard() BaseComponentMonitoringDashboard
+}
+
+// BaseComponentMonitoringDashboard represents the Grafana dashboard of the component
+type BaseComponentMonitoringDashboard interface {
+	GetTemplates() []string
+	GetInstanceSelector() *metav1.LabelSelector
+}
+
+// BaseComponentMonitoringAlerts represents the alerts of the component
+type BaseComponentMonitoringAlerts interface {
+	GetReplicasUnavailable() BaseComponentAlert
+	GetRestartStorm() BaseComponentAlert
+	GetHighErrorRate() BaseComponentErrorRateAlert
+	GetCertificateExpiry() BaseComponentAlert
+	GetRules() []prometheusv1.Rule
+}
+
+// BaseComponentAlert represents a built-in alert
+type BaseComponentAlert interface {
+	IsEnabled() bool
+	GetThreshold() *int32
+	GetFor() *prometheusv1.Duration
+	GetSeverity() string
+}
+
+// BaseComponentErrorRateAlert represents the alert on the rate of HTTP 5xx responses
+type BaseComponentErrorRateAlert interface {
+	BaseComponentAlert
+	GetMetric() string
+	GetStatusCodeLabel() string
 }
 
 // BaseComponentRoute represents route configuration
@@ -171,6 +305,20 @@ type BaseComponentRoute interface {
 	GetPath() string
 	GetPathType() networkingv1.PathType
 	GetCertificateSecretRef() *string
+	GetCertificate() BaseComponentRouteCertificate
+}
+
+// BaseComponentRouteCertificate represents the cert-manager certificate requested for the Route or Ingress host
+type BaseComponentRouteCertificate interface {
+	GetIssuerRef() BaseComponentIssuerReference
+	GetDuration() *metav1.Duration
+	GetDNSNames() []string
+}
+
+// BaseComponentIssuerReference references a cert-manager Issuer or ClusterIssuer
+type BaseComponentIssuerReference interface {
+	GetName() string
+	GetKind() string
 }
 
 // BaseComponentAffinity describes deployment and pod affinity
@@ -222,6 +370,44 @@ type BaseComponentDNS interface {
 	GetConfig() *corev1.PodDNSConfig
 }
 
+// BaseComponentOverride represents a patch applied to a generated resource
+type BaseComponentOverride interface {
+	GetKind() string
+	GetPatchType() string
+	GetPatch() string
+}
+
+const (
+	// Override patch types
+	OverridePatchTypeStrategic = "strategic"
+	OverridePatchTypeMerge     = "merge"
+	OverridePatchTypeJSON      = "json"
+)
+
+const (
+	// Kinds of the monitoring resource
+	MonitoringKindServiceMonitor = "ServiceMonitor"
+	MonitoringKindPodMonitor     = "PodMonitor"
+)
+
+const (
+	// Defaults of the alert on the rate of HTTP 5xx responses
+	DefaultErrorRateMetric          = "http_server_request_duration_seconds_count"
+	DefaultErrorRateStatusCodeLabel = "http_response_status_code"
+)
+
+const (
+	// Templates of the panels of the Grafana dashboard
+	DashboardTemplateHTTP         = "HTTP"
+	DashboardTemplateJVM          = "JVM"
+	DashboardTemplateMicroProfile = "MicroProfile"
+)
+
+// Defaults of the tracing, which are the same for every component so that the trace context is propagated across them
+const DefaultTracingSampler = "parentbased_always_on"
+
+var DefaultTracingPropagators = []string{"tracecontext", "baggage"}
+
 // BaseComponent represents basic kubernetes application
 type BaseComponent interface {
 	GetApplicationImage() string
@@ -265,4 +451,42 @@ type BaseComponent interface {
 	GetDisableTopologyRouting() *bool
 	GetHostAliases() []corev1.HostAlias
 	GetPriorityClassName() *string
+	GetOverrides() []BaseComponentOverride
+	GetExtraResources() []runtime.RawExtension
+	GetTrust() BaseComponentTrust
+	GetObservability() BaseComponentObservability
+}
+
+// BaseComponentTrust represents the certificate authorities trusted by the application
+type BaseComponentTrust interface {
+	GetInjectCABundle() *bool
+}
+
+// BaseComponentObservability represents the observability of the application
+type BaseComponentObservability interface {
+	GetTracing() BaseComponentTracing
+}
+
+// BaseComponentTracing represents the OpenTelemetry tracing of the application
+type BaseComponentTracing interface {
+	GetEndpoint() *string
+	GetProtocol() *string
+	GetSampler() string
+	GetSamplerArgument() *string
+	GetPropagators() []string
+	GetResourceAttributes() map[string]string
+	GetInstrumentation() BaseComponentTracingInstrumentation
+	GetCollector() BaseComponentTracingCollector
+}
+
+// BaseComponentTracingInstrumentation represents the automatic instrumentation by the OpenTelemetry Operator
+type BaseComponentTracingInstrumentation interface {
+	GetLanguage() string
+	GetName() string
+}
+
+// BaseComponentTracingCollector represents an OpenTelemetry Collector sidecar
+type BaseComponentTracingCollector interface {
+	GetImage() string
+	GetResources() *corev1.ResourceRequirements
 }
@@ -20,6 +20,8 @@ var _ handler.EventHandler = &EnqueueRequestsForCustomIndexField{}
 
 const (
 	indexFieldImageStreamName = "spec.applicationImage"
+	indexFieldProfile         = "spec.profile"
+	indexFieldHost            = "status.references.host"
 )
 
 // EnqueueRequestsForCustomIndexField enqueues reconcile Requests Runtime Components if the app is relying on
@@ -101,3 +103,37 @@ func (i *ImageStreamMatcher) Match(imageStreamTag metav1.Object) ([]appstacksv1.
 
 	return apps, nil
 }
+
+// ProfileMatcher implements CustomMatcher for RuntimeComponentProfiles and ClusterRuntimeComponentProfiles
+type ProfileMatcher struct {
+	Klient          client.Client
+	WatchNamespaces []string
+	Kind            string
+}
+
+// Match returns all applications referencing the input profile
+func (p *ProfileMatcher) Match(profile metav1.Object) ([]appstacksv1.RuntimeComponent, error) {
+	apps := []appstacksv1.RuntimeComponent{}
+	var namespaces []string
+	if profile.GetNamespace() != "" {
+		namespaces = []string{profile.GetNamespace()}
+	} else if appstacksutils.IsClusterWide(p.WatchNamespaces) {
+		// Use all namespaces, as a cluster-scoped profile can be referenced from any of them
+		namespaces = []string{""}
+	} else {
+		namespaces = p.WatchNamespaces
+	}
+	for _, ns := range namespaces {
+		appList := &appstacksv1.RuntimeComponentList{}
+		err := p.Klient.List(context.Background(),
+			appList,
+			client.InNamespace(ns),
+			client.MatchingFields{indexFieldProfile: p.Kind + "/" + profile.GetName()})
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, appList.Items...)
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,219 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	appstacksutils "github.com/application-stacks/runtime-component-operator/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// Finalizer that removes the pause of the components, or deletes them, when the application is deleted
+	runtimeApplicationFinalizer = "rc.app.stacks/runtimeapplication"
+)
+
+// RuntimeApplicationReconciler reconciles a RuntimeApplication object
+type RuntimeApplicationReconciler struct {
+	appstacksutils.ReconcilerBase
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=rc.app.stacks,resources=runtimeapplications;runtimeapplications/status;runtimeapplications/finalizers,verbs=get;list;watch;create;update;patch;delete,namespace=runtime-component-operator
+
+// Reconcile aggregates the status of the components of a RuntimeApplication and applies the
+// application-wide operations to them
+func (r *RuntimeApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	reqLogger.Info("Reconciling RuntimeApplication")
+
+	app := &appstacksv1.RuntimeApplication{}
+	err := r.GetClient().Get(ctx, req.NamespacedName, app)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	components, others, err := r.getComponents(ctx, app)
+	if err != nil {
+		return r.manageError(app, err)
+	}
+
+	if !app.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(app, runtimeApplicationFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		if app.GetDeletionPolicy() == appstacksv1.RuntimeApplicationDeletionPolicyDelete {
+			for i := range components {
+				reqLogger.Info("Deleting component of the application", "component", components[i].Name)
+				if err := r.GetClient().Delete(ctx, &components[i]); err != nil && !kerrors.IsNotFound(err) {
+					return r.manageError(app, err)
+				}
+			}
+		} else if err := r.ResumeApplicationComponents(ctx, app.Name, baseComponents(append(components, others...))); err != nil {
+			return r.manageError(app, err)
+		}
+		controllerutil.RemoveFinalizer(app, runtimeApplicationFinalizer)
+		return reconcile.Result{}, r.GetClient().Update(ctx, app)
+	}
+
+	if !controllerutil.ContainsFinalizer(app, runtimeApplicationFinalizer) {
+		controllerutil.AddFinalizer(app, runtimeApplicationFinalizer)
+		if err := r.GetClient().Update(ctx, app); err != nil {
+			return r.manageError(app, err)
+		}
+	}
+
+	// Components that left the application are no longer paused by it
+	if err := r.ResumeApplicationComponents(ctx, app.Name, baseComponents(others)); err != nil {
+		return r.manageError(app, err)
+	}
+	if app.IsPaused() {
+		err = r.PauseApplicationComponents(ctx, app.Name, baseComponents(components))
+	} else {
+		err = r.ResumeApplicationComponents(ctx, app.Name, baseComponents(components))
+	}
+	if err != nil {
+		return r.manageError(app, err)
+	}
+
+	if app.Spec.RestartedAt != nil && *app.Spec.RestartedAt != app.Status.RestartedAt {
+		if err := r.RestartApplicationComponents(ctx, baseComponents(components), *app.Spec.RestartedAt); err != nil {
+			return r.manageError(app, err)
+		}
+		app.Status.RestartedAt = *app.Spec.RestartedAt
+	}
+
+	setRuntimeApplicationStatus(app, components)
+	app.Status.SetCondition(appstacksv1.StatusCondition{Type: appstacksv1.StatusConditionType(common.StatusConditionTypeReconciled), Status: corev1.ConditionTrue})
+	app.Status.ObservedGeneration = app.Generation
+	if err := r.UpdateStatus(app); err != nil {
+		reqLogger.Error(err, "Unable to update status")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// getComponents returns the components of the application, ordered by name, and the other components of the namespace
+func (r *RuntimeApplicationReconciler) getComponents(ctx context.Context, app *appstacksv1.RuntimeApplication) ([]appstacksv1.RuntimeComponent, []appstacksv1.RuntimeComponent, error) {
+	list := &appstacksv1.RuntimeComponentList{}
+	if err := r.GetClient().List(ctx, list, client.InNamespace(app.Namespace)); err != nil {
+		return nil, nil, err
+	}
+	components, others := []appstacksv1.RuntimeComponent{}, []appstacksv1.RuntimeComponent{}
+	for _, component := range list.Items {
+		if component.GetApplicationNameOrDefault() == app.GetApplicationName() {
+			components = append(components, component)
+		} else {
+			others = append(others, component)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+	return components, others, nil
+}
+
+// baseComponents returns the components as instances, which point to the items of the slice
+func baseComponents(components []appstacksv1.RuntimeComponent) []common.BaseComponent {
+	instances := make([]common.BaseComponent, len(components))
+	for i := range components {
+		instances[i] = &components[i]
+	}
+	return instances
+}
+
+// setRuntimeApplicationStatus aggregates the readiness, versions and endpoints of the components
+func setRuntimeApplicationStatus(app *appstacksv1.RuntimeApplication, components []appstacksv1.RuntimeComponent) {
+	app.Status.Components = nil
+	for i := range components {
+		component := &components[i]
+		app.Status.Components = append(app.Status.Components, appstacksv1.RuntimeApplicationComponentStatus{
+			Name:      component.Name,
+			Version:   component.Spec.ApplicationVersion,
+			Ready:     appstacksutils.GetComponentReadiness(component),
+			Paused:    appstacksutils.IsComponentPaused(component),
+			Endpoints: component.Status.Endpoints,
+		})
+	}
+	readiness := appstacksutils.GetApplicationReadiness(app.GetApplicationName(), baseComponents(components))
+	app.Status.ReadyComponents = readiness.ReadyComponents
+	app.Status.SetCondition(appstacksv1.StatusCondition{
+		Type:    appstacksv1.StatusConditionType(common.StatusConditionTypeReady),
+		Status:  readiness.Status,
+		Reason:  readiness.Reason,
+		Message: readiness.Message,
+	})
+}
+
+func (r *RuntimeApplicationReconciler) manageError(app *appstacksv1.RuntimeApplication, issue error) (reconcile.Result, error) {
+	r.Log.Error(issue, "Failed to reconcile RuntimeApplication", "Namespace", app.Namespace, "Name", app.Name)
+	app.Status.SetCondition(appstacksv1.StatusCondition{
+		Type:    appstacksv1.StatusConditionType(common.StatusConditionTypeReconciled),
+		Status:  corev1.ConditionFalse,
+		Reason:  string(kerrors.ReasonForError(issue)),
+		Message: issue.Error(),
+	})
+	if err := r.UpdateStatus(app); err != nil {
+		r.Log.Error(err, "Unable to update status")
+	}
+	return reconcile.Result{}, issue
+}
+
+// SetupWithManager initializes reconciler
+func (r *RuntimeApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appstacksv1.RuntimeApplication{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&appstacksv1.RuntimeComponent{}, handler.EnqueueRequestsFromMapFunc(r.applicationsForComponent)).
+		Complete(r)
+}
+
+// applicationsForComponent returns the applications that the component is part of or that paused it
+func (r *RuntimeApplicationReconciler) applicationsForComponent(ctx context.Context, obj client.Object) []reconcile.Request {
+	component, ok := obj.(*appstacksv1.RuntimeComponent)
+	if !ok {
+		return nil
+	}
+	apps := &appstacksv1.RuntimeApplicationList{}
+	if err := r.GetClient().List(ctx, apps, client.InNamespace(component.Namespace)); err != nil {
+		r.Log.Error(err, "Failed to list RuntimeApplications")
+		return nil
+	}
+	pausedBy := component.Annotations[appstacksutils.GetPausedByApplicationAnnotationName(component)]
+	requests := []reconcile.Request{}
+	for _, app := range apps.Items {
+		if app.GetApplicationName() == component.GetApplicationNameOrDefault() || app.Name == pausedBy {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&app)})
+		}
+	}
+	return requests
+}
@@ -23,7 +23,6 @@ import (
 	"strings"
 
 	"github.com/application-stacks/runtime-component-operator/common"
-	"github.com/pkg/errors"
 
 	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -39,6 +38,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/library-go/pkg/image/imageutil"
@@ -48,10 +48,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
@@ -61,12 +62,15 @@ const (
 // RuntimeComponentReconciler reconciles a RuntimeComponent object
 type RuntimeComponentReconciler struct {
 	appstacksutils.ReconcilerBase
-	Log             logr.Logger
-	watchNamespaces []string
+	Log               logr.Logger
+	watchNamespaces   []string
+	operatorCACleanup *appstacksutils.OperatorCACleanup
 }
 
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=restricted,verbs=use,namespace=runtime-component-operator
 // +kubebuilder:rbac:groups=rc.app.stacks,resources=runtimecomponents;runtimecomponents/status;runtimecomponents/finalizers,verbs=get;list;watch;create;update;patch;delete,namespace=runtime-component-operator
+// +kubebuilder:rbac:groups=rc.app.stacks,resources=runtimecomponentprofiles,verbs=get;list;watch,namespace=runtime-component-operator
+// +kubebuilder:rbac:groups=rc.app.stacks,resources=clusterruntimecomponentprofiles,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
 // +kubebuilder:rbac:groups=apps,resources=deployments/finalizers;statefulsets,verbs=update,namespace=runtime-component-operator
 // +kubebuilder:rbac:groups=core,resources=services;secrets;serviceaccounts;configmaps,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
@@ -75,8 +79,10 @@ type RuntimeComponentReconciler struct {
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
 // +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams;imagestreamtags,verbs=get;list;watch,namespace=runtime-component-operator
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
-// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors;prometheusrules,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
+// +kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadashboards,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
 // +kubebuilder:rbac:groups=cert-manager.io,resources=certificates;issuers,verbs=get;list;watch;create;update;delete,namespace=runtime-component-operator
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;create;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -111,19 +117,33 @@ func (r *RuntimeComponentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	// Fetch the RuntimeComponent instance
 	instance := &appstacksv1.RuntimeComponent{}
-	var ba common.BaseComponent = instance
 	err = r.GetClient().Get(context.TODO(), req.NamespacedName, instance)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
-			// Return and don't requeue
-			return reconcile.Result{}, nil
+			// Owned objects are automatically garbage collected. The rules of the Prometheus Adapter are
+			// removed before the deletion by the finalizer of the instance.
+			// The operator CA of the namespace is deleted once no instance uses it, checking again after the grace period
+			remaining, err := r.newReconcilePipeline().CleanupOperatorCA(req.Namespace, appstacksv1.GroupVersion.Group)
+			if remaining > 0 && r.operatorCACleanup != nil {
+				r.operatorCACleanup.Schedule(remaining)
+			}
+			return reconcile.Result{}, err
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	// Remove the rules of the instance from the configuration of the Prometheus Adapter, which is not owned by the instance
+	if !instance.DeletionTimestamp.IsZero() && controllerutil.ContainsFinalizer(instance, appstacksutils.PrometheusAdapterFinalizer) {
+		if err := r.FinalizePrometheusAdapterRules(instance); err != nil {
+			reqLogger.Error(err, "Failed to remove the rules of the Prometheus Adapter")
+			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+		}
+		controllerutil.RemoveFinalizer(instance, appstacksutils.PrometheusAdapterFinalizer)
+		return reconcile.Result{}, r.GetClient().Update(context.TODO(), instance)
+	}
+
 	if err = common.CheckValidValue(common.Config, common.OpConfigReconcileIntervalMinimum, OperatorName); err != nil {
 		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 	}
@@ -140,6 +160,13 @@ func (r *RuntimeComponentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 	}
 
+	// Leave all child resources untouched while reconciliation is paused
+	if paused, message := appstacksutils.IsReconcilePaused(instance); paused {
+		reqLogger.Info("Reconciliation is paused", "reason", message)
+		return r.ManagePaused(message, instance)
+	}
+	r.UnsetPaused(instance)
+
 	if instance.Status.Versions.Reconciled == "1.4.1" {
 		common.UpdateReconcileIntervalPercentage(common.Config, OperatorName)
 		err = r.CreateOrUpdate(configMap, instance, func() error {
@@ -159,11 +186,16 @@ func (r *RuntimeComponentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		reqLogger.V(1).Info(fmt.Sprintf("%s is not supported on the cluster", servingv1.SchemeGroupVersion.String()))
 	}
 
-	// Check if there is an existing Deployment, Statefulset or Knative service by this name
-	// not managed by this operator
-	err = appstacksutils.CheckForNameConflicts("RuntimeComponent", instance.Name, instance.Namespace, r.GetClient(), req, isKnativeSupported)
-	if err != nil {
-		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+	// Merge the settings of the profile under the spec. They are not saved on the instance
+	unmergedInstance := instance
+	var profileStatus *appstacksv1.StatusProfile
+	if instance.Spec.Profile != nil {
+		unmergedInstance = instance.DeepCopy()
+		profileStatus, err = r.mergeProfile(instance)
+		if err != nil {
+			reqLogger.Error(err, "Error merging profile into RuntimeComponent")
+			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+		}
 	}
 
 	// initialize the RuntimeComponent instance
@@ -182,20 +214,37 @@ func (r *RuntimeComponentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		instance.Annotations = appstacksutils.MergeMaps(instance.Annotations, appstacksutils.GetOpenShiftAnnotations(instance))
 	}
 
-	err = r.GetClient().Update(context.TODO(), instance)
+	if unmergedInstance != instance {
+		unmergedInstance.Initialize()
+		unmergedInstance.Annotations = instance.Annotations
+	}
+	// The finalizer is added before the rules of the Prometheus Adapter, and removed once they are removed
+	if appstacksutils.HasPrometheusAdapterRules(instance) {
+		controllerutil.AddFinalizer(unmergedInstance, appstacksutils.PrometheusAdapterFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(unmergedInstance, appstacksutils.PrometheusAdapterFinalizer)
+	}
+	err = r.GetClient().Update(context.TODO(), unmergedInstance)
 	if err != nil {
 		reqLogger.Error(err, "Error updating RuntimeComponent")
 		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 	}
+	instance.ObjectMeta = unmergedInstance.ObjectMeta
 
-	// currentGen := instance.Generation
-	// if currentGen == 1 {
-	// 	return reconcile.Result{RequeueAfter: common.ReconcileInterval * time.Second}, nil
-	// }
+	// Adopt an existing Deployment, Statefulset or Knative service by this name that has no owner,
+	// if allowed by the adopt annotation. The instance is merged with its profile and initialized first,
+	// so the workloads are checked against the labels of the pods, such as the part-of label
+	err = r.AdoptWorkloads(instance, isKnativeSupported)
+	if err != nil {
+		reqLogger.Error(err, "Failed to adopt existing workloads")
+		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+	}
 
-	defaultMeta := metav1.ObjectMeta{
-		Name:      instance.Name,
-		Namespace: instance.Namespace,
+	// Check if there is an existing Deployment, Statefulset or Knative service by this name
+	// not managed by this operator
+	err = appstacksutils.CheckForNameConflicts("RuntimeComponent", instance.Name, instance.Namespace, r.GetClient(), req, isKnativeSupported)
+	if err != nil {
+		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 	}
 
 	imageReferenceOld := instance.Status.ImageReference
@@ -233,331 +282,56 @@ func (r *RuntimeComponentReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
-	serviceAccountName := appstacksutils.GetServiceAccountName(instance)
-	if serviceAccountName != defaultMeta.Name {
-		if serviceAccountName == "" {
-			serviceAccount := &corev1.ServiceAccount{ObjectMeta: defaultMeta}
-			err = r.CreateOrUpdate(serviceAccount, instance, func() error {
-				return appstacksutils.CustomizeServiceAccount(serviceAccount, instance, r.GetClient())
-			})
-			if err != nil {
-				reqLogger.Error(err, "Failed to reconcile ServiceAccount")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-		} else {
-			// delete our SA, as one has been specified
-			serviceAccount := &corev1.ServiceAccount{ObjectMeta: defaultMeta}
-			err = r.DeleteResource(serviceAccount)
-			if err != nil {
-				reqLogger.Error(err, "Failed to delete ServiceAccount")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-		}
-	}
-
-	// Check if the ServiceAccount has a valid pull secret before creating the deployment/statefulset
-	// or setting up knative. Otherwise the pods can go into an ImagePullBackOff loop
-	saErr := appstacksutils.ServiceAccountPullSecretExists(instance, r.GetClient())
-	if saErr != nil {
-		return r.ManageError(saErr, common.StatusConditionTypeReconciled, instance)
-	}
-
-	if instance.Spec.CreateKnativeService != nil && *instance.Spec.CreateKnativeService {
-		// Clean up non-Knative resources
-		resources := []client.Object{
-			&corev1.Service{ObjectMeta: defaultMeta},
-			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + "-headless", Namespace: instance.Namespace}},
-			&appsv1.Deployment{ObjectMeta: defaultMeta},
-			&appsv1.StatefulSet{ObjectMeta: defaultMeta},
-			&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: defaultMeta},
-			&networkingv1.NetworkPolicy{ObjectMeta: defaultMeta},
-		}
-		err = r.DeleteResources(resources)
-		if err != nil {
-			reqLogger.Error(err, "Failed to clean up non-Knative resources")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-
-		if ok, _ := r.IsGroupVersionSupported(networkingv1.SchemeGroupVersion.String(), "Ingress"); ok {
-			r.DeleteResource(&networkingv1.Ingress{ObjectMeta: defaultMeta})
-		}
-
-		if r.IsOpenShift() {
-			route := &routev1.Route{ObjectMeta: defaultMeta}
-			err = r.DeleteResource(route)
-			if err != nil {
-				reqLogger.Error(err, "Failed to clean up non-Knative resource Route")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-		}
-
-		if isKnativeSupported {
-			reqLogger.Info("Knative is supported and Knative Service is enabled")
-			ksvc := &servingv1.Service{ObjectMeta: defaultMeta}
-			err = r.CreateOrUpdate(ksvc, instance, func() error {
-				appstacksutils.CustomizeKnativeService(ksvc, instance)
-				return nil
-			})
-			if err != nil {
-				reqLogger.Error(err, "Failed to reconcile Knative Service")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-			instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
-			instance.Status.Versions.Reconciled = appstacksutils.RCOOperandVersion
-			reqLogger.Info("Reconcile RuntimeComponent - completed")
-			return r.ManageSuccess(common.StatusConditionTypeReconciled, instance)
-		}
-		return r.ManageError(errors.New("failed to reconcile Knative service as operator could not find Knative CRDs"), common.StatusConditionTypeReconciled, instance)
-	}
-
-	if isKnativeSupported {
-		ksvc := &servingv1.Service{ObjectMeta: defaultMeta}
-		err = r.DeleteResource(ksvc)
-		if err != nil {
-			reqLogger.Error(err, "Failed to delete Knative Service")
-			r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-	}
-
-	useCertmanager, err := r.GenerateSvcCertSecret(ba, "rco", "Runtime Component Operator", "runtime-component-operator")
-	if err != nil {
-		reqLogger.Error(err, "Failed to reconcile CertManager Certificate")
-		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-	}
-	if ba.GetService().GetCertificateSecretRef() != nil {
-		ba.GetStatus().SetReference(common.StatusReferenceCertSecretName, *ba.GetService().GetCertificateSecretRef())
-	}
-
-	svc := &corev1.Service{ObjectMeta: defaultMeta}
-	err = r.CreateOrUpdate(svc, instance, func() error {
-		appstacksutils.CustomizeService(svc, ba)
-		svc.Annotations = appstacksutils.MergeMaps(svc.Annotations, instance.Spec.Service.Annotations)
-		if !useCertmanager && r.IsOpenShift() {
-			appstacksutils.AddOCPCertAnnotation(ba, svc)
-		}
-		monitoringEnabledLabelName := getMonitoringEnabledLabelName(ba)
-		if instance.Spec.Monitoring != nil {
-			svc.Labels[monitoringEnabledLabelName] = "true"
-		} else {
-			delete(svc.Labels, monitoringEnabledLabelName)
-		}
-		return nil
-	})
-	if err != nil {
-		reqLogger.Error(err, "Failed to reconcile Service")
-		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-	}
-
-	networkPolicy := &networkingv1.NetworkPolicy{ObjectMeta: defaultMeta}
-	if np := instance.Spec.NetworkPolicy; np == nil || np != nil && !np.IsDisabled() {
-		err = r.CreateOrUpdate(networkPolicy, instance, func() error {
-			appstacksutils.CustomizeNetworkPolicy(networkPolicy, r.IsOpenShift(), instance)
-			return nil
-		})
-		if err != nil {
-			reqLogger.Error(err, "Failed to reconcile network policy")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-	} else {
-		if err := r.DeleteResource(networkPolicy); err != nil {
-			reqLogger.Error(err, "Failed to delete network policy")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
+	pipeline := r.newReconcilePipeline()
+	pipeline.OnReconciled = func(ba common.BaseComponent) {
+		instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+		instance.Status.Versions.Reconciled = appstacksutils.RCOOperandVersion
+		instance.Status.Profile = profileStatus
+		reqLogger.Info("Reconcile RuntimeComponent - completed")
 	}
+	pipeline.FindHostOwner = r.findHostOwner
+	return pipeline.ReconcileBaseComponent(ctx, instance)
+}
 
-	err = r.ReconcileBindings(instance)
-	if err != nil {
-		return r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+// newReconcilePipeline returns the pipeline of the RuntimeComponents, which deletes the operator CA of a namespace once
+// it is no longer used
+func (r *RuntimeComponentReconciler) newReconcilePipeline() *appstacksutils.ReconcilePipeline {
+	pipeline := r.NewReconcilePipeline("rco", "Runtime Component Operator", OperatorName)
+	pipeline.IsOperatorCAInUse = r.isOperatorCAInUse
+	if r.operatorCACleanup != nil {
+		pipeline.ScheduleOperatorCACleanup = r.operatorCACleanup.Schedule
 	}
+	return pipeline
+}
 
-	if instance.Spec.StatefulSet != nil {
-		// Delete Deployment if exists
-		deploy := &appsv1.Deployment{ObjectMeta: defaultMeta}
-		err = r.DeleteResource(deploy)
-
-		if err != nil {
-			reqLogger.Error(err, "Failed to delete Deployment")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + "-headless", Namespace: instance.Namespace}}
-		err = r.CreateOrUpdate(svc, instance, func() error {
-			appstacksutils.CustomizeService(svc, instance)
-			svc.Spec.ClusterIP = corev1.ClusterIPNone
-			svc.Spec.Type = corev1.ServiceTypeClusterIP
-			return nil
-		})
-		if err != nil {
-			reqLogger.Error(err, "Failed to reconcile headless Service")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-
-		statefulSet := &appsv1.StatefulSet{ObjectMeta: defaultMeta}
-		err = r.CreateOrUpdate(statefulSet, instance, func() error {
-			appstacksutils.CustomizeStatefulSet(statefulSet, instance)
-			appstacksutils.CustomizePodSpec(&statefulSet.Spec.Template, instance)
-			if err := appstacksutils.CustomizePodWithSVCCertificate(&statefulSet.Spec.Template, instance, r.GetClient()); err != nil {
-				return err
-			}
-			appstacksutils.CustomizePersistence(statefulSet, instance)
-			return nil
-		})
-		if err != nil {
-			reqLogger.Error(err, "Failed to reconcile StatefulSet")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-
-	} else {
-		// Delete StatefulSet if exists
-		statefulSet := &appsv1.StatefulSet{ObjectMeta: defaultMeta}
-		err = r.DeleteResource(statefulSet)
-		if err != nil {
-			reqLogger.Error(err, "Failed to delete Statefulset")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-
-		// Delete StatefulSet if exists
-		headlesssvc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + "-headless", Namespace: instance.Namespace}}
-		err = r.DeleteResource(headlesssvc)
-
-		if err != nil {
-			reqLogger.Error(err, "Failed to delete headless Service")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-		deploy := &appsv1.Deployment{ObjectMeta: defaultMeta}
-		err = r.CreateOrUpdate(deploy, instance, func() error {
-			appstacksutils.CustomizeDeployment(deploy, instance)
-			appstacksutils.CustomizePodSpec(&deploy.Spec.Template, instance)
-			if err := appstacksutils.CustomizePodWithSVCCertificate(&deploy.Spec.Template, instance, r.GetClient()); err != nil {
-				return err
-			}
-			return nil
-		})
-		if err != nil {
-			reqLogger.Error(err, "Failed to reconcile Deployment")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-
+// findHostOwner returns the namespace/name of another RuntimeComponent that claims the host and path and has
+// precedence over the instance, or ""
+func (r *RuntimeComponentReconciler) findHostOwner(ba common.BaseComponent, hostPath string) (string, error) {
+	instances := &appstacksv1.RuntimeComponentList{}
+	if err := r.GetClient().List(context.TODO(), instances, client.MatchingFields{indexFieldHost: hostPath}); err != nil {
+		return "", err
 	}
-
-	if instance.Spec.Autoscaling != nil {
-		hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: defaultMeta}
-		err = r.CreateOrUpdate(hpa, instance, func() error {
-			appstacksutils.CustomizeHPA(hpa, instance)
-			return nil
-		})
-
-		if err != nil {
-			reqLogger.Error(err, "Failed to reconcile HorizontalPodAutoscaler")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		}
-	} else {
-		hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: defaultMeta}
-		err = r.DeleteResource(hpa)
-		if err != nil {
-			reqLogger.Error(err, "Failed to delete HorizontalPodAutoscaler")
-			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+	obj := ba.(client.Object)
+	for i, instance := range instances.Items {
+		if instance.UID != obj.GetUID() && appstacksutils.HasHostPrecedence(&instances.Items[i], ba, hostPath) {
+			return instance.Namespace + "/" + instance.Name, nil
 		}
 	}
+	return "", nil
+}
 
-	if ok, err := r.IsGroupVersionSupported(routev1.SchemeGroupVersion.String(), "Route"); err != nil {
-		reqLogger.Error(err, fmt.Sprintf("Failed to check if %s is supported", routev1.SchemeGroupVersion.String()))
-		r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-	} else if ok {
-		if instance.Spec.Expose != nil && *instance.Spec.Expose {
-			if appstacksutils.ShouldDeleteRoute(ba) {
-				reqLogger.Info("Custom hostname has been removed from route, deleting and recreating the route")
-				route := &routev1.Route{ObjectMeta: defaultMeta}
-				err = r.DeleteResource(route)
-				if err != nil {
-					reqLogger.Error(err, "Failed to delete Route")
-					return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-				}
-			}
-
-			route := &routev1.Route{ObjectMeta: defaultMeta}
-			err = r.CreateOrUpdate(route, instance, func() error {
-				key, cert, caCert, destCACert, err := r.GetRouteTLSValues(ba)
-				if err != nil {
-					return err
-				}
-				appstacksutils.CustomizeRoute(route, ba, key, cert, caCert, destCACert)
-
-				return nil
-			})
-			if err != nil {
-				reqLogger.Error(err, "Failed to reconcile Route")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-		} else {
-			route := &routev1.Route{ObjectMeta: defaultMeta}
-			err = r.DeleteResource(route)
-			if err != nil {
-				reqLogger.Error(err, "Failed to delete Route")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-		}
-	} else {
-
-		if ok, err := r.IsGroupVersionSupported(networkingv1.SchemeGroupVersion.String(), "Ingress"); err != nil {
-			reqLogger.Error(err, fmt.Sprintf("Failed to check if %s is supported", networkingv1.SchemeGroupVersion.String()))
-			r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-		} else if ok {
-			if instance.Spec.Expose != nil && *instance.Spec.Expose {
-				ing := &networkingv1.Ingress{ObjectMeta: defaultMeta}
-				err = r.CreateOrUpdate(ing, instance, func() error {
-					appstacksutils.CustomizeIngress(ing, instance)
-					return nil
-				})
-				if err != nil {
-					reqLogger.Error(err, "Failed to reconcile Ingress")
-					return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-				}
-			} else {
-				ing := &networkingv1.Ingress{ObjectMeta: defaultMeta}
-				err = r.DeleteResource(ing)
-				if err != nil {
-					reqLogger.Error(err, "Failed to delete Ingress")
-					return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-				}
-			}
-		}
+// isOperatorCAInUse returns true if a RuntimeComponent of the namespace that is not being deleted uses the operator CA
+func (r *RuntimeComponentReconciler) isOperatorCAInUse(namespace string) (bool, error) {
+	instances := &appstacksv1.RuntimeComponentList{}
+	if err := r.GetClient().List(context.TODO(), instances, client.InNamespace(namespace)); err != nil {
+		return false, err
 	}
-
-	if ok, err := r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "ServiceMonitor"); err != nil {
-		reqLogger.Error(err, fmt.Sprintf("Failed to check if %s is supported", prometheusv1.SchemeGroupVersion.String()))
-		r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-	} else if ok {
-		if instance.Spec.Monitoring != nil && (instance.Spec.CreateKnativeService == nil || !*instance.Spec.CreateKnativeService) {
-			// Validate the monitoring endpoints' configuration before creating/updating the ServiceMonitor
-			if err := appstacksutils.ValidatePrometheusMonitoringEndpoints(instance, r.GetClient(), instance.GetNamespace()); err != nil {
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-			sm := &prometheusv1.ServiceMonitor{ObjectMeta: defaultMeta}
-			err = r.CreateOrUpdate(sm, instance, func() error {
-				appstacksutils.CustomizeServiceMonitor(sm, instance)
-				return nil
-			})
-			if err != nil {
-				reqLogger.Error(err, "Failed to reconcile ServiceMonitor")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
-		} else {
-			sm := &prometheusv1.ServiceMonitor{ObjectMeta: defaultMeta}
-			err = r.DeleteResource(sm)
-			if err != nil {
-				reqLogger.Error(err, "Failed to delete ServiceMonitor")
-				return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
-			}
+	for i := range instances.Items {
+		if instances.Items[i].DeletionTimestamp.IsZero() && appstacksutils.UsesOperatorCA(&instances.Items[i]) {
+			return true, nil
 		}
-
-	} else {
-		reqLogger.V(1).Info(fmt.Sprintf("%s is not supported", prometheusv1.SchemeGroupVersion.String()))
 	}
-
-	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
-	instance.Status.Versions.Reconciled = appstacksutils.RCOOperandVersion
-	reqLogger.Info("Reconcile RuntimeComponent - completed")
-	return r.ManageSuccess(common.StatusConditionTypeReconciled, instance)
+	return false, nil
 }
 
 // SetupWithManager initializes reconciler
@@ -577,12 +351,34 @@ func (r *RuntimeComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return nil
 	})
 
+	mgr.GetFieldIndexer().IndexField(context.Background(), &appstacksv1.RuntimeComponent{}, indexFieldProfile, func(obj client.Object) []string {
+		instance := obj.(*appstacksv1.RuntimeComponent)
+		if instance.Spec.Profile == nil {
+			return nil
+		}
+		return []string{instance.Spec.Profile.GetKind() + "/" + instance.Spec.Profile.Name}
+	})
+
+	mgr.GetFieldIndexer().IndexField(context.Background(), &appstacksv1.RuntimeComponent{}, indexFieldHost, func(obj client.Object) []string {
+		instance := obj.(*appstacksv1.RuntimeComponent)
+		if hostPath := instance.Status.References[common.StatusReferenceHost]; hostPath != "" {
+			return []string{hostPath}
+		}
+		return nil
+	})
+
 	watchNamespaces, err := appstacksutils.GetWatchNamespaces()
 	if err != nil {
 		r.Log.Error(err, "Failed to get watch namespace")
 		os.Exit(1)
 	}
 
+	// The operator CAs that are no longer used are checked when the operator starts and once their grace period is over
+	r.operatorCACleanup = appstacksutils.NewOperatorCACleanup(r.newReconcilePipeline(), appstacksv1.GroupVersion.Group)
+	if err := mgr.Add(r.operatorCACleanup); err != nil {
+		return err
+	}
+
 	watchNamespacesMap := make(map[string]bool)
 	for _, ns := range watchNamespaces {
 		watchNamespacesMap[ns] = true
@@ -592,7 +388,9 @@ func (r *RuntimeComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	pred := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			// Ignore updates to CR status in which case metadata.Generation does not change
-			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() && (isClusterWide || watchNamespacesMap[e.ObjectNew.GetNamespace()])
+			// Pausing, resuming or restarting only changes the annotations
+			annotationsChanged := isWatchedAnnotationChanged(e.ObjectOld, e.ObjectNew)
+			return (e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() || annotationsChanged) && (isClusterWide || watchNamespacesMap[e.ObjectNew.GetNamespace()])
 		},
 		CreateFunc: func(e event.CreateEvent) bool {
 			return isClusterWide || watchNamespacesMap[e.Object.GetNamespace()]
@@ -638,6 +436,24 @@ func (r *RuntimeComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	b := ctrl.NewControllerManagedBy(mgr).For(&appstacksv1.RuntimeComponent{}, builder.WithPredicates(pred))
 
+	// Re-reconcile the components that reference a profile when it changes
+	b = b.Watches(&appstacksv1.RuntimeComponentProfile{}, &EnqueueRequestsForCustomIndexField{
+		Matcher: &ProfileMatcher{
+			Klient:          mgr.GetClient(),
+			WatchNamespaces: watchNamespaces,
+			Kind:            appstacksv1.RuntimeComponentProfileKind,
+		},
+	})
+	if isClusterWide {
+		b = b.Watches(&appstacksv1.ClusterRuntimeComponentProfile{}, &EnqueueRequestsForCustomIndexField{
+			Matcher: &ProfileMatcher{
+				Klient:          mgr.GetClient(),
+				WatchNamespaces: watchNamespaces,
+				Kind:            appstacksv1.ClusterRuntimeComponentProfileKind,
+			},
+		})
+	}
+
 	if !appstacksutils.GetOperatorDisableWatches() {
 		b = b.Owns(&corev1.Service{}, builder.WithPredicates(predSubResource)).
 			Owns(&corev1.Secret{}, builder.WithPredicates(predSubResource)).
@@ -660,10 +476,29 @@ func (r *RuntimeComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		if ok {
 			b = b.Owns(&servingv1.Service{}, builder.WithPredicates(predSubResource))
 		}
+		ok, _ = r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Certificate")
+		if ok {
+			// Renewed certificates are rendered into the Route
+			b = b.Owns(&certmanagerv1.Certificate{}, builder.WithPredicates(predSubResource))
+		}
 		ok, _ = r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "ServiceMonitor")
 		if ok {
 			b = b.Owns(&prometheusv1.ServiceMonitor{}, builder.WithPredicates(predSubResource))
 		}
+		ok, _ = r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "PodMonitor")
+		if ok {
+			b = b.Owns(&prometheusv1.PodMonitor{}, builder.WithPredicates(predSubResource))
+		}
+		ok, _ = r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "PrometheusRule")
+		if ok {
+			b = b.Owns(&prometheusv1.PrometheusRule{}, builder.WithPredicates(predSubResource))
+		}
+		ok, _ = r.IsGroupVersionSupported(appstacksutils.GrafanaDashboardGVK.GroupVersion().String(), appstacksutils.GrafanaDashboardGVK.Kind)
+		if ok {
+			gd := &unstructured.Unstructured{}
+			gd.SetGroupVersionKind(appstacksutils.GrafanaDashboardGVK)
+			b = b.Owns(gd, builder.WithPredicates(predSubResource))
+		}
 		ok, _ = r.IsGroupVersionSupported(imagev1.SchemeGroupVersion.String(), "ImageStream")
 		if ok {
 			b = b.Watches(&imagev1.ImageStream{}, &EnqueueRequestsForCustomIndexField{
@@ -682,6 +517,49 @@ func (r *RuntimeComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}).Complete(r)
 }
 
-func getMonitoringEnabledLabelName(ba common.BaseComponent) string {
-	return "monitor." + ba.GetGroupName() + "/enabled"
+// isWatchedAnnotationChanged returns true if the paused, restartedAt or adopt annotation changed
+func isWatchedAnnotationChanged(oldObj client.Object, newObj client.Object) bool {
+	ba, ok := newObj.(common.BaseComponent)
+	if !ok {
+		return false
+	}
+	for _, annotation := range []string{appstacksutils.GetPausedAnnotationName(ba), appstacksutils.GetRestartedAtAnnotationName(ba), appstacksutils.GetAdoptAnnotationName(ba)} {
+		if oldObj.GetAnnotations()[annotation] != newObj.GetAnnotations()[annotation] {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeProfile merges the profile referenced by the instance under its spec and returns the profile status
+func (r *RuntimeComponentReconciler) mergeProfile(instance *appstacksv1.RuntimeComponent) (*appstacksv1.StatusProfile, error) {
+	ref := instance.Spec.Profile
+	var profileSpec *appstacksv1.RuntimeComponentProfileSpec
+	var generation int64
+	if ref.GetKind() == appstacksv1.ClusterRuntimeComponentProfileKind {
+		watchNamespaces, err := appstacksutils.GetWatchNamespaces()
+		if err != nil {
+			return nil, err
+		}
+		// The operator is only given access to cluster-scoped profiles when it watches all namespaces
+		if !appstacksutils.IsClusterWide(watchNamespaces) {
+			return nil, fmt.Errorf("%s %s can only be used when the operator watches all namespaces", ref.GetKind(), ref.Name)
+		}
+		profile := &appstacksv1.ClusterRuntimeComponentProfile{}
+		if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ref.Name}, profile); err != nil {
+			return nil, err
+		}
+		profileSpec, generation = &profile.Spec, profile.Generation
+	} else {
+		profile := &appstacksv1.RuntimeComponentProfile{}
+		if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: instance.Namespace}, profile); err != nil {
+			return nil, err
+		}
+		profileSpec, generation = &profile.Spec, profile.Generation
+	}
+
+	if err := appstacksutils.MergeProfile(&instance.Spec, profileSpec); err != nil {
+		return nil, fmt.Errorf("failed to merge %s %s: %w", ref.GetKind(), ref.Name, err)
+	}
+	return &appstacksv1.StatusProfile{Kind: ref.GetKind(), Name: ref.Name, ObservedGeneration: generation}, nil
 }
@@ -0,0 +1,129 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Returns the name of the annotation that allows the instance to adopt unowned workloads with the same name
+func GetAdoptAnnotationName(ba common.BaseComponent) string {
+	return ba.GetGroupName() + "/adopt"
+}
+
+// IsAdoptionEnabled returns true if the instance may adopt unowned workloads with the same name
+func IsAdoptionEnabled(ba common.BaseComponent) bool {
+	return ba.GetAnnotations()[GetAdoptAnnotationName(ba)] == "true"
+}
+
+// AdoptWorkloads takes ownership of the Deployment, StatefulSet and Knative Service with the name of the instance
+// that have no owner, when adoption is enabled on the instance. The adopted workloads are then updated in place,
+// so their pods are rolled instead of recreated. An error is returned if a workload is unowned and adoption is
+// not enabled, or if the workload cannot be updated in place. Workloads with an owner are left to CheckForNameConflicts.
+func (r *ReconcilerBase) AdoptWorkloads(ba common.BaseComponent, isKnativeSupported bool) error {
+	obj := ba.(metav1.Object)
+	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+
+	workloads := []struct {
+		kind   string
+		object client.Object
+	}{
+		{"Deployment", &appsv1.Deployment{}},
+		{"StatefulSet", &appsv1.StatefulSet{}},
+	}
+	if isKnativeSupported {
+		workloads = append(workloads, struct {
+			kind   string
+			object client.Object
+		}{"Knative Service", &servingv1.Service{}})
+	}
+
+	for _, workload := range workloads {
+		err := r.GetClient().Get(context.TODO(), key, workload.object)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(workload.object.GetOwnerReferences()) > 0 {
+			continue
+		}
+
+		if !IsAdoptionEnabled(ba) {
+			return fmt.Errorf("existing %s %q is not managed by this operator, set the %s annotation to \"true\" to adopt it or resolve the naming conflict",
+				workload.kind, key.Name, GetAdoptAnnotationName(ba))
+		}
+		if err := checkAdoptable(ba, workload.object); err != nil {
+			return err
+		}
+		if err := controllerutil.SetControllerReference(obj, workload.object, r.scheme); err != nil {
+			return err
+		}
+		if err := r.GetClient().Update(context.TODO(), workload.object); err != nil {
+			return err
+		}
+		log.Info("Adopted existing workload", "kind", workload.kind, "name", key.Name, "namespace", key.Namespace)
+		r.GetRecorder().Event(ba.(client.Object), "Normal", "Adopted", fmt.Sprintf("Adopted existing %s %q.", workload.kind, key.Name))
+	}
+	return nil
+}
+
+// checkAdoptable returns an error if the workload cannot be updated in place to match the instance
+func checkAdoptable(ba common.BaseComponent, workload client.Object) error {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return checkAdoptableSelector(ba, "Deployment", w.Name, w.Spec.Selector)
+	case *appsv1.StatefulSet:
+		if err := checkAdoptableSelector(ba, "StatefulSet", w.Name, w.Spec.Selector); err != nil {
+			return err
+		}
+		// The service name of a StatefulSet is immutable
+		if serviceName := w.Name + "-headless"; w.Spec.ServiceName != serviceName {
+			return fmt.Errorf("existing StatefulSet %q cannot be adopted: its serviceName %q is not %q and cannot be changed in place, recreate the StatefulSet or resolve the naming conflict",
+				w.Name, w.Spec.ServiceName, serviceName)
+		}
+	}
+	return nil
+}
+
+// checkAdoptableSelector returns an error if the selector of the workload does not select the pods of the instance.
+// The selector is immutable, so the operator keeps it and the pod template labels must match it.
+func checkAdoptableSelector(ba common.BaseComponent, kind string, name string, labelSelector *metav1.LabelSelector) error {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return fmt.Errorf("existing %s %q cannot be adopted: %w", kind, name, err)
+	}
+	podLabels := labels.Set(ba.GetLabels())
+	if selector.Empty() || !selector.Matches(podLabels) {
+		return fmt.Errorf("existing %s %q cannot be adopted: its selector %q does not match the pod labels %q and cannot be changed in place, add the selected labels to the instance, recreate the %s or resolve the naming conflict",
+			kind, name, selector.String(), podLabels.String(), kind)
+	}
+	return nil
+}
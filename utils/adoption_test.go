@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestAdoptWorkloads(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.UID = "my-app-uid"
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/instance": name}},
+		},
+	}
+	objs, s := []runtime.Object{runtimecomponent, deploy}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+
+	notEnabledErr := r.AdoptWorkloads(runtimecomponent, false)
+
+	runtimecomponent.Annotations = map[string]string{GetAdoptAnnotationName(runtimecomponent): "true"}
+	err := r.AdoptWorkloads(runtimecomponent, false)
+	adopted := &appsv1.Deployment{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, adopted)
+	owner := metav1.GetControllerOf(adopted)
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.StatefulSetSpec{
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy"}},
+			ServiceName: name + "-headless",
+		},
+	}
+	cl.Create(context.TODO(), statefulSet)
+	selectorErr := r.AdoptWorkloads(runtimecomponent, false)
+
+	runtimecomponent.Labels = map[string]string{"app": "legacy"}
+	statefulSet.Spec.ServiceName = name
+	cl.Update(context.TODO(), statefulSet)
+	serviceNameErr := r.AdoptWorkloads(runtimecomponent, false)
+
+	testAW := []Test{
+		{"adoption not enabled", true, notEnabledErr != nil && strings.Contains(notEnabledErr.Error(), GetAdoptAnnotationName(runtimecomponent))},
+		{"no error", nil, err},
+		{"controller reference", true, owner != nil && owner.UID == runtimecomponent.UID},
+		{"selector kept", map[string]string{"app.kubernetes.io/instance": name}, adopted.Spec.Selector.MatchLabels},
+		{"incompatible selector", true, selectorErr != nil && strings.Contains(selectorErr.Error(), "app=legacy")},
+		{"incompatible service name", true, serviceNameErr != nil && strings.Contains(serviceNameErr.Error(), "serviceName")},
+	}
+	verifyTests(testAW, t)
+}
+
+func TestAdoptWorkloadsPartOf(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	// The part-of label is set from the application name, which defaults to the name of the instance
+	runtimecomponent := createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage})
+	runtimecomponent.UID = "my-app-uid"
+	runtimecomponent.Annotations = map[string]string{GetAdoptAnnotationName(runtimecomponent): "true"}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/part-of": name}},
+		},
+	}
+	objs, s := []runtime.Object{runtimecomponent, deploy}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+
+	uninitializedErr := r.AdoptWorkloads(runtimecomponent, false)
+	runtimecomponent.Initialize()
+	err := r.AdoptWorkloads(runtimecomponent, false)
+	adopted := &appsv1.Deployment{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, adopted)
+
+	testAWPO := []Test{
+		{"part-of label not set before initialization", true, uninitializedErr != nil && strings.Contains(uninitializedErr.Error(), "app.kubernetes.io/part-of=my-app")},
+		{"no error after initialization", nil, err},
+		{"controller reference", true, metav1.IsControlledBy(adopted, runtimecomponent)},
+	}
+	verifyTests(testAWPO, t)
+}
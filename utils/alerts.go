@@ -0,0 +1,169 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// invalidLabelNameChars matches the characters that are not allowed in Prometheus label names
+var invalidLabelNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// CustomizePrometheusRule sets the PrometheusRule with the built-in alerts that are enabled and the custom rules of
+// .spec.monitoring.alerts. The labels of the instance are added to the labels of every rule
+func CustomizePrometheusRule(pr *prometheusv1.PrometheusRule, ba common.BaseComponent) {
+	obj := ba.(metav1.Object)
+	pr.Labels = MergeMaps(ba.GetLabels(), ba.GetMonitoring().GetLabels())
+	pr.Annotations = MergeMaps(pr.Annotations, ba.GetAnnotations())
+
+	alerts := ba.GetMonitoring().GetAlerts()
+	ns, podRegex := obj.GetNamespace(), getPodRegex(ba)
+	selector := fmt.Sprintf(`namespace=%q,pod=~"%s"`, ns, podRegex)
+	var rules []prometheusv1.Rule
+
+	isKnative := ba.GetCreateKnativeService() != nil && *ba.GetCreateKnativeService()
+	if a := alerts.GetReplicasUnavailable(); (a == nil || a.IsEnabled()) && !isKnative {
+		threshold := getAlertThreshold(a, 1)
+		expr := fmt.Sprintf(`kube_deployment_status_replicas_unavailable{namespace=%q,deployment=%q} >= %d`, ns, obj.GetName(), threshold)
+		if ba.GetStatefulSet() != nil {
+			expr = fmt.Sprintf(`kube_statefulset_replicas{namespace=%q,statefulset=%q} - kube_statefulset_status_replicas_ready{namespace=%q,statefulset=%q} >= %d`,
+				ns, obj.GetName(), ns, obj.GetName(), threshold)
+		}
+		rules = append(rules, createAlertRule(a, "ReplicasUnavailable", expr, "10m",
+			"Replicas of {{ $labels.namespace }}/"+obj.GetName()+" are unavailable",
+			"{{ $value }} replicas of "+obj.GetName()+" are unavailable."))
+	}
+
+	if a := alerts.GetRestartStorm(); a == nil || a.IsEnabled() {
+		threshold := getAlertThreshold(a, 3)
+		expr := fmt.Sprintf(`increase(kube_pod_container_status_restarts_total{%s}[15m]) >= %d`, selector, threshold)
+		rules = append(rules, createAlertRule(a, "RestartStorm", expr, "",
+			"Container {{ $labels.container }} of pod {{ $labels.namespace }}/{{ $labels.pod }} restarts repeatedly",
+			"Container {{ $labels.container }} of pod {{ $labels.pod }} restarted {{ $value }} times in 15 minutes."))
+	}
+
+	if a := alerts.GetHighErrorRate(); a == nil || a.IsEnabled() {
+		metric, statusCodeLabel := common.DefaultErrorRateMetric, common.DefaultErrorRateStatusCodeLabel
+		var alert common.BaseComponentAlert
+		if a != nil {
+			metric, statusCodeLabel, alert = a.GetMetric(), a.GetStatusCodeLabel(), a
+		}
+		threshold := getAlertThreshold(alert, 5)
+		expr := fmt.Sprintf(`sum by (namespace) (rate(%s{%s,%s=~"5.."}[5m])) / sum by (namespace) (rate(%s{%s}[5m])) * 100 > %d`,
+			metric, selector, statusCodeLabel, metric, selector, threshold)
+		rules = append(rules, createAlertRule(alert, "HighErrorRate", expr, "10m",
+			"High rate of HTTP 5xx responses from {{ $labels.namespace }}/"+obj.GetName(),
+			"{{ $value | humanize }}% of the requests to "+obj.GetName()+" failed with a 5xx status in 5 minutes."))
+	}
+
+	if a := alerts.GetCertificateExpiry(); a == nil || a.IsEnabled() {
+		threshold := getAlertThreshold(a, 7)
+		// The namespace of the Certificate is exported_namespace when the cert-manager metrics are scraped without honorLabels
+		certRegex := quotePromQLRegex(obj.GetName()) + "-(svc|client|route)-tls-cm"
+		expr := fmt.Sprintf(`(certmanager_certificate_expiration_timestamp_seconds{exported_namespace=%q,name=~"%s"} or certmanager_certificate_expiration_timestamp_seconds{namespace=%q,name=~"%s"}) - time() < %d * 86400`,
+			ns, certRegex, ns, certRegex, threshold)
+		rules = append(rules, createAlertRule(a, "CertificateExpiry", expr, "1h",
+			"Certificate {{ $labels.name }} of "+obj.GetName()+" expires soon",
+			"Certificate {{ $labels.name }} expires in {{ $value | humanizeDuration }}."))
+	}
+
+	replacer := newQueryReplacer(ba)
+	for _, custom := range alerts.GetRules() {
+		rule := *custom.DeepCopy()
+		if rule.Expr.Type == intstr.String {
+			rule.Expr = intstr.FromString(replacer.Replace(rule.Expr.StrVal))
+		}
+		for k, v := range rule.Labels {
+			rule.Labels[k] = replacer.Replace(v)
+		}
+		for k, v := range rule.Annotations {
+			rule.Annotations[k] = replacer.Replace(v)
+		}
+		rules = append(rules, rule)
+	}
+
+	// Labels of the instance, which are overridden by the labels of the rules
+	componentLabels := map[string]string{}
+	for k, v := range ba.GetLabels() {
+		componentLabels[invalidLabelNameChars.ReplaceAllString(k, "_")] = v
+	}
+	for i := range rules {
+		if rules[i].Alert != "" || rules[i].Record != "" {
+			rules[i].Labels = MergeMaps(componentLabels, rules[i].Labels)
+		}
+	}
+
+	pr.Spec.Groups = []prometheusv1.RuleGroup{{Name: obj.GetNamespace() + "-" + obj.GetName(), Rules: rules}}
+}
+
+// createAlertRule returns a built-in alerting rule, with the severity and duration of the alert configuration
+func createAlertRule(a common.BaseComponentAlert, name string, expr string, defaultFor prometheusv1.Duration, summary string, description string) prometheusv1.Rule {
+	rule := prometheusv1.Rule{
+		Alert:       name,
+		Expr:        intstr.FromString(expr),
+		Labels:      map[string]string{"severity": "warning"},
+		Annotations: map[string]string{"summary": summary, "description": description},
+	}
+	if defaultFor != "" {
+		rule.For = &defaultFor
+	}
+	if a != nil {
+		rule.Labels["severity"] = a.GetSeverity()
+		if a.GetFor() != nil {
+			rule.For = a.GetFor()
+		}
+	}
+	return rule
+}
+
+// getAlertThreshold returns the threshold of the alert configuration, or the default threshold
+func getAlertThreshold(a common.BaseComponentAlert, defaultThreshold int32) int32 {
+	if a == nil || a.GetThreshold() == nil {
+		return defaultThreshold
+	}
+	return *a.GetThreshold()
+}
+
+// getPodRegex returns a regular expression that matches the names of the pods of the instance
+func getPodRegex(ba common.BaseComponent) string {
+	name := quotePromQLRegex(ba.(metav1.Object).GetName())
+	if ba.GetCreateKnativeService() != nil && *ba.GetCreateKnativeService() {
+		return name + "-[0-9]+-deployment-[a-z0-9]+-[a-z0-9]+"
+	}
+	if ba.GetStatefulSet() != nil {
+		return name + "-[0-9]+"
+	}
+	return name + "-[a-z0-9]+-[a-z0-9]+"
+}
+
+// newQueryReplacer returns a replacer of the $(NAME), $(NAMESPACE) and $(POD_REGEX) variables of PromQL queries
+func newQueryReplacer(ba common.BaseComponent) *strings.Replacer {
+	obj := ba.(metav1.Object)
+	return strings.NewReplacer("$(NAME)", obj.GetName(), "$(NAMESPACE)", obj.GetNamespace(), "$(POD_REGEX)", getPodRegex(ba))
+}
+
+// quotePromQLRegex escapes the regular expression metacharacters of a name in a double-quoted PromQL string
+func quotePromQLRegex(name string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(name), `\`, `\\`)
+}
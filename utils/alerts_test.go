@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestCustomizePrometheusRule(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	disabled, threshold, severity, forDuration := false, int32(10), "critical", prometheusv1.Duration("5m")
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Spec.Monitoring = &appstacksv1.RuntimeComponentMonitoring{Alerts: &appstacksv1.RuntimeComponentMonitoringAlerts{
+		RestartStorm: &appstacksv1.RuntimeComponentAlert{Threshold: &threshold, Severity: &severity, For: &forDuration},
+		HighErrorRate: &appstacksv1.RuntimeComponentErrorRateAlert{
+			RuntimeComponentAlert: appstacksv1.RuntimeComponentAlert{Enabled: &disabled}},
+		Rules: []prometheusv1.Rule{{Alert: "SlowRequests", Expr: intstr.FromString(`latency{namespace="$(NAMESPACE)",pod=~"$(POD_REGEX)"} > 1`),
+			Labels: map[string]string{"team": "a"}, Annotations: map[string]string{"summary": "$(NAME) is slow"}}},
+	}}
+
+	pr := &prometheusv1.PrometheusRule{}
+	CustomizePrometheusRule(pr, runtime)
+	rules := map[string]prometheusv1.Rule{}
+	for _, rule := range pr.Spec.Groups[0].Rules {
+		rules[rule.Alert] = rule
+	}
+	_, errorRateEnabled := rules["HighErrorRate"]
+
+	runtime.Spec.StatefulSet = &appstacksv1.RuntimeComponentStatefulSet{}
+	ssPR := &prometheusv1.PrometheusRule{}
+	CustomizePrometheusRule(ssPR, runtime)
+
+	runtime.Spec.CreateKnativeService = &createKNS
+	knativePR := &prometheusv1.PrometheusRule{}
+	CustomizePrometheusRule(knativePR, runtime)
+
+	testCPR := []Test{
+		{"rules", 4, len(pr.Spec.Groups[0].Rules)},
+		{"error rate disabled", false, errorRateEnabled},
+		{"replicas of the Deployment", true, strings.HasPrefix(rules["ReplicasUnavailable"].Expr.StrVal, `kube_deployment_status_replicas_unavailable{namespace="runtime",deployment="my-app"} >= 1`)},
+		{"default for", prometheusv1.Duration("10m"), *rules["ReplicasUnavailable"].For},
+		{"default severity", "warning", rules["ReplicasUnavailable"].Labels["severity"]},
+		{"restart threshold", `increase(kube_pod_container_status_restarts_total{namespace="runtime",pod=~"my-app-[a-z0-9]+-[a-z0-9]+"}[15m]) >= 10`, rules["RestartStorm"].Expr.StrVal},
+		{"restart severity", "critical", rules["RestartStorm"].Labels["severity"]},
+		{"restart for", forDuration, *rules["RestartStorm"].For},
+		{"certificate expiry", true, strings.Contains(rules["CertificateExpiry"].Expr.StrVal, `name=~"my-app-(svc|client|route)-tls-cm"`)},
+		{"component labels", name, rules["CertificateExpiry"].Labels["app_kubernetes_io_instance"]},
+		{"custom expression", `latency{namespace="runtime",pod=~"my-app-[a-z0-9]+-[a-z0-9]+"} > 1`, rules["SlowRequests"].Expr.StrVal},
+		{"custom annotation", "my-app is slow", rules["SlowRequests"].Annotations["summary"]},
+		{"custom label", "a", rules["SlowRequests"].Labels["team"]},
+		{"custom rule not modified", "$(NAME) is slow", runtime.Spec.Monitoring.Alerts.Rules[0].Annotations["summary"]},
+		{"replicas of the StatefulSet", true, strings.HasPrefix(ssPR.Spec.Groups[0].Rules[0].Expr.StrVal, `kube_statefulset_replicas{namespace="runtime",statefulset="my-app"}`)},
+		{"no replicas alert for Knative", "RestartStorm", knativePR.Spec.Groups[0].Rules[0].Alert},
+	}
+	verifyTests(testCPR, t)
+}
@@ -0,0 +1,144 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplicationReadiness reports the readiness of the components of an application
+type ApplicationReadiness struct {
+	// The number of ready components out of the total, such as 2/3
+	ReadyComponents string
+	Status          corev1.ConditionStatus
+	Reason          string
+	Message         string
+}
+
+// GetComponentReadiness returns the status of the Ready condition of the instance, or Unknown when it is not set
+func GetComponentReadiness(ba common.BaseComponent) corev1.ConditionStatus {
+	if ready := ba.GetStatus().GetCondition(common.StatusConditionTypeReady); ready != nil {
+		return ready.GetStatus()
+	}
+	return corev1.ConditionUnknown
+}
+
+// IsComponentPaused returns true if reconciliation of the instance is paused by its annotation
+func IsComponentPaused(ba common.BaseComponent) bool {
+	return ba.(metav1.Object).GetAnnotations()[GetPausedAnnotationName(ba)] == "true"
+}
+
+// GetApplicationReadiness aggregates the readiness of the components of an application
+func GetApplicationReadiness(applicationName string, components []common.BaseComponent) ApplicationReadiness {
+	notReady := []string{}
+	for _, component := range components {
+		if GetComponentReadiness(component) != corev1.ConditionTrue {
+			notReady = append(notReady, component.(metav1.Object).GetName())
+		}
+	}
+
+	readiness := ApplicationReadiness{ReadyComponents: fmt.Sprintf("%d/%d", len(components)-len(notReady), len(components))}
+	switch {
+	case len(components) == 0:
+		readiness.Status, readiness.Reason = corev1.ConditionFalse, "NoComponents"
+		readiness.Message = "No RuntimeComponent has the application name " + applicationName + "."
+	case len(notReady) > 0:
+		readiness.Status, readiness.Reason = corev1.ConditionFalse, "ComponentsNotReady"
+		readiness.Message = "Components are not ready: " + strings.Join(notReady, ", ") + "."
+	default:
+		readiness.Status = corev1.ConditionTrue
+		readiness.Message = "All components are ready."
+	}
+	return readiness
+}
+
+// PauseApplicationComponents pauses reconciliation of the components that are not already paused, and records the
+// application that paused them
+func (r *ReconcilerBase) PauseApplicationComponents(ctx context.Context, appName string, components []common.BaseComponent) error {
+	for _, component := range components {
+		if IsComponentPaused(component) {
+			continue
+		}
+		err := r.patchComponentAnnotations(ctx, component, map[string]string{
+			GetPausedAnnotationName(component):              "true",
+			GetPausedByApplicationAnnotationName(component): appName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResumeApplicationComponents resumes reconciliation of the components paused by the application. The components
+// paused by the user or by another application stay paused
+func (r *ReconcilerBase) ResumeApplicationComponents(ctx context.Context, appName string, components []common.BaseComponent) error {
+	for _, component := range components {
+		pausedByAnnotation := GetPausedByApplicationAnnotationName(component)
+		if component.(metav1.Object).GetAnnotations()[pausedByAnnotation] != appName {
+			continue
+		}
+		err := r.patchComponentAnnotations(ctx, component, map[string]string{
+			GetPausedAnnotationName(component): "",
+			pausedByAnnotation:                 "",
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestartApplicationComponents sets the restartedAt annotation of the components, which is added to their pods
+func (r *ReconcilerBase) RestartApplicationComponents(ctx context.Context, components []common.BaseComponent, restartedAt string) error {
+	for _, component := range components {
+		restartedAtAnnotation := GetRestartedAtAnnotationName(component)
+		if component.(metav1.Object).GetAnnotations()[restartedAtAnnotation] == restartedAt {
+			continue
+		}
+		if err := r.patchComponentAnnotations(ctx, component, map[string]string{restartedAtAnnotation: restartedAt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchComponentAnnotations sets the annotations of the instance. Annotations with an empty value are removed
+func (r *ReconcilerBase) patchComponentAnnotations(ctx context.Context, ba common.BaseComponent, annotations map[string]string) error {
+	obj := ba.(client.Object)
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	current := obj.GetAnnotations()
+	if current == nil {
+		current = map[string]string{}
+	}
+	for key, value := range annotations {
+		if value == "" {
+			delete(current, key)
+		} else {
+			current[key] = value
+		}
+	}
+	obj.SetAnnotations(current)
+	return r.GetClient().Patch(ctx, obj, patch)
+}
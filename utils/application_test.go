@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestGetApplicationReadiness(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ApplicationName: "shop"}
+	frontend, backend := createRuntimeComponent("frontend", namespace, spec), createRuntimeComponent("backend", namespace, spec)
+	frontend.Status.SetCondition(&appstacksv1.StatusCondition{Type: appstacksv1.StatusConditionTypeReady, Status: corev1.ConditionTrue})
+	backend.Annotations = map[string]string{GetPausedAnnotationName(backend): "true"}
+
+	none := GetApplicationReadiness("shop", nil)
+	notReady := GetApplicationReadiness("shop", []common.BaseComponent{backend, frontend})
+	backend.Status.SetCondition(&appstacksv1.StatusCondition{Type: appstacksv1.StatusConditionTypeReady, Status: corev1.ConditionFalse})
+	backendReadiness := GetComponentReadiness(backend)
+	backend.Status.SetCondition(&appstacksv1.StatusCondition{Type: appstacksv1.StatusConditionTypeReady, Status: corev1.ConditionTrue})
+	ready := GetApplicationReadiness("shop", []common.BaseComponent{backend, frontend})
+
+	testGAR := []Test{
+		{"no components", corev1.ConditionFalse, none.Status},
+		{"no components reason", "NoComponents", none.Reason},
+		{"no components message", "No RuntimeComponent has the application name shop.", none.Message},
+		{"no ready components", "0/0", none.ReadyComponents},
+		{"unknown readiness", corev1.ConditionUnknown, GetComponentReadiness(createRuntimeComponent(name, namespace, spec))},
+		{"component paused", true, IsComponentPaused(backend)},
+		{"component not paused", false, IsComponentPaused(frontend)},
+		{"components not ready", corev1.ConditionFalse, notReady.Status},
+		{"components not ready reason", "ComponentsNotReady", notReady.Reason},
+		{"components not ready message", "Components are not ready: backend.", notReady.Message},
+		{"ready components", "1/2", notReady.ReadyComponents},
+		{"component not ready", corev1.ConditionFalse, backendReadiness},
+		{"all components ready", corev1.ConditionTrue, ready.Status},
+		{"all components ready reason", "", ready.Reason},
+		{"all components ready message", "All components are ready.", ready.Message},
+		{"all ready components", "2/2", ready.ReadyComponents},
+	}
+	verifyTests(testGAR, t)
+}
+
+func TestApplicationComponentAnnotations(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ApplicationName: "shop"}
+	frontend, backend, other := createRuntimeComponent("frontend", namespace, spec), createRuntimeComponent("backend", namespace, spec),
+		createRuntimeComponent("other", namespace, spec)
+	// Paused by the user before the application, so it is not resumed by the application
+	backend.Annotations = map[string]string{GetPausedAnnotationName(backend): "true"}
+	// Paused by another application
+	other.Annotations = map[string]string{GetPausedAnnotationName(other): "true", GetPausedByApplicationAnnotationName(other): "other-app"}
+	objs, s := []runtime.Object{frontend, backend, other}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, frontend)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	components := []common.BaseComponent{backend, frontend, other}
+
+	getAnnotations := func(ba *appstacksv1.RuntimeComponent) map[string]string {
+		current := &appstacksv1.RuntimeComponent{}
+		cl.Get(context.TODO(), client.ObjectKeyFromObject(ba), current)
+		return current.Annotations
+	}
+	pausedAnnotation, pausedByAnnotation := GetPausedAnnotationName(frontend), GetPausedByApplicationAnnotationName(frontend)
+	restartedAtAnnotation := GetRestartedAtAnnotationName(frontend)
+
+	pauseErr := r.PauseApplicationComponents(context.TODO(), "shop", components)
+	pausedFrontend, pausedBackend, pausedOther := getAnnotations(frontend), getAnnotations(backend), getAnnotations(other)
+
+	restartErr := r.RestartApplicationComponents(context.TODO(), components, "2026-10-18T10:00:00Z")
+	restarted := getAnnotations(frontend)
+	// The components that are already restarted are not patched
+	frontend.Annotations[restartedAtAnnotation] = "stale"
+	unchangedErr := r.RestartApplicationComponents(context.TODO(), []common.BaseComponent{frontend}, "stale")
+
+	resumeErr := r.ResumeApplicationComponents(context.TODO(), "shop", components)
+	resumedFrontend, resumedBackend, resumedOther := getAnnotations(frontend), getAnnotations(backend), getAnnotations(other)
+
+	testACA := []Test{
+		{"no pause error", nil, pauseErr},
+		{"component paused", "true", pausedFrontend[pausedAnnotation]},
+		{"component paused by application", "shop", pausedFrontend[pausedByAnnotation]},
+		{"paused component kept", "", pausedBackend[pausedByAnnotation]},
+		{"component paused by other application kept", "other-app", pausedOther[pausedByAnnotation]},
+		{"no restart error", nil, restartErr},
+		{"component restarted", "2026-10-18T10:00:00Z", restarted[restartedAtAnnotation]},
+		{"paused annotation kept on restart", "true", restarted[pausedAnnotation]},
+		{"no error on unchanged restart", nil, unchangedErr},
+		{"unchanged component not patched", "2026-10-18T10:00:00Z", getAnnotations(frontend)[restartedAtAnnotation]},
+		{"no resume error", nil, resumeErr},
+		{"component resumed", "", resumedFrontend[pausedAnnotation]},
+		{"paused by annotation removed", "", resumedFrontend[pausedByAnnotation]},
+		{"component paused by the user not resumed", "true", resumedBackend[pausedAnnotation]},
+		{"component paused by other application not resumed", "true", resumedOther[pausedAnnotation]},
+		{"restarted annotation kept on resume", "2026-10-18T10:00:00Z", resumedFrontend[restartedAtAnnotation]},
+	}
+	verifyTests(testACA, t)
+}
@@ -0,0 +1,252 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultCMCACleanupGracePeriod is used when certManagerCACleanupGracePeriod is not a valid duration
+const defaultCMCACleanupGracePeriod = 24 * time.Hour
+
+// UsesOperatorCA returns true if the service certificate of the instance may be issued by the operator CA of the
+// namespace, or if the CA bundle is injected in its pods
+func UsesOperatorCA(ba common.BaseComponent) bool {
+	if IsCABundleInjected(ba) {
+		return true
+	}
+	if !shouldGenerateSvcCertificate(ba) {
+		return false
+	}
+	return ba.GetService() == nil || ba.GetService().GetCertificate() == nil || ba.GetService().GetCertificate().GetIssuerRef() == nil
+}
+
+// CleanupOperatorCA deletes the self-signed Issuer, the CA Certificate and its secret, the CA Issuer and the CA bundle
+// ConfigMap created by GenerateCMIssuer in the namespace, once no instance and no Certificate has used the CA Issuer
+// for the certManagerCACleanupGracePeriod of the operator ConfigMap. The resources are kept if the CA Issuer has the
+// <group>/retain-ca annotation set to true, or if IsOperatorCAInUse is not set. It returns the time left before the
+// resources are deleted, or 0.
+func (p *ReconcilePipeline) CleanupOperatorCA(namespace string, group string) (time.Duration, error) {
+	if p.IsOperatorCAInUse == nil {
+		return 0, nil
+	}
+	if ok, err := p.r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Issuer"); err != nil || !ok {
+		return 0, err
+	}
+	issuer := &certmanagerv1.Issuer{}
+	err := p.r.GetClient().Get(context.TODO(), client.ObjectKey{Name: p.prefix + "-ca-issuer", Namespace: namespace}, issuer)
+	if err != nil || issuer.Labels["app.kubernetes.io/managed-by"] != p.operatorName || issuer.Annotations[group+"/retain-ca"] == "true" {
+		return 0, client.IgnoreNotFound(err)
+	}
+
+	inUse, err := p.IsOperatorCAInUse(namespace)
+	if err != nil {
+		return 0, err
+	}
+	if !inUse {
+		if inUse, err = p.isCAIssuerReferenced(issuer); err != nil {
+			return 0, err
+		}
+	}
+	unusedSinceKey := group + "/ca-unused-since"
+	if inUse {
+		if _, ok := issuer.Annotations[unusedSinceKey]; ok {
+			delete(issuer.Annotations, unusedSinceKey)
+			return 0, p.r.GetClient().Update(context.TODO(), issuer)
+		}
+		return 0, nil
+	}
+
+	gracePeriod, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCMCACleanupGracePeriod))
+	if err != nil {
+		gracePeriod = defaultCMCACleanupGracePeriod
+	}
+	unusedSince, err := time.Parse(time.RFC3339, issuer.Annotations[unusedSinceKey])
+	if err != nil {
+		unusedSince = time.Now()
+		if gracePeriod > 0 {
+			issuer.Annotations = MergeMaps(issuer.Annotations, map[string]string{unusedSinceKey: unusedSince.UTC().Format(time.RFC3339)})
+			log.Info("The operator CA is no longer used", "namespace", namespace, "gracePeriod", gracePeriod.String())
+			return gracePeriod, p.r.GetClient().Update(context.TODO(), issuer)
+		}
+	}
+	if remaining := time.Until(unusedSince.Add(gracePeriod)); remaining > 0 {
+		return remaining, nil
+	}
+
+	log.Info("Deleting the operator CA", "namespace", namespace)
+	for _, obj := range []client.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: GetCABundleName(p.prefix), Namespace: namespace}},
+		issuer,
+		&certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: p.prefix + "-ca-cert", Namespace: namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: p.prefix + "-ca-tls", Namespace: namespace}},
+		&certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: p.prefix + "-self-signed", Namespace: namespace}},
+	} {
+		if err := p.r.GetClient().Delete(context.TODO(), obj); err != nil && !kerrors.IsNotFound(err) {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// CleanupOperatorCAs runs CleanupOperatorCA for the CA Issuers of the operator in the watched namespaces, and returns the
+// time left before the next one is deleted, or 0. The last instance that used a CA may have been deleted while the
+// operator was not running
+func (p *ReconcilePipeline) CleanupOperatorCAs(group string) (time.Duration, error) {
+	if p.IsOperatorCAInUse == nil {
+		return 0, nil
+	}
+	if ok, err := p.r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Issuer"); err != nil || !ok {
+		return 0, err
+	}
+	issuers := &certmanagerv1.IssuerList{}
+	if err := p.r.GetClient().List(context.TODO(), issuers, client.MatchingLabels{"app.kubernetes.io/managed-by": p.operatorName}); err != nil {
+		return 0, err
+	}
+	var next time.Duration
+	for i := range issuers.Items {
+		if issuers.Items[i].Name != p.prefix+"-ca-issuer" {
+			continue
+		}
+		remaining, err := p.CleanupOperatorCA(issuers.Items[i].Namespace, group)
+		if err != nil {
+			return 0, err
+		}
+		if remaining > 0 && (next == 0 || remaining < next) {
+			next = remaining
+		}
+	}
+	return next, nil
+}
+
+// OperatorCACleanup checks the operator CAs of the watched namespaces when the operator starts, and once the grace
+// period of an unused CA is over. The start of the grace period is kept in the <group>/ca-unused-since annotation of
+// the CA Issuer, so that a restart of the operator does not delay the deletion of the CA
+type OperatorCACleanup struct {
+	pipeline *ReconcilePipeline
+	group    string
+
+	mu   sync.Mutex
+	next time.Time
+	wake chan struct{}
+}
+
+// NewOperatorCACleanup returns an OperatorCACleanup that cleans up the operator CAs of the pipeline, see CleanupOperatorCAs.
+// It is started by the manager
+func NewOperatorCACleanup(p *ReconcilePipeline, group string) *OperatorCACleanup {
+	return &OperatorCACleanup{pipeline: p, group: group, wake: make(chan struct{}, 1)}
+}
+
+// Schedule checks the operator CAs again after the given time, unless a check is already scheduled before it
+func (c *OperatorCACleanup) Schedule(after time.Duration) {
+	c.mu.Lock()
+	if at := time.Now().Add(after); c.next.IsZero() || at.Before(c.next) {
+		c.next = at
+	}
+	c.mu.Unlock()
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start checks the operator CAs until the context is done
+func (c *OperatorCACleanup) Start(ctx context.Context) error {
+	c.Schedule(0)
+	for {
+		c.mu.Lock()
+		next := c.next
+		c.mu.Unlock()
+		var timer <-chan time.Time
+		if !next.IsZero() {
+			timer = time.After(time.Until(next))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.wake:
+			continue
+		case <-timer:
+		}
+
+		c.mu.Lock()
+		c.next = time.Time{}
+		c.mu.Unlock()
+		remaining, err := c.pipeline.CleanupOperatorCAs(c.group)
+		if err != nil {
+			log.Error(err, "Failed to clean up the operator CAs")
+			remaining = time.Minute
+		}
+		if remaining > 0 {
+			c.Schedule(remaining)
+		}
+	}
+}
+
+// isCAIssuerReferenced returns true if a Certificate of the namespace, including those of other workloads, is issued
+// by the CA Issuer. The Certificates that wait for the garbage collector after their owner is deleted are ignored
+func (p *ReconcilePipeline) isCAIssuerReferenced(issuer *certmanagerv1.Issuer) (bool, error) {
+	certs := &certmanagerv1.CertificateList{}
+	if err := p.r.GetClient().List(context.TODO(), certs, client.InNamespace(issuer.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range certs.Items {
+		ref := certs.Items[i].Spec.IssuerRef
+		if ref.Name != issuer.Name || (ref.Kind != "" && ref.Kind != "Issuer") || (ref.Group != "" && ref.Group != certmanagerv1.SchemeGroupVersion.Group) {
+			continue
+		}
+		deleted, err := p.isCertificateOwnerDeleted(&certs.Items[i])
+		if err != nil {
+			return false, err
+		}
+		if !deleted {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isCertificateOwnerDeleted returns true if the Certificate, or the owner that controls it, is being deleted or is gone
+func (p *ReconcilePipeline) isCertificateOwnerDeleted(cert *certmanagerv1.Certificate) (bool, error) {
+	if !cert.DeletionTimestamp.IsZero() {
+		return true, nil
+	}
+	ref := metav1.GetControllerOf(cert)
+	if ref == nil {
+		return false, nil
+	}
+	owner := &metav1.PartialObjectMetadata{}
+	owner.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	err := p.r.GetAPIReader().Get(context.TODO(), client.ObjectKey{Name: ref.Name, Namespace: cert.Namespace}, owner)
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return owner.UID != ref.UID || !owner.DeletionTimestamp.IsZero(), nil
+}
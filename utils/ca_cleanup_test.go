@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certmanagermetav1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestCleanupOperatorCA(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	managedBy := map[string]string{"app.kubernetes.io/managed-by": "runtime-component-operator"}
+	issuer := &certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-issuer", Namespace: namespace, Labels: managedBy}}
+	objs, s := []runtime.Object{
+		issuer,
+		&certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "rco-self-signed", Namespace: namespace, Labels: managedBy}},
+		&certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-cert", Namespace: namespace, Labels: managedBy}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-tls", Namespace: namespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-bundle", Namespace: namespace, Labels: managedBy}},
+	}, scheme.Scheme
+	certmanagerv1.AddToScheme(s)
+	s.AddKnownTypes(appstacksv1.GroupVersion, &appstacksv1.RuntimeComponent{})
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	dc := createFakeDiscoveryClient().(*fakediscovery.FakeDiscovery)
+	dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+		GroupVersion: certmanagerv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "issuers", Namespaced: true, Kind: "Issuer"}},
+	})
+	r.SetDiscoveryClient(dc)
+
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	noHookRemaining, _ := p.CleanupOperatorCA(namespace, "rc.app.stacks")
+	inUse := true
+	p.IsOperatorCAInUse = func(ns string) (bool, error) { return inUse, nil }
+	getIssuer := func() *certmanagerv1.Issuer {
+		cl.Get(context.TODO(), client.ObjectKeyFromObject(issuer), issuer)
+		return issuer
+	}
+
+	usedRemaining, _ := p.CleanupOperatorCA(namespace, "rc.app.stacks")
+	inUse = false
+	unusedRemaining, unusedErr := p.CleanupOperatorCA(namespace, "rc.app.stacks")
+	_, unusedSinceSet := getIssuer().Annotations["rc.app.stacks/ca-unused-since"]
+
+	// A Certificate of another workload still uses the CA Issuer
+	otherCert := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: "other-cert", Namespace: namespace},
+		Spec: certmanagerv1.CertificateSpec{IssuerRef: certmanagermetav1.ObjectReference{Name: "rco-ca-issuer"}}}
+	cl.Create(context.TODO(), otherCert)
+	p.CleanupOperatorCA(namespace, "rc.app.stacks")
+	_, unusedSinceKept := getIssuer().Annotations["rc.app.stacks/ca-unused-since"]
+	cl.Delete(context.TODO(), otherCert)
+
+	// The Certificate of a deleted instance waits for the garbage collector
+	deletedInstance := createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage})
+	deletedInstance.UID = "deleted-uid"
+	deletedCert := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: "my-app-svc-crt", Namespace: namespace},
+		Spec: certmanagerv1.CertificateSpec{IssuerRef: certmanagermetav1.ObjectReference{Name: "rco-ca-issuer"}}}
+	controllerutil.SetControllerReference(deletedInstance, deletedCert, s)
+	cl.Create(context.TODO(), deletedCert)
+	delete(getIssuer().Annotations, "rc.app.stacks/ca-unused-since")
+	cl.Update(context.TODO(), issuer)
+	deletedOwnerRemaining, deletedOwnerErr := p.CleanupOperatorCA(namespace, "rc.app.stacks")
+
+	// The owner of the Certificate still exists
+	cl.Create(context.TODO(), deletedInstance)
+	delete(getIssuer().Annotations, "rc.app.stacks/ca-unused-since")
+	cl.Update(context.TODO(), issuer)
+	p.CleanupOperatorCA(namespace, "rc.app.stacks")
+	_, ownerUnusedSince := getIssuer().Annotations["rc.app.stacks/ca-unused-since"]
+	cl.Delete(context.TODO(), deletedCert)
+
+	common.Config.Store(common.OpConfigCMCACleanupGracePeriod, "0")
+	issuer.Annotations = map[string]string{"rc.app.stacks/retain-ca": "true"}
+	cl.Update(context.TODO(), issuer)
+	p.CleanupOperatorCA(namespace, "rc.app.stacks")
+	retainErr := cl.Get(context.TODO(), client.ObjectKeyFromObject(issuer), &certmanagerv1.Issuer{})
+
+	delete(getIssuer().Annotations, "rc.app.stacks/retain-ca")
+	cl.Update(context.TODO(), issuer)
+	cleanupErr := func() error { _, err := p.CleanupOperatorCA(namespace, "rc.app.stacks"); return err }()
+	issuerErr := cl.Get(context.TODO(), client.ObjectKey{Name: "rco-ca-issuer", Namespace: namespace}, &certmanagerv1.Issuer{})
+	selfSignedErr := cl.Get(context.TODO(), client.ObjectKey{Name: "rco-self-signed", Namespace: namespace}, &certmanagerv1.Issuer{})
+	caCertErr := cl.Get(context.TODO(), client.ObjectKey{Name: "rco-ca-cert", Namespace: namespace}, &certmanagerv1.Certificate{})
+	caSecretErr := cl.Get(context.TODO(), client.ObjectKey{Name: "rco-ca-tls", Namespace: namespace}, &corev1.Secret{})
+	bundleErr := cl.Get(context.TODO(), client.ObjectKey{Name: "rco-ca-bundle", Namespace: namespace}, &corev1.ConfigMap{})
+
+	testCOCA := []Test{
+		{"no cleanup without hook", time.Duration(0), noHookRemaining},
+		{"in use", time.Duration(0), usedRemaining},
+		{"grace period", 24 * time.Hour, unusedRemaining},
+		{"no error", nil, unusedErr},
+		{"unused since", true, unusedSinceSet},
+		{"used by another Certificate", false, unusedSinceKept},
+		{"no error with the Certificate of a deleted instance", nil, deletedOwnerErr},
+		{"Certificate of a deleted instance ignored", 24 * time.Hour, deletedOwnerRemaining},
+		{"used by the Certificate of an instance", false, ownerUnusedSince},
+		{"retained", nil, retainErr},
+		{"no cleanup error", nil, cleanupErr},
+		{"CA issuer deleted", true, kerrors.IsNotFound(issuerErr)},
+		{"self-signed issuer deleted", true, kerrors.IsNotFound(selfSignedErr)},
+		{"CA certificate deleted", true, kerrors.IsNotFound(caCertErr)},
+		{"CA secret deleted", true, kerrors.IsNotFound(caSecretErr)},
+		{"CA bundle deleted", true, kerrors.IsNotFound(bundleErr)},
+	}
+	verifyTests(testCOCA, t)
+	common.Config = common.DefaultOpConfig()
+}
+
+func TestUsesOperatorCA(t *testing.T) {
+	issuerKind, manageTLS, inject := "ClusterIssuer", false, true
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: &appstacksv1.RuntimeComponentService{Port: 9443}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	defaultCA := UsesOperatorCA(runtimecomponent)
+
+	runtimecomponent.Spec.Service.Certificate = &appstacksv1.RuntimeComponentCertificate{
+		IssuerRef: &appstacksv1.RuntimeComponentIssuerReference{Name: "internal-ca", Kind: &issuerKind}}
+	issuerRef := UsesOperatorCA(runtimecomponent)
+
+	runtimecomponent.Spec.ManageTLS = &manageTLS
+	noTLS := UsesOperatorCA(runtimecomponent)
+
+	runtimecomponent.Spec.Trust = &appstacksv1.RuntimeComponentTrust{InjectCABundle: &inject}
+	caBundle := UsesOperatorCA(runtimecomponent)
+
+	testUOCA := []Test{
+		{"operator CA by default", true, defaultCA},
+		{"issuer of the certificate", false, issuerRef},
+		{"TLS not managed", false, noTLS},
+		{"CA bundle injected", true, caBundle},
+	}
+	verifyTests(testUOCA, t)
+}
+
+func TestOperatorCACleanup(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	// The CA of the namespace was unused for longer than the grace period when the operator stopped
+	managedBy := map[string]string{"app.kubernetes.io/managed-by": "runtime-component-operator"}
+	unusedSince := map[string]string{"rc.app.stacks/ca-unused-since": time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)}
+	issuer := &certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-issuer", Namespace: namespace, Labels: managedBy, Annotations: unusedSince}}
+	otherIssuer := &certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-issuer", Namespace: "other", Labels: managedBy}}
+	selfSigned := &certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "rco-self-signed", Namespace: "other", Labels: managedBy}}
+	objs, s := []runtime.Object{issuer, otherIssuer, selfSigned}, scheme.Scheme
+	certmanagerv1.AddToScheme(s)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	dc := createFakeDiscoveryClient().(*fakediscovery.FakeDiscovery)
+	dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+		GroupVersion: certmanagerv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "issuers", Namespaced: true, Kind: "Issuer"}},
+	})
+	r.SetDiscoveryClient(dc)
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	p.IsOperatorCAInUse = func(ns string) (bool, error) { return false, nil }
+
+	// The CAs are checked when the cleanup starts
+	cleanup := NewOperatorCACleanup(p, "rc.app.stacks")
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error)
+	go func() { done <- cleanup.Start(ctx) }()
+	issuerErr := cl.Get(context.TODO(), client.ObjectKeyFromObject(issuer), &certmanagerv1.Issuer{})
+	for deadline := time.Now().Add(5 * time.Second); !kerrors.IsNotFound(issuerErr) && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		issuerErr = cl.Get(context.TODO(), client.ObjectKeyFromObject(issuer), &certmanagerv1.Issuer{})
+	}
+	cancel()
+	startErr := <-done
+
+	remaining, cleanupErr := p.CleanupOperatorCAs("rc.app.stacks")
+	cl.Get(context.TODO(), client.ObjectKeyFromObject(otherIssuer), otherIssuer)
+	_, otherUnusedSince := otherIssuer.Annotations["rc.app.stacks/ca-unused-since"]
+
+	// The operator CA is only checked when the instance stops using it
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: &appstacksv1.RuntimeComponentService{Port: 9443}}
+	runtimecomponent := createRuntimeComponent(name, "other", spec)
+	var scheduled time.Duration
+	p.ScheduleOperatorCACleanup = func(after time.Duration) { scheduled = after }
+	p.CertificateProviders = nil
+	state := &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: name, Namespace: "other"}}
+	cl.Delete(context.TODO(), otherIssuer)
+	otherIssuer.ResourceVersion = ""
+	otherIssuer.Annotations = nil
+	cl.Create(context.TODO(), otherIssuer)
+	usedErr := p.reconcileCertificates(runtimecomponent, state)
+	usedReference := runtimecomponent.Status.References[common.StatusReferenceOperatorCAIssuer]
+	usedScheduled := scheduled
+
+	manageTLS := false
+	runtimecomponent.Spec.ManageTLS = &manageTLS
+	unusedErr := p.reconcileCertificates(runtimecomponent, state)
+	_, referenceKept := runtimecomponent.Status.References[common.StatusReferenceOperatorCAIssuer]
+
+	testOCAC := []Test{
+		{"start stopped", nil, startErr},
+		{"CA unused before the start deleted", true, kerrors.IsNotFound(issuerErr)},
+		{"no cleanup error", nil, cleanupErr},
+		{"grace period of the other CA", true, remaining > 23*time.Hour && remaining <= 24*time.Hour},
+		{"other CA unused since", true, otherUnusedSince},
+		{"no error when the CA is used", nil, usedErr},
+		{"CA Issuer reference", "rco-ca-issuer", usedReference},
+		{"not checked when used", time.Duration(0), usedScheduled},
+		{"no error when the CA is no longer used", nil, unusedErr},
+		{"checked once no longer used", 24 * time.Hour, scheduled},
+		{"CA Issuer reference removed", false, referenceKept},
+	}
+	verifyTests(testOCAC, t)
+	common.Config = common.DefaultOpConfig()
+}
@@ -0,0 +1,190 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultCertificateExpiryWarningThreshold is used when certificateExpiryWarningThreshold is not a valid duration
+const defaultCertificateExpiryWarningThreshold = 720 * time.Hour
+
+// reconcileCertificateExpiry checks the certificates mounted or referenced by the instance and reports the one that
+// expires first in the status. A Warning event is recorded when it enters the certificateExpiryWarningThreshold of
+// the operator ConfigMap, and when it expires. An error is returned when a certificate does not match its private key or its chain is invalid
+func (p *ReconcilePipeline) reconcileCertificateExpiry(ba common.BaseComponent, state *ReconcileState) error {
+	var expirySecret string
+	var expiry time.Time
+	for _, secretName := range p.getCertificateSecretNames(ba, state) {
+		secret := &corev1.Secret{}
+		err := p.r.GetClient().Get(state.Context, client.ObjectKey{Name: secretName, Namespace: state.DefaultMeta.Namespace}, secret)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		notAfter, err := CheckCertificateSecret(secret)
+		if err != nil {
+			return fmt.Errorf("the certificate in secret %s is not valid: %w", secretName, err)
+		}
+		if !notAfter.IsZero() && (expiry.IsZero() || notAfter.Before(expiry)) {
+			expirySecret, expiry = secretName, notAfter
+		}
+	}
+
+	s := ba.GetStatus()
+	if expiry.IsZero() {
+		s.UnsetCertificateExpiry()
+		return nil
+	}
+	s.SetCertificateExpiry(expirySecret, metav1.NewTime(expiry))
+	// The event is recorded once when the certificate enters the threshold, and once when it expires. The time of the
+	// last event is reset when the certificate changes
+	now := time.Now()
+	if isCertificateExpiring(expiry, now) {
+		lastWarning := s.GetCertificateExpiry().GetLastWarningTime()
+		if lastWarning == nil || (lastWarning.Time.Before(expiry) && !now.Before(expiry)) {
+			p.r.GetRecorder().Event(ba.(client.Object), "Warning", "CertificateExpiring", getCertificateExpiryMessage(ba))
+			s.SetCertificateExpiryWarningTime(metav1.NewTime(now))
+		}
+	}
+	return nil
+}
+
+// getCertificateSecretNames returns the secrets of the service, client and route certificates of the instance,
+// and of the operator CA and the custom CA of the namespace when they issue the service certificate or the CA bundle
+func (p *ReconcilePipeline) getCertificateSecretNames(ba common.BaseComponent, state *ReconcileState) []string {
+	candidates := []string{
+		ba.GetStatus().GetReferences()[common.StatusReferenceCertSecretName],
+		ba.GetStatus().GetReferences()[common.StatusReferenceClientCertSecretName],
+	}
+	if ba.GetExpose() != nil && *ba.GetExpose() {
+		candidates = append(candidates, GetRouteCertificateSecretName(ba))
+	}
+	var names []string
+	for _, name := range candidates {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	usesOperatorCA := state.UseCertManager && (ba.GetService() == nil || ba.GetService().GetCertificate() == nil || ba.GetService().GetCertificate().GetIssuerRef() == nil)
+	if usesOperatorCA || IsCABundleInjected(ba) {
+		names = append(names, p.prefix+"-ca-tls", p.prefix+"-custom-ca-tls")
+	}
+	return names
+}
+
+// CheckCertificateSecret parses the tls.crt and ca.crt keys of a secret and returns the earliest expiry of their
+// certificates, or the zero time if the secret has no certificate. It returns an error if the private key in
+// tls.key does not match the certificate, or if a certificate of the chain is not signed by the next one
+func CheckCertificateSecret(secret *corev1.Secret) (time.Time, error) {
+	chain, err := parseCertificates(secret.Data["tls.crt"])
+	if err != nil || len(chain) == 0 {
+		return time.Time{}, err
+	}
+	if len(secret.Data["tls.key"]) > 0 {
+		if _, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"]); err != nil {
+			return time.Time{}, fmt.Errorf("the private key does not match the certificate: %w", err)
+		}
+	}
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return time.Time{}, fmt.Errorf("the certificate %q of the chain is not signed by %q: %w", chain[i].Subject.CommonName, chain[i+1].Subject.CommonName, err)
+		}
+	}
+	cas, err := parseCertificates(secret.Data["ca.crt"])
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(cas) > 0 && !isSignedByCA(chain[len(chain)-1], cas) {
+		return time.Time{}, fmt.Errorf("the certificate %q is not signed by a CA of ca.crt", chain[len(chain)-1].Subject.CommonName)
+	}
+
+	expiry := chain[0].NotAfter
+	for _, crt := range append(chain, cas...) {
+		if crt.NotAfter.Before(expiry) {
+			expiry = crt.NotAfter
+		}
+	}
+	return expiry, nil
+}
+
+// parseCertificates returns the certificates of a PEM bundle
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var crts []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		crt, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		crts = append(crts, crt)
+	}
+	return crts, nil
+}
+
+// isSignedByCA returns true if the certificate is one of the CAs or is signed by one of them
+func isSignedByCA(crt *x509.Certificate, cas []*x509.Certificate) bool {
+	for _, ca := range cas {
+		if crt.Equal(ca) || crt.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getCertificateExpiryWarningThreshold returns certificateExpiryWarningThreshold of the operator ConfigMap
+func getCertificateExpiryWarningThreshold() time.Duration {
+	threshold, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCertificateExpiryWarningThreshold))
+	if err != nil {
+		return defaultCertificateExpiryWarningThreshold
+	}
+	return threshold
+}
+
+// isCertificateExpiring returns true if a certificate that expires at notAfter is within the warning threshold
+func isCertificateExpiring(notAfter time.Time, now time.Time) bool {
+	threshold := getCertificateExpiryWarningThreshold()
+	return threshold > 0 && now.Add(threshold).After(notAfter)
+}
+
+// getCertificateExpiryMessage returns the message of the Warning condition of the certificate that expires first
+func getCertificateExpiryMessage(ba common.BaseComponent) string {
+	e := ba.GetStatus().GetCertificateExpiry()
+	if e == nil {
+		return ""
+	}
+	notAfter := e.GetNotAfter().UTC().Format(time.RFC3339)
+	if !time.Now().Before(e.GetNotAfter().Time) {
+		return fmt.Sprintf("The certificate in secret %s expired on %s", e.GetSecretName(), notAfter)
+	}
+	return fmt.Sprintf("The certificate in secret %s expires on %s", e.GetSecretName(), notAfter)
+}
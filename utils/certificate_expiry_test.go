@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestCheckCertificateSecret(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	now := time.Now()
+	caPEM, caKey, ca := createTestCertificate(t, "Test CA", now.Add(90*24*time.Hour), nil, nil)
+	crtPEM, keyPEM, _ := createTestCertificate(t, "my-app", now.Add(10*24*time.Hour), ca, caKey)
+	otherCAPEM, _, _ := createTestCertificate(t, "Other CA", now.Add(90*24*time.Hour), nil, nil)
+	_, otherKeyPEM, _ := createTestCertificate(t, "other-app", now.Add(10*24*time.Hour), ca, caKey)
+
+	expiry, err := CheckCertificateSecret(&corev1.Secret{Data: map[string][]byte{"tls.crt": crtPEM, "tls.key": keyPEM, "ca.crt": caPEM}})
+	chainExpiry, chainErr := CheckCertificateSecret(&corev1.Secret{Data: map[string][]byte{"tls.crt": append(append([]byte{}, crtPEM...), caPEM...)}})
+	_, keyErr := CheckCertificateSecret(&corev1.Secret{Data: map[string][]byte{"tls.crt": crtPEM, "tls.key": otherKeyPEM}})
+	_, caErr := CheckCertificateSecret(&corev1.Secret{Data: map[string][]byte{"tls.crt": crtPEM, "ca.crt": otherCAPEM}})
+	_, orderErr := CheckCertificateSecret(&corev1.Secret{Data: map[string][]byte{"tls.crt": append(append([]byte{}, caPEM...), crtPEM...)}})
+	emptyExpiry, emptyErr := CheckCertificateSecret(&corev1.Secret{Data: map[string][]byte{"ca.crt": caPEM}})
+
+	testCCS := []Test{
+		{"no error", nil, err},
+		{"earliest expiry", now.Add(10 * 24 * time.Hour).Truncate(time.Second).UTC(), expiry.UTC()},
+		{"chain without error", nil, chainErr},
+		{"chain expiry", expiry, chainExpiry},
+		{"key mismatch", true, keyErr != nil && strings.Contains(keyErr.Error(), "private key does not match")},
+		{"unknown CA", true, caErr != nil && strings.Contains(caErr.Error(), "not signed by a CA of ca.crt")},
+		{"invalid chain", true, orderErr != nil && strings.Contains(orderErr.Error(), "of the chain is not signed")},
+		{"no certificate", true, emptyExpiry.IsZero()},
+		{"no certificate error", nil, emptyErr},
+	}
+	verifyTests(testCCS, t)
+}
+
+func TestReconcileCertificateExpiry(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	certSecretRef := "my-app-tls"
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: &appstacksv1.RuntimeComponentService{Port: 9443, CertificateSecretRef: &certSecretRef}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.Status.SetReference(common.StatusReferenceCertSecretName, certSecretRef)
+	notAfter := time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second)
+	crtPEM, keyPEM, _ := createTestCertificate(t, "my-app", notAfter, nil, nil)
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: certSecretRef, Namespace: namespace},
+		Data: map[string][]byte{"tls.crt": crtPEM, "tls.key": keyPEM}}
+	objs, s := []runtime.Object{runtimecomponent, secret}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	recorder := record.NewFakeRecorder(10)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, recorder)
+
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	state := &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	err := p.reconcileCertificateExpiry(runtimecomponent, state)
+	expiry := runtimecomponent.Status.CertificateExpiry
+	addStatusWarnings(runtimecomponent, getDefaultWarnings())
+	warningMessage := runtimecomponent.Status.GetCondition(common.StatusConditionTypeWarning).GetMessage()
+	event := <-recorder.Events
+
+	// The event was already recorded for the certificate, even though another warning replaces the Warning condition
+	runtimecomponent.Status.SetCondition(&appstacksv1.StatusCondition{Type: appstacksv1.StatusConditionTypeWarning, Status: corev1.ConditionTrue, Message: "other warning"})
+	p.reconcileCertificateExpiry(runtimecomponent, state)
+	repeatedEvents := len(recorder.Events)
+	lastWarningTime := runtimecomponent.Status.CertificateExpiry.LastWarningTime
+
+	// The certificate is renewed, and expires again later
+	renewedNotAfter := notAfter.Add(24 * time.Hour)
+	renewedCrtPEM, renewedKeyPEM, _ := createTestCertificate(t, "my-app", renewedNotAfter, nil, nil)
+	secret.Data = map[string][]byte{"tls.crt": renewedCrtPEM, "tls.key": renewedKeyPEM}
+	cl.Update(context.TODO(), secret)
+	p.reconcileCertificateExpiry(runtimecomponent, state)
+	renewedEvents := len(recorder.Events)
+	<-recorder.Events
+
+	// The certificate expired since the last event
+	expiredCrtPEM, expiredKeyPEM, _ := createTestCertificate(t, "my-app", time.Now().Add(-time.Hour).Truncate(time.Second), nil, nil)
+	secret.Data = map[string][]byte{"tls.crt": expiredCrtPEM, "tls.key": expiredKeyPEM}
+	cl.Update(context.TODO(), secret)
+	p.reconcileCertificateExpiry(runtimecomponent, state)
+	<-recorder.Events
+	runtimecomponent.Status.SetCertificateExpiryWarningTime(metav1.NewTime(time.Now().Add(-2 * time.Hour)))
+	p.reconcileCertificateExpiry(runtimecomponent, state)
+	expiredEvents := len(recorder.Events)
+	expiredEvent := <-recorder.Events
+	p.reconcileCertificateExpiry(runtimecomponent, state)
+	repeatedExpiredEvents := len(recorder.Events)
+
+	common.Config.Store(common.OpConfigCertificateExpiryWarningThreshold, "0")
+	addStatusWarnings(runtimecomponent, getDefaultWarnings())
+	disabledWarning := runtimecomponent.Status.GetCondition(common.StatusConditionTypeWarning)
+
+	// The operator CA of the namespace expires first when it issues the service certificate
+	common.Config = common.DefaultOpConfig()
+	secret.Data = map[string][]byte{"tls.crt": crtPEM, "tls.key": keyPEM}
+	cl.Update(context.TODO(), secret)
+	caNotAfter := time.Now().Add(5 * 24 * time.Hour).Truncate(time.Second)
+	caCrtPEM, caKeyPEM, _ := createTestCertificate(t, "rco-ca", caNotAfter, nil, nil)
+	caSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rco-ca-tls", Namespace: namespace},
+		Data: map[string][]byte{"tls.crt": caCrtPEM, "tls.key": caKeyPEM}}
+	cl.Create(context.TODO(), caSecret)
+	operatorCAErr := p.reconcileCertificateExpiry(runtimecomponent, &ReconcileState{Context: context.TODO(), DefaultMeta: state.DefaultMeta, UseCertManager: true})
+	caExpiry := runtimecomponent.Status.CertificateExpiry.DeepCopy()
+	cl.Delete(context.TODO(), caSecret)
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+
+	_, otherKeyPEM, _ := createTestCertificate(t, "other-app", notAfter, nil, nil)
+	secret.Data["tls.key"] = otherKeyPEM
+	cl.Update(context.TODO(), secret)
+	mismatchErr := p.reconcileCertificateExpiry(runtimecomponent, state)
+
+	testRCE := []Test{
+		{"no error", nil, err},
+		{"expiry secret", certSecretRef, expiry.SecretName},
+		{"expiry", notAfter.UTC(), expiry.NotAfter.UTC()},
+		{"warning message", "The certificate in secret my-app-tls expires on " + notAfter.UTC().Format(time.RFC3339), warningMessage},
+		{"warning event", true, strings.HasPrefix(event, "Warning CertificateExpiring")},
+		{"no repeated event", 0, repeatedEvents},
+		{"last warning time", true, lastWarningTime != nil},
+		{"event of the renewed certificate", 1, renewedEvents},
+		{"event once expired", 1, expiredEvents},
+		{"expired event", true, strings.Contains(expiredEvent, "expired on")},
+		{"no repeated event once expired", 0, repeatedExpiredEvents},
+		{"warning disabled", nil, disabledWarning},
+		{"no error with operator CA", nil, operatorCAErr},
+		{"operator CA expiry secret", "rco-ca-tls", caExpiry.SecretName},
+		{"operator CA expiry", caNotAfter.UTC(), caExpiry.NotAfter.UTC()},
+		{"key mismatch", true, mismatchErr != nil && strings.Contains(mismatchErr.Error(), "my-app-tls")},
+	}
+	verifyTests(testRCE, t)
+	common.Config = common.DefaultOpConfig()
+}
+
+// createTestCertificate returns a certificate that expires at notAfter, its private key and the parsed certificate.
+// The certificate is a self-signed CA if parent is nil
+func createTestCertificate(t *testing.T, cn string, notAfter time.Time, parent *x509.Certificate, parentKeyPEM []byte) ([]byte, []byte, *x509.Certificate) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var parentKey any = key
+	template := &x509.Certificate{SerialNumber: big.NewInt(time.Now().UnixNano()), Subject: pkix.Name{CommonName: cn},
+		DNSNames: []string{cn}, NotBefore: time.Now().Add(-time.Hour), NotAfter: notAfter}
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid, template.KeyUsage = true, true, x509.KeyUsageCertSign
+		parent = template
+	} else {
+		block, _ := pem.Decode(parentKeyPEM)
+		parentKey, _ = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crt, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), crt
+}
@@ -0,0 +1,89 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/application-stacks/runtime-component-operator/common"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// Names of the certificate providers
+const (
+	CertificateProviderCertManager = "cert-manager"
+	CertificateProviderOpenShift   = "OpenShift service CA"
+	CertificateProviderCSR         = "CertificateSigningRequest"
+)
+
+// CertificateProvider issues the service certificate of an instance into a secret with the tls.crt, tls.key and
+// ca.crt keys. The Certificates step of the ReconcilePipeline uses the first available provider
+type CertificateProvider interface {
+	// Name returns the name of the provider
+	Name() string
+	// IsAvailable returns true if the provider can issue service certificates in the cluster
+	IsAvailable(r *ReconcilerBase) (bool, error)
+	// ReconcileCertificate issues or renews the service certificate of the instance. It returns the name of the
+	// secret of the certificate, or "" if the secret is referenced later, as for the OpenShift service CA
+	ReconcileCertificate(r *ReconcilerBase, ba common.BaseComponent, state *ReconcileState) (string, error)
+	// DeleteCertificate deletes the resources created by the provider for the instance
+	DeleteCertificate(r *ReconcilerBase, ba common.BaseComponent) error
+}
+
+// CertManagerCertificateProvider issues service certificates with cert-manager, see GenerateSvcCertSecret
+type CertManagerCertificateProvider struct {
+	Prefix       string
+	CACommonName string
+	OperatorName string
+}
+
+func (p *CertManagerCertificateProvider) Name() string {
+	return CertificateProviderCertManager
+}
+
+func (p *CertManagerCertificateProvider) IsAvailable(r *ReconcilerBase) (bool, error) {
+	return r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Certificate")
+}
+
+func (p *CertManagerCertificateProvider) ReconcileCertificate(r *ReconcilerBase, ba common.BaseComponent, state *ReconcileState) (string, error) {
+	if _, err := r.GenerateSvcCertSecret(ba, p.Prefix, p.CACommonName, p.OperatorName); err != nil {
+		return "", err
+	}
+	return ba.GetStatus().GetReferences()[common.StatusReferenceCertSecretName], nil
+}
+
+func (p *CertManagerCertificateProvider) DeleteCertificate(r *ReconcilerBase, ba common.BaseComponent) error {
+	return r.deleteSvcCertificates(ba)
+}
+
+// OpenShiftCertificateProvider issues service certificates with the OpenShift service CA. The Service step
+// annotates the Service of the instance, see AddOCPCertAnnotation
+type OpenShiftCertificateProvider struct{}
+
+func (p *OpenShiftCertificateProvider) Name() string {
+	return CertificateProviderOpenShift
+}
+
+func (p *OpenShiftCertificateProvider) IsAvailable(r *ReconcilerBase) (bool, error) {
+	return r.IsOpenShift(), nil
+}
+
+func (p *OpenShiftCertificateProvider) ReconcileCertificate(r *ReconcilerBase, ba common.BaseComponent, state *ReconcileState) (string, error) {
+	return "", nil
+}
+
+func (p *OpenShiftCertificateProvider) DeleteCertificate(r *ReconcilerBase, ba common.BaseComponent) error {
+	return nil
+}
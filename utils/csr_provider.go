@@ -0,0 +1,361 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// csrPendingKey is the key of the private key of a pending CertificateSigningRequest in the pending secret, which
+	// is not mounted in the pods of the instance
+	csrPendingKey = "pending.key"
+	// csrMinExpirationSeconds is the minimum expirationSeconds of a CertificateSigningRequest accepted by Kubernetes
+	csrMinExpirationSeconds = 600
+	// csrAccessReviewInterval is how long the result of the access reviews of IsAvailable is kept
+	csrAccessReviewInterval = 10 * time.Minute
+)
+
+// csrAccess keeps the result of the access reviews of IsAvailable, so that they do not run on every reconcile
+var csrAccess struct {
+	mu         sync.Mutex
+	reviewedAt time.Time
+	err        error
+}
+
+// CSRCertificateProvider issues service certificates with the certificates.k8s.io/v1 CertificateSigningRequest API,
+// signed by the signer set in csrSignerName of the operator ConfigMap. The requests are approved by the approver of the signer.
+type CSRCertificateProvider struct{}
+
+func (p *CSRCertificateProvider) Name() string {
+	return CertificateProviderCSR
+}
+
+// IsAvailable returns true when csrSignerName is set and the API is supported. An error is returned when csrSignerCABundle
+// does not have the CA certificates of the signer. CertificateSigningRequests are cluster-scoped, so an error is also returned
+// when the operator is not allowed to manage them, such as when it does not watch all namespaces. The access of the operator
+// is checked again after csrAccessReviewInterval
+func (p *CSRCertificateProvider) IsAvailable(r *ReconcilerBase) (bool, error) {
+	if common.LoadFromConfig(common.Config, common.OpConfigCSRSignerName) == "" {
+		return false, nil
+	}
+	if _, err := getCSRSignerCABundle(); err != nil {
+		return false, err
+	}
+	ok, err := r.IsGroupVersionSupported(certificatesv1.SchemeGroupVersion.String(), "CertificateSigningRequest")
+	if err != nil || !ok {
+		return false, err
+	}
+
+	csrAccess.mu.Lock()
+	defer csrAccess.mu.Unlock()
+	if csrAccess.reviewedAt.IsZero() || time.Since(csrAccess.reviewedAt) >= csrAccessReviewInterval {
+		denied, err := reviewCSRAccess(r)
+		if err != nil {
+			return false, err
+		}
+		csrAccess.reviewedAt, csrAccess.err = time.Now(), denied
+	}
+	return csrAccess.err == nil, csrAccess.err
+}
+
+// reviewCSRAccess returns an error that describes the missing access of the operator to CertificateSigningRequests, or
+// nil if it has access. The returned error is set when the access could not be reviewed
+func reviewCSRAccess(r *ReconcilerBase) (denied error, err error) {
+	for _, verb := range []string{"get", "create", "delete"} {
+		review := &authorizationv1.SelfSubjectAccessReview{Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    certificatesv1.SchemeGroupVersion.Group,
+				Resource: "certificatesigningrequests",
+				Verb:     verb,
+			},
+		}}
+		if err := r.GetClient().Create(context.TODO(), review); err != nil {
+			return nil, err
+		}
+		if !review.Status.Allowed {
+			return fmt.Errorf("csrSignerName is set, but the operator is not allowed to %s the cluster-scoped CertificateSigningRequests. "+
+				"Install the operator to watch all namespaces or bind it to a ClusterRole with the get, create and delete verbs on certificatesigningrequests", verb), nil
+		}
+	}
+	return nil, nil
+}
+
+// getCSRSignerCABundle returns the CA certificates of the signer set in csrSignerCABundle of the operator ConfigMap
+func getCSRSignerCABundle() ([]byte, error) {
+	bundle := []byte(common.LoadFromConfig(common.Config, common.OpConfigCSRSignerCABundle))
+	cas, err := parseCertificates(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("csrSignerCABundle in the operator ConfigMap is not valid: %w", err)
+	}
+	if len(cas) == 0 {
+		return nil, errors.New("csrSignerName is set, but csrSignerCABundle in the operator ConfigMap does not have the CA certificates of the signer")
+	}
+	return bundle, nil
+}
+
+// ReconcileCertificate requests a certificate for a new private key when the certificate in the secret is missing, does
+// not have the DNS names of the instance, or is in the last third of its lifetime. The current certificate is kept until
+// the request is signed, and an error is returned while the instance has no valid certificate.
+func (p *CSRCertificateProvider) ReconcileCertificate(r *ReconcilerBase, ba common.BaseComponent, state *ReconcileState) (string, error) {
+	ca, err := getCSRSignerCABundle()
+	if err != nil {
+		return "", err
+	}
+	obj := ba.(metav1.Object)
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: obj.GetName() + "-svc-tls-csr", Namespace: obj.GetNamespace()}}
+	if err := r.GetClient().Get(context.TODO(), client.ObjectKeyFromObject(secret), secret); err != nil && !kerrors.IsNotFound(err) {
+		return "", err
+	}
+	dnsNames := getSvcCertificateDNSNames(ba)
+	valid, renew := checkCSRCertificate(secret.Data["tls.crt"], dnsNames, time.Now())
+	if !renew {
+		// The CA bundle of the signer may have changed since the certificate was issued
+		if !bytes.Equal(secret.Data["ca.crt"], ca) {
+			secret.Data["ca.crt"] = ca
+			if err := r.GetClient().Update(context.TODO(), secret); err != nil {
+				return "", err
+			}
+		}
+		return secret.Name, nil
+	}
+
+	// The private key is kept in its own secret until the certificate is issued, as the secret of the certificate is mounted
+	pending := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: obj.GetName() + "-svc-tls-csr-pending", Namespace: obj.GetNamespace()}}
+	if err := r.GetClient().Get(context.TODO(), client.ObjectKeyFromObject(pending), pending); err != nil && !kerrors.IsNotFound(err) {
+		return "", err
+	}
+	keyPEM := pending.Data[csrPendingKey]
+	if len(keyPEM) == 0 {
+		var err error
+		if keyPEM, err = generateCSRPrivateKey(ba); err != nil {
+			return "", err
+		}
+		err = r.CreateOrUpdate(pending, obj, func() error {
+			pending.Labels = ba.GetLabels()
+			pending.Data = map[string][]byte{csrPendingKey: keyPEM}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	csr := &certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{
+		Name: fmt.Sprintf("%s-%s-%s", obj.GetNamespace(), obj.GetName(), HashData(map[string][]byte{csrPendingKey: keyPEM})[:10]),
+	}}
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: csr.Name}, csr)
+	if kerrors.IsNotFound(err) {
+		if err := CustomizeCSR(csr, ba, keyPEM, dnsNames); err != nil {
+			return "", err
+		}
+		if err := r.GetClient().Create(context.TODO(), csr); err != nil {
+			return "", err
+		}
+		log.Info("Requested a service certificate", "CertificateSigningRequest", csr.Name, "signer", csr.Spec.SignerName)
+	} else if err != nil {
+		return "", err
+	}
+
+	for _, condition := range csr.Status.Conditions {
+		if (condition.Type == certificatesv1.CertificateDenied || condition.Type == certificatesv1.CertificateFailed) && condition.Status == corev1.ConditionTrue {
+			// A new private key and request are created on the next reconcile
+			r.DeleteResource(csr)
+			if err := r.DeleteResource(pending); err != nil {
+				return "", err
+			}
+			return "", fmt.Errorf("the CertificateSigningRequest %q of the service certificate is %s: %s", csr.Name, condition.Type, condition.Message)
+		}
+	}
+	if len(csr.Status.Certificate) == 0 {
+		if valid {
+			return secret.Name, nil
+		}
+		return "", fmt.Errorf("the CertificateSigningRequest %q of the service certificate is waiting to be approved and signed by %s", csr.Name, csr.Spec.SignerName)
+	}
+
+	err = r.CreateOrUpdate(secret, obj, func() error {
+		secret.Labels = ba.GetLabels()
+		secret.Data = map[string][]byte{
+			"tls.crt": csr.Status.Certificate,
+			"tls.key": keyPEM,
+			"ca.crt":  ca,
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	log.Info("Issued a service certificate", "CertificateSigningRequest", csr.Name, "Secret", secret.Name)
+	if err := r.DeleteResource(pending); err != nil {
+		return "", err
+	}
+	return secret.Name, r.DeleteResource(csr)
+}
+
+// DeleteCertificate deletes the secrets of the service certificate and of the private key of a pending request. Pending
+// CertificateSigningRequests are removed by the CertificateSigningRequest cleaner of Kubernetes.
+func (p *CSRCertificateProvider) DeleteCertificate(r *ReconcilerBase, ba common.BaseComponent) error {
+	obj := ba.(metav1.Object)
+	for _, name := range []string{obj.GetName() + "-svc-tls-csr", obj.GetName() + "-svc-tls-csr-pending"} {
+		secret := &corev1.Secret{}
+		err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}, secret)
+		if err != nil || !metav1.IsControlledBy(secret, obj) {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.DeleteResource(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CustomizeCSR configures the CertificateSigningRequest of the service certificate for the private key in keyPEM
+func CustomizeCSR(csr *certificatesv1.CertificateSigningRequest, ba common.BaseComponent, keyPEM []byte, dnsNames []string) error {
+	obj := ba.(metav1.Object)
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return errors.New("failed to decode the private key of the service certificate")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	template := &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: trimCommonName(obj.GetName(), obj.GetNamespace())},
+		DNSNames: dnsNames,
+	}
+	if ba.GetService() != nil && ba.GetService().GetCertificate() != nil {
+		for _, ip := range ba.GetService().GetCertificate().GetIPAddresses() {
+			template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+		}
+	}
+	request, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return err
+	}
+
+	csr.Labels = MergeMaps(csr.Labels, map[string]string{
+		"app.kubernetes.io/instance":     obj.GetName(),
+		ba.GetGroupName() + "/namespace": obj.GetNamespace(),
+	})
+	csr.Spec.Request = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request})
+	csr.Spec.SignerName = common.LoadFromConfig(common.Config, common.OpConfigCSRSignerName)
+	csr.Spec.Usages = []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageServerAuth}
+	csr.Spec.ExpirationSeconds = nil
+	if ba.GetService() != nil && ba.GetService().GetCertificate() != nil && ba.GetService().GetCertificate().GetDuration() != nil {
+		seconds := int32(ba.GetService().GetCertificate().GetDuration().Seconds())
+		if seconds < csrMinExpirationSeconds {
+			return fmt.Errorf(".spec.service.certificate.duration %s must be at least %ds", ba.GetService().GetCertificate().GetDuration().Duration, csrMinExpirationSeconds)
+		}
+		csr.Spec.ExpirationSeconds = &seconds
+	}
+	return nil
+}
+
+// generateCSRPrivateKey returns a new private key in PKCS#8 PEM for the service certificate, with the
+// algorithm and size of .spec.service.certificate.privateKey. Defaults to a 2048 bits RSA key
+func generateCSRPrivateKey(ba common.BaseComponent) ([]byte, error) {
+	algorithm, size := "RSA", 0
+	if ba.GetService() != nil && ba.GetService().GetCertificate() != nil && ba.GetService().GetCertificate().GetPrivateKey() != nil {
+		pk := ba.GetService().GetCertificate().GetPrivateKey()
+		if pk.GetAlgorithm() != nil {
+			algorithm = *pk.GetAlgorithm()
+		}
+		if pk.GetSize() != nil {
+			size = *pk.GetSize()
+		}
+	}
+
+	var key crypto.Signer
+	var err error
+	switch algorithm {
+	case "ECDSA":
+		curves := map[int]elliptic.Curve{0: elliptic.P256(), 256: elliptic.P256(), 384: elliptic.P384(), 521: elliptic.P521()}
+		curve, ok := curves[size]
+		if !ok {
+			return nil, fmt.Errorf(".spec.service.certificate.privateKey.size %d is not valid for the ECDSA algorithm", size)
+		}
+		key, err = ecdsa.GenerateKey(curve, rand.Reader)
+	case "Ed25519":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		if size == 0 {
+			size = 2048
+		}
+		key, err = rsa.GenerateKey(rand.Reader, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// checkCSRCertificate returns whether the certificate in crtPEM is valid for the DNS names at the given time,
+// and whether it should be renewed because it is not valid or is in the last third of its lifetime
+func checkCSRCertificate(crtPEM []byte, dnsNames []string, now time.Time) (valid bool, renew bool) {
+	block, _ := pem.Decode(crtPEM)
+	if block == nil {
+		return false, true
+	}
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false, true
+	}
+	for _, dnsName := range dnsNames {
+		if !slices.Contains(crt.DNSNames, dnsName) {
+			return false, true
+		}
+	}
+	if now.Before(crt.NotBefore) || !now.Before(crt.NotAfter) {
+		return false, true
+	}
+	renewAt := crt.NotAfter.Add(-crt.NotAfter.Sub(crt.NotBefore) / 3)
+	return true, !now.Before(renewAt)
+}
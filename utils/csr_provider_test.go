@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestCSRCertificateProvider(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: &appstacksv1.RuntimeComponentService{Port: 9443}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewClientBuilder().WithRuntimeObjects(objs...).WithInterceptorFuncs(accessReviewFuncs(true)).Build()
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	dc := createFakeDiscoveryClient().(*fakediscovery.FakeDiscovery)
+	dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+		GroupVersion: certificatesv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "certificatesigningrequests", Kind: "CertificateSigningRequest"}},
+	})
+	r.SetDiscoveryClient(dc)
+
+	p := &CSRCertificateProvider{}
+	csrAccess.reviewedAt = time.Time{}
+	noSignerAvailable, _ := p.IsAvailable(&r)
+	common.Config.Store(common.OpConfigCSRSignerName, "example.com/internal")
+	_, noCABundleErr := p.IsAvailable(&r)
+	ca, caKey := createTestCA(t)
+	common.Config.Store(common.OpConfigCSRSignerCABundle, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})))
+	available, _ := p.IsAvailable(&r)
+	deniedCl := fakeclient.NewClientBuilder().WithInterceptorFuncs(accessReviewFuncs(false)).Build()
+	denied := NewReconcilerBase(deniedCl, deniedCl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	denied.SetDiscoveryClient(dc)
+	// The access is not reviewed again until csrAccessReviewInterval is over
+	cachedAvailable, _ := p.IsAvailable(&denied)
+	csrAccess.reviewedAt = time.Now().Add(-csrAccessReviewInterval)
+	deniedAvailable, deniedErr := p.IsAvailable(&denied)
+	csrAccess.reviewedAt = time.Time{}
+
+	state := &ReconcileState{Context: context.TODO()}
+	_, pendingErr := p.ReconcileCertificate(&r, runtimecomponent, state)
+	csrList := &certificatesv1.CertificateSigningRequestList{}
+	cl.List(context.TODO(), csrList)
+	csr := csrList.Items[0]
+	pendingSecret := &corev1.Secret{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: name + "-svc-tls-csr-pending", Namespace: namespace}, pendingSecret)
+	pendingSvcSecretErr := cl.Get(context.TODO(), types.NamespacedName{Name: name + "-svc-tls-csr", Namespace: namespace}, &corev1.Secret{})
+	signCSR(t, cl, &csr, ca, caKey)
+
+	secretName, err := p.ReconcileCertificate(&r, runtimecomponent, state)
+	secret := &corev1.Secret{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: name + "-svc-tls-csr", Namespace: namespace}, secret)
+	csrErr := cl.Get(context.TODO(), types.NamespacedName{Name: csr.Name}, &certificatesv1.CertificateSigningRequest{})
+	pendingErrAfterSigning := cl.Get(context.TODO(), types.NamespacedName{Name: name + "-svc-tls-csr-pending", Namespace: namespace}, &corev1.Secret{})
+	_, chainErr := CheckCertificateSecret(secret)
+
+	dnsNames := getSvcCertificateDNSNames(runtimecomponent)
+	valid, renew := checkCSRCertificate(secret.Data["tls.crt"], dnsNames, time.Now())
+	validLate, renewLate := checkCSRCertificate(secret.Data["tls.crt"], dnsNames, time.Now().Add(20*time.Hour))
+	validOther, _ := checkCSRCertificate(secret.Data["tls.crt"], append(dnsNames, "other.runtime.svc"), time.Now())
+
+	deleteErr := p.DeleteCertificate(&r, runtimecomponent)
+	secretErr := cl.Get(context.TODO(), types.NamespacedName{Name: name + "-svc-tls-csr", Namespace: namespace}, &corev1.Secret{})
+
+	// Invalid settings of the service certificate are rejected by the Certificates step before a request is created
+	pipeline := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	pipeline.CertificateProviders = []CertificateProvider{p}
+	algorithm, size := "ECDSA", 2048
+	runtimecomponent.Spec.Service.Certificate = &appstacksv1.RuntimeComponentCertificate{
+		PrivateKey: &appstacksv1.RuntimeComponentCertificatePrivateKey{Algorithm: &algorithm, Size: &size}}
+	invalidSizeErr := pipeline.reconcileCertificates(runtimecomponent, state)
+	_, invalidKeyErr := generateCSRPrivateKey(runtimecomponent)
+	runtimecomponent.Spec.Service.Certificate = &appstacksv1.RuntimeComponentCertificate{Duration: &metav1.Duration{Duration: time.Minute}}
+	invalidDurationErr := pipeline.reconcileCertificates(runtimecomponent, state)
+	shortCSRErr := CustomizeCSR(&certificatesv1.CertificateSigningRequest{}, runtimecomponent, secret.Data["tls.key"], dnsNames)
+	runtimecomponent.Spec.Service.Certificate = &appstacksv1.RuntimeComponentCertificate{RenewBefore: &metav1.Duration{Duration: 10000 * time.Hour}}
+	invalidRenewBeforeErr := pipeline.reconcileCertificates(runtimecomponent, state)
+	invalidCSRList := &certificatesv1.CertificateSigningRequestList{}
+	cl.List(context.TODO(), invalidCSRList)
+
+	testCSR := []Test{
+		{"not available without signer", false, noSignerAvailable},
+		{"CA bundle of the signer required", true, noCABundleErr != nil && strings.Contains(noCABundleErr.Error(), "csrSignerCABundle")},
+		{"available", true, available},
+		{"access review cached", true, cachedAvailable},
+		{"not available without access", false, deniedAvailable},
+		{"cluster-scoped access error", true, deniedErr != nil && strings.Contains(deniedErr.Error(), "cluster-scoped")},
+		{"waiting for signer", true, pendingErr != nil && strings.Contains(pendingErr.Error(), "waiting")},
+		{"pending private key", true, len(pendingSecret.Data[csrPendingKey]) > 0},
+		{"no service certificate secret while pending", true, kerrors.IsNotFound(pendingSvcSecretErr)},
+		{"signer name", "example.com/internal", csr.Spec.SignerName},
+		{"server auth usage", true, csr.Spec.Usages[len(csr.Spec.Usages)-1] == certificatesv1.UsageServerAuth},
+		{"no error once signed", nil, err},
+		{"secret name", name + "-svc-tls-csr", secretName},
+		{"pending secret deleted", true, kerrors.IsNotFound(pendingErrAfterSigning)},
+		{"no pending private key", 0, len(secret.Data[csrPendingKey])},
+		{"has private key", true, len(secret.Data["tls.key"]) > 0},
+		{"CA bundle of the signer", common.LoadFromConfig(common.Config, common.OpConfigCSRSignerCABundle), string(secret.Data["ca.crt"])},
+		{"certificate signed by the CA bundle", nil, chainErr},
+		{"request deleted", true, kerrors.IsNotFound(csrErr)},
+		{"valid certificate", true, valid},
+		{"no renewal", false, renew},
+		{"valid late certificate", true, validLate},
+		{"renewal in the last third", true, renewLate},
+		{"missing DNS name", false, validOther},
+		{"no delete error", nil, deleteErr},
+		{"secret deleted", true, kerrors.IsNotFound(secretErr)},
+		{"invalid ECDSA size", true, invalidSizeErr != nil && strings.Contains(invalidSizeErr.Error(), "privateKey.size")},
+		{"no key for invalid ECDSA size", true, invalidKeyErr != nil},
+		{"invalid duration", true, invalidDurationErr != nil && strings.Contains(invalidDurationErr.Error(), ".spec.service.certificate.duration")},
+		{"invalid renewBefore", true, invalidRenewBeforeErr != nil && strings.Contains(invalidRenewBeforeErr.Error(), "renewBefore")},
+		{"expiration below the minimum", true, shortCSRErr != nil},
+		{"no request for invalid settings", 0, len(invalidCSRList.Items)},
+	}
+	verifyTests(testCSR, t)
+	common.Config = common.DefaultOpConfig()
+}
+
+// accessReviewFuncs answers the SelfSubjectAccessReviews of the operator with the allowed value
+func accessReviewFuncs(allowed bool) interceptor.Funcs {
+	return interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if review, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+				review.Status.Allowed = allowed
+				return nil
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}
+}
+
+// createTestCA returns a test CA valid for 48 hours
+func createTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	now := time.Now()
+	caTemplate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "Test CA"}, IsCA: true,
+		BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign, NotBefore: now.Add(-time.Hour), NotAfter: now.Add(48 * time.Hour)}
+	caDER, _ := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ca, caKey
+}
+
+// signCSR issues a 24 hours certificate for the CertificateSigningRequest with the test CA. Only the certificate is
+// returned, as by most signers
+func signCSR(t *testing.T, cl client.Client, csr *certificatesv1.CertificateSigningRequest, ca *x509.Certificate, caKey *ecdsa.PrivateKey) {
+	block, _ := pem.Decode(csr.Spec.Request)
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: request.Subject, DNSNames: request.DNSNames,
+		NotBefore: now.Add(-time.Minute), NotAfter: now.Add(24*time.Hour - time.Minute)}
+	crtDER, err := x509.CreateCertificate(rand.Reader, template, ca, request.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr.Status.Certificate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: crtDER})
+	if err := cl.Status().Update(context.TODO(), csr); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GrafanaDashboardGVK is the kind of the dashboards of the Grafana Operator
+var GrafanaDashboardGVK = schema.GroupVersionKind{Group: "grafana.integreatly.org", Version: "v1beta1", Kind: "GrafanaDashboard"}
+
+// dashboardPanel is a time series panel of a dashboard template
+type dashboardPanel struct {
+	title  string
+	expr   string
+	unit   string
+	legend string
+}
+
+// dashboardTemplates returns the panels of the templates of the dashboard, for the series selected by sel
+var dashboardTemplates = map[string]func(sel string) []dashboardPanel{
+	common.DashboardTemplateHTTP: func(sel string) []dashboardPanel {
+		return []dashboardPanel{
+			{"Request rate", fmt.Sprintf(`sum by (pod) (rate(http_server_request_duration_seconds_count{%s}[5m]))`, sel), "reqps", "{{pod}}"},
+			{"Error rate", fmt.Sprintf(`sum by (pod) (rate(http_server_request_duration_seconds_count{%s,http_response_status_code=~"5.."}[5m])) / sum by (pod) (rate(http_server_request_duration_seconds_count{%s}[5m]))`, sel, sel), "percentunit", "{{pod}}"},
+			{"Request duration (p95)", fmt.Sprintf(`histogram_quantile(0.95, sum by (le, pod) (rate(http_server_request_duration_seconds_bucket{%s}[5m])))`, sel), "s", "{{pod}}"},
+			{"Requests by route", fmt.Sprintf(`sum by (http_route) (rate(http_server_request_duration_seconds_count{%s}[5m]))`, sel), "reqps", "{{http_route}}"},
+		}
+	},
+	common.DashboardTemplateJVM: func(sel string) []dashboardPanel {
+		return []dashboardPanel{
+			{"Heap used", fmt.Sprintf(`sum by (pod) (jvm_memory_used_bytes{%s,jvm_memory_type="heap"})`, sel), "bytes", "{{pod}}"},
+			{"CPU utilization", fmt.Sprintf(`avg by (pod) (jvm_cpu_recent_utilization_ratio{%s})`, sel), "percentunit", "{{pod}}"},
+			{"Garbage collection time", fmt.Sprintf(`sum by (pod) (rate(jvm_gc_duration_seconds_sum{%s}[5m]))`, sel), "s", "{{pod}}"},
+			{"Threads", fmt.Sprintf(`sum by (pod) (jvm_thread_count{%s})`, sel), "short", "{{pod}}"},
+		}
+	},
+	common.DashboardTemplateMicroProfile: func(sel string) []dashboardPanel {
+		return []dashboardPanel{
+			{"Heap used", fmt.Sprintf(`sum by (pod) (memory_usedHeap_bytes{%s,mp_scope="base"})`, sel), "bytes", "{{pod}}"},
+			{"CPU load", fmt.Sprintf(`avg by (pod) (cpu_processCpuLoad_percent{%s,mp_scope="base"})`, sel), "percentunit", "{{pod}}"},
+			{"Garbage collection time", fmt.Sprintf(`sum by (pod) (rate(gc_time_seconds_total{%s,mp_scope="base"}[5m]))`, sel), "s", "{{pod}}"},
+			{"REST request rate", fmt.Sprintf(`sum by (pod) (rate(REST_request_seconds_count{%s,mp_scope="base"}[5m]))`, sel), "reqps", "{{pod}}"},
+		}
+	},
+}
+
+// GetDashboardJSON returns the Grafana dashboard of the instance, built from the templates of .spec.monitoring.dashboard
+// for the series of its namespace and scrape job
+func GetDashboardJSON(ba common.BaseComponent) (string, error) {
+	obj := ba.(metav1.Object)
+	job := getMonitoringJobName(ba)
+	sel := fmt.Sprintf(`namespace=%q,job=%q,pod=~"$pod"`, obj.GetNamespace(), job)
+	datasource := map[string]interface{}{"type": "prometheus", "uid": "${datasource}"}
+
+	var panels []interface{}
+	id, y := 1, 0
+	for _, template := range ba.GetMonitoring().GetDashboard().GetTemplates() {
+		panelsOf, ok := dashboardTemplates[template]
+		if !ok {
+			return "", fmt.Errorf("unknown dashboard template %q", template)
+		}
+		panels = append(panels, map[string]interface{}{
+			"id": id, "type": "row", "title": template, "collapsed": false, "panels": []interface{}{},
+			"gridPos": map[string]int{"h": 1, "w": 24, "x": 0, "y": y},
+		})
+		id, y = id+1, y+1
+		for i, panel := range panelsOf(sel) {
+			panels = append(panels, map[string]interface{}{
+				"id": id, "type": "timeseries", "title": panel.title, "datasource": datasource,
+				"gridPos":     map[string]int{"h": 8, "w": 12, "x": (i % 2) * 12, "y": y + (i/2)*8},
+				"fieldConfig": map[string]interface{}{"defaults": map[string]interface{}{"unit": panel.unit}, "overrides": []interface{}{}},
+				"targets":     []interface{}{map[string]interface{}{"refId": "A", "datasource": datasource, "expr": panel.expr, "legendFormat": panel.legend}},
+			})
+			id++
+		}
+		y += (len(panelsOf(sel)) + 1) / 2 * 8
+	}
+
+	dashboard := map[string]interface{}{
+		"uid":           getDashboardUID(ba),
+		"title":         obj.GetNamespace() + " / " + obj.GetName(),
+		"tags":          []string{ba.GetGroupName(), obj.GetNamespace(), obj.GetName()},
+		"editable":      false,
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-1h", "to": "now"},
+		"templating": map[string]interface{}{"list": []interface{}{
+			map[string]interface{}{"name": "datasource", "label": "Data source", "type": "datasource", "query": "prometheus"},
+			map[string]interface{}{"name": "pod", "label": "Pod", "type": "query", "datasource": datasource, "refresh": 2,
+				"includeAll": true, "multi": true, "allValue": ".*",
+				"query": fmt.Sprintf(`label_values(up{namespace=%q,job=%q}, pod)`, obj.GetNamespace(), job)},
+		}},
+		"panels": panels,
+	}
+	data, err := json.Marshal(dashboard)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// CustomizeDashboardConfigMap sets the ConfigMap of the dashboard of the instance, with the grafanaDashboardLabels of
+// the operator ConfigMap so that the Grafana dashboard sidecar loads it
+func CustomizeDashboardConfigMap(cm *corev1.ConfigMap, ba common.BaseComponent, dashboardJSON string) error {
+	dashboardLabels, err := labels.ConvertSelectorToLabelsMap(common.LoadFromConfig(common.Config, common.OpConfigGrafanaDashboardLabels))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s in the operator ConfigMap: %w", common.OpConfigGrafanaDashboardLabels, err)
+	}
+	obj := ba.(metav1.Object)
+	cm.Labels = MergeMaps(ba.GetLabels(), ba.GetMonitoring().GetLabels(), dashboardLabels)
+	cm.Annotations = MergeMaps(cm.Annotations, ba.GetAnnotations())
+	cm.Data = map[string]string{obj.GetNamespace() + "-" + obj.GetName() + ".json": dashboardJSON}
+	return nil
+}
+
+// CustomizeGrafanaDashboard sets the GrafanaDashboard of the Grafana Operator with the dashboard of the instance
+func CustomizeGrafanaDashboard(gd *unstructured.Unstructured, ba common.BaseComponent, dashboardJSON string) error {
+	gd.SetGroupVersionKind(GrafanaDashboardGVK)
+	gd.SetLabels(MergeMaps(gd.GetLabels(), ba.GetLabels(), ba.GetMonitoring().GetLabels()))
+	gd.SetAnnotations(MergeMaps(gd.GetAnnotations(), ba.GetAnnotations()))
+
+	instanceSelector := map[string]interface{}{}
+	if selector := ba.GetMonitoring().GetDashboard().GetInstanceSelector(); selector != nil {
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(selector)
+		if err != nil {
+			return err
+		}
+		instanceSelector = converted
+	}
+	if err := unstructured.SetNestedMap(gd.Object, instanceSelector, "spec", "instanceSelector"); err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(gd.Object, dashboardJSON, "spec", "json")
+}
+
+// GetDashboardConfigMapName returns the name of the ConfigMap of the dashboard of the instance
+func GetDashboardConfigMapName(ba common.BaseComponent) string {
+	return ba.(metav1.Object).GetName() + "-grafana-dashboard"
+}
+
+// getMonitoringJobName returns the job label of the series scraped by the ServiceMonitor or PodMonitor of the instance
+func getMonitoringJobName(ba common.BaseComponent) string {
+	obj := ba.(metav1.Object)
+	if ba.GetMonitoring().GetKind() == common.MonitoringKindPodMonitor {
+		return obj.GetNamespace() + "/" + obj.GetName()
+	}
+	// The job of a ServiceMonitor is the name of the Service
+	return obj.GetName()
+}
+
+// getDashboardUID returns a stable UID for the dashboard of the instance, within the 40 characters allowed by Grafana
+func getDashboardUID(ba common.BaseComponent) string {
+	obj := ba.(metav1.Object)
+	sum := sha256.Sum256([]byte(obj.GetNamespace() + "/" + obj.GetName()))
+	return hex.EncodeToString(sum[:])[:40]
+}
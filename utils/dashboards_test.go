@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestGetDashboardJSON(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Spec.Monitoring = &appstacksv1.RuntimeComponentMonitoring{Dashboard: &appstacksv1.RuntimeComponentMonitoringDashboard{}}
+
+	defaultJSON, err := GetDashboardJSON(runtime)
+	dashboard := map[string]interface{}{}
+	unmarshalErr := json.Unmarshal([]byte(defaultJSON), &dashboard)
+	secondJSON, _ := GetDashboardJSON(runtime)
+
+	runtime.Spec.Monitoring.Dashboard.Templates = []string{common.DashboardTemplateJVM, common.DashboardTemplateMicroProfile}
+	podMonitor := common.MonitoringKindPodMonitor
+	runtime.Spec.Monitoring.Kind = &podMonitor
+	templatesJSON, _ := GetDashboardJSON(runtime)
+	templates := map[string]interface{}{}
+	json.Unmarshal([]byte(templatesJSON), &templates)
+
+	runtime.Spec.Monitoring.Dashboard.Templates = []string{"Unknown"}
+	_, unknownErr := GetDashboardJSON(runtime)
+
+	testGDJ := []Test{
+		{"no error", nil, err},
+		{"valid JSON", nil, unmarshalErr},
+		{"deterministic", defaultJSON, secondJSON},
+		{"title", "runtime / my-app", dashboard["title"]},
+		{"uid length", 40, len(dashboard["uid"].(string))},
+		{"default template panels", 5, len(dashboard["panels"].([]interface{}))},
+		{"ServiceMonitor job", true, strings.Contains(defaultJSON, `namespace=\"runtime\",job=\"my-app\"`)},
+		{"template panels", 10, len(templates["panels"].([]interface{}))},
+		{"PodMonitor job", true, strings.Contains(templatesJSON, `job=\"runtime/my-app\"`)},
+		{"JVM metrics", true, strings.Contains(templatesJSON, "jvm_memory_used_bytes")},
+		{"unknown template", true, unknownErr != nil},
+	}
+	verifyTests(testGDJ, t)
+}
+
+func TestCustomizeDashboard(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Spec.Monitoring = &appstacksv1.RuntimeComponentMonitoring{Dashboard: &appstacksv1.RuntimeComponentMonitoringDashboard{
+		InstanceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"dashboards": "grafana"}},
+	}}
+
+	cm := &corev1.ConfigMap{}
+	cmErr := CustomizeDashboardConfigMap(cm, runtime, "{}")
+	common.Config.Store(common.OpConfigGrafanaDashboardLabels, "team=a,grafana_folder=apps")
+	labelledCM := &corev1.ConfigMap{}
+	CustomizeDashboardConfigMap(labelledCM, runtime, "{}")
+
+	gd := &unstructured.Unstructured{}
+	gdErr := CustomizeGrafanaDashboard(gd, runtime, "{}")
+	instanceSelector, _, _ := unstructured.NestedStringMap(gd.Object, "spec", "instanceSelector", "matchLabels")
+	dashboardJSON, _, _ := unstructured.NestedString(gd.Object, "spec", "json")
+
+	testCD := []Test{
+		{"ConfigMap name", "my-app-grafana-dashboard", GetDashboardConfigMapName(runtime)},
+		{"ConfigMap no error", nil, cmErr},
+		{"default sidecar label", "1", cm.Labels["grafana_dashboard"]},
+		{"component label", name, cm.Labels["app.kubernetes.io/instance"]},
+		{"ConfigMap data", "{}", cm.Data["runtime-my-app.json"]},
+		{"configured sidecar labels", map[string]string{"team": "a", "grafana_folder": "apps"},
+			map[string]string{"team": labelledCM.Labels["team"], "grafana_folder": labelledCM.Labels["grafana_folder"]}},
+		{"GrafanaDashboard no error", nil, gdErr},
+		{"GrafanaDashboard kind", GrafanaDashboardGVK, gd.GroupVersionKind()},
+		{"instance selector", map[string]string{"dashboards": "grafana"}, instanceSelector},
+		{"GrafanaDashboard JSON", "{}", dashboardJSON},
+	}
+	verifyTests(testCD, t)
+
+	common.Config = common.DefaultOpConfig()
+}
+
+func TestReconcileDashboardDiscoveryError(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.Spec.Monitoring = &appstacksv1.RuntimeComponentMonitoring{Dashboard: &appstacksv1.RuntimeComponentMonitoringDashboard{}}
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+
+	// The discovery of the Grafana Operator fails
+	dc := createFakeDiscoveryClient().(*fakediscovery.FakeDiscovery)
+	dc.PrependReactor("get", "resource", func(action coretesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("discovery failed")
+	})
+	r.SetDiscoveryClient(dc)
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	err := p.reconcileDashboard(runtimecomponent, &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+	cm := &corev1.ConfigMap{}
+	cmErr := cl.Get(context.TODO(), client.ObjectKey{Name: GetDashboardConfigMapName(runtimecomponent), Namespace: namespace}, cm)
+
+	testRDDE := []Test{
+		{"no error", nil, err},
+		{"dashboard ConfigMap", nil, cmErr},
+		{"dashboard sidecar label", "1", cm.Labels["grafana_dashboard"]},
+	}
+	verifyTests(testRDDE, t)
+}
@@ -0,0 +1,238 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ExtraResourceTemplateData is the data available to the templates in .spec.extraResources
+type ExtraResourceTemplateData struct {
+	Name       string
+	Namespace  string
+	Labels     map[string]string
+	References common.StatusReferences
+}
+
+// IsExtraResourceKindAllowed returns true if the kind is listed in the extraResourcesAllowedKinds key of the operator ConfigMap
+func IsExtraResourceKindAllowed(gk schema.GroupKind) bool {
+	for _, allowed := range strings.Split(common.LoadFromConfig(common.Config, common.OpConfigExtraResourcesAllowedKinds), ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed != "" && schema.ParseGroupKind(allowed) == gk {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExtraResources returns the resources in .spec.extraResources with the templates in their string values executed.
+// The resources are placed in the namespace of the instance.
+func GetExtraResources(ba common.BaseComponent) ([]*unstructured.Unstructured, error) {
+	obj := ba.(metav1.Object)
+	data := ExtraResourceTemplateData{
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Labels:     ba.GetLabels(),
+		References: ba.GetStatus().GetReferences(),
+	}
+
+	resources := []*unstructured.Unstructured{}
+	for i, raw := range ba.GetExtraResources() {
+		resource := &unstructured.Unstructured{}
+		if err := resource.UnmarshalJSON(raw.Raw); err != nil {
+			return nil, fmt.Errorf("failed to read .spec.extraResources[%d]: %w", i, err)
+		}
+		if _, err := executeExtraResourceTemplates(resource.Object, data); err != nil {
+			return nil, fmt.Errorf("failed to execute the templates of .spec.extraResources[%d]: %w", i, err)
+		}
+
+		gvk := resource.GroupVersionKind()
+		if gvk.Kind == "" || gvk.Version == "" || resource.GetName() == "" {
+			return nil, fmt.Errorf(".spec.extraResources[%d] must set apiVersion, kind and metadata.name", i)
+		}
+		if !IsExtraResourceKindAllowed(gvk.GroupKind()) {
+			return nil, fmt.Errorf(".spec.extraResources[%d] is a %s, which is not allowed by %s in the operator ConfigMap", i, gvk.GroupKind(), common.OpConfigExtraResourcesAllowedKinds)
+		}
+		if ns := resource.GetNamespace(); ns != "" && ns != obj.GetNamespace() {
+			return nil, fmt.Errorf(".spec.extraResources[%d] must be in namespace %s", i, obj.GetNamespace())
+		}
+		resource.SetNamespace(obj.GetNamespace())
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// executeExtraResourceTemplates executes every string value of content as a template, leaving the keys unchanged
+func executeExtraResourceTemplates(content interface{}, data ExtraResourceTemplateData) (interface{}, error) {
+	switch value := content.(type) {
+	case map[string]interface{}:
+		for key := range value {
+			rendered, err := executeExtraResourceTemplates(value[key], data)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = rendered
+		}
+		return value, nil
+	case []interface{}:
+		for i := range value {
+			rendered, err := executeExtraResourceTemplates(value[i], data)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = rendered
+		}
+		return value, nil
+	case string:
+		if !strings.Contains(value, "{{") {
+			return value, nil
+		}
+		tmpl, err := template.New("extraResource").Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, data); err != nil {
+			return nil, err
+		}
+		return out.String(), nil
+	default:
+		return value, nil
+	}
+}
+
+// GetExtraResourceKeysAnnotationName returns the name of the annotation that records the top-level keys of an extra
+// resource set from .spec.extraResources, so that the keys removed from the spec are removed from the resource
+func GetExtraResourceKeysAnnotationName(ba common.BaseComponent) string {
+	return ba.GetGroupName() + "/extra-resource-keys"
+}
+
+// CheckExtraResourceScope returns an error if the kind of the extra resource at index i of .spec.extraResources is
+// not namespaced, since the extra resources are created in the namespace of the instance and owned by it
+func CheckExtraResourceScope(mapper meta.RESTMapper, resource *unstructured.Unstructured, i int) error {
+	gvk := resource.GroupVersionKind()
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to find the kind of .spec.extraResources[%d]: %w", i, err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return fmt.Errorf(".spec.extraResources[%d] is a %s, which is cluster-scoped. Only namespaced kinds can be created", i, gvk.GroupKind())
+	}
+	return nil
+}
+
+// CustomizeExtraResource copies the desired content of an extra resource onto the existing resource.
+// The top-level keys set from the previous desired content and no longer desired are removed.
+// Labels and annotations are merged and the status is left untouched.
+func CustomizeExtraResource(resource *unstructured.Unstructured, desired *unstructured.Unstructured, ba common.BaseComponent) {
+	keysAnnotation := GetExtraResourceKeysAnnotationName(ba)
+	for _, key := range strings.Split(resource.GetAnnotations()[keysAnnotation], ",") {
+		if _, ok := desired.Object[key]; !ok && !isExtraResourceReservedKey(key) {
+			delete(resource.Object, key)
+		}
+	}
+
+	keys := []string{}
+	for key, value := range desired.Object {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		resource.Object[key] = runtime.DeepCopyJSONValue(value)
+		if !isExtraResourceReservedKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	resource.SetLabels(MergeMaps(resource.GetLabels(), desired.GetLabels()))
+	resource.SetAnnotations(MergeMaps(resource.GetAnnotations(), desired.GetAnnotations(), map[string]string{keysAnnotation: strings.Join(keys, ",")}))
+}
+
+// isExtraResourceReservedKey returns true for the top-level keys of a resource that are never removed from it
+func isExtraResourceReservedKey(key string) bool {
+	return key == "" || key == "apiVersion" || key == "kind" || key == "metadata" || key == "status"
+}
+
+// ExtraResourceReference returns a reference to an extra resource, as recorded in the status
+func ExtraResourceReference(resource *unstructured.Unstructured) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: resource.GetAPIVersion(),
+		Kind:       resource.GetKind(),
+		Namespace:  resource.GetNamespace(),
+		Name:       resource.GetName(),
+	}
+}
+
+// PruneExtraResources deletes the resources recorded in the status that are not in current.
+// Resources that are no longer controlled by the instance are left in place.
+func (r *ReconcilerBase) PruneExtraResources(ba common.BaseComponent, current []corev1.ObjectReference) error {
+	owner := ba.(metav1.Object)
+	for _, ref := range ba.GetStatus().GetExtraResources() {
+		if containsObjectReference(current, ref) {
+			continue
+		}
+		resource := &unstructured.Unstructured{}
+		resource.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+		err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, resource)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(resource, owner) {
+			continue
+		}
+		if err := r.DeleteResource(resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsObjectReference(refs []corev1.ObjectReference, ref corev1.ObjectReference) bool {
+	for i := range refs {
+		if refs[i] == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeObjectReferences returns refs with the references of added that are not already in it
+func mergeObjectReferences(refs []corev1.ObjectReference, added []corev1.ObjectReference) []corev1.ObjectReference {
+	merged := append([]corev1.ObjectReference{}, refs...)
+	for _, ref := range added {
+		if !containsObjectReference(merged, ref) {
+			merged = append(merged, ref)
+		}
+	}
+	return merged
+}
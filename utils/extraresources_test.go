@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestGetExtraResources(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.Status.SetReference(common.StatusReferenceCertSecretName, "my-app-svc-tls")
+	runtimecomponent.Spec.ExtraResources = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "{{ .Name }}-config"},
+			"data": {"secret": "{{ .References.svcCertSecretName }}", "instance": "{{ index .Labels \"app.kubernetes.io/instance\" }}"}}`)},
+	}
+	resources, err := GetExtraResources(runtimecomponent)
+
+	runtimecomponent.Spec.ExtraResources = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "Role", "metadata": {"name": "my-role"}}`)},
+	}
+	_, notAllowedErr := GetExtraResources(runtimecomponent)
+	common.Config.Store(common.OpConfigExtraResourcesAllowedKinds, "ConfigMap, Role.rbac.authorization.k8s.io")
+	_, allowedErr := GetExtraResources(runtimecomponent)
+
+	runtimecomponent.Spec.ExtraResources = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "my-config", "namespace": "other"}}`)},
+	}
+	_, namespaceErr := GetExtraResources(runtimecomponent)
+	runtimecomponent.Spec.ExtraResources = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "{{ .Unknown }}"}}`)},
+	}
+	_, templateErr := GetExtraResources(runtimecomponent)
+
+	testGER := []Test{
+		{"no error", nil, err},
+		{"templated name", name + "-config", resources[0].GetName()},
+		{"namespace of the instance", namespace, resources[0].GetNamespace()},
+		{"templated reference", "my-app-svc-tls", resources[0].Object["data"].(map[string]interface{})["secret"]},
+		{"templated label", name, resources[0].Object["data"].(map[string]interface{})["instance"]},
+		{"kind not allowed", true, notAllowedErr != nil && strings.Contains(notAllowedErr.Error(), "Role.rbac.authorization.k8s.io")},
+		{"kind allowed", nil, allowedErr},
+		{"other namespace", true, namespaceErr != nil},
+		{"invalid template", true, templateErr != nil && strings.Contains(templateErr.Error(), ".spec.extraResources[0]")},
+	}
+	verifyTests(testGER, t)
+	common.Config = common.DefaultOpConfig()
+}
+
+func TestReconcileExtraResources(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.UID = "my-app-uid"
+	runtimecomponent.Spec.ExtraResources = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "first"}, "data": {"key": "value"}}`)},
+		{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "second"}}`)},
+	}
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).WithRESTMapper(testrestmapper.TestOnlyStaticRESTMapper(s)).Build()
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+
+	createErr := p.reconcileExtraResources(runtimecomponent, &ReconcileState{})
+	first := &corev1.ConfigMap{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: "first", Namespace: namespace}, first)
+	created := runtimecomponent.Status.GetExtraResources()
+
+	// Removing a resource from the list prunes it
+	runtimecomponent.Spec.ExtraResources = runtimecomponent.Spec.ExtraResources[:1]
+	pruneErr := p.reconcileExtraResources(runtimecomponent, &ReconcileState{})
+	secondErr := cl.Get(context.TODO(), types.NamespacedName{Name: "second", Namespace: namespace}, &corev1.ConfigMap{})
+
+	// The keys removed from the desired content are removed from the resource
+	runtimecomponent.Spec.ExtraResources = []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "first"}, "binaryData": {"key": "dmFsdWU="}}`)},
+	}
+	keysErr := p.reconcileExtraResources(runtimecomponent, &ReconcileState{})
+	updated := &corev1.ConfigMap{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: "first", Namespace: namespace}, updated)
+
+	// Cluster-scoped kinds are not created in the namespace of the instance
+	common.Config.Store(common.OpConfigExtraResourcesAllowedKinds, "ConfigMap,Namespace")
+	runtimecomponent.Spec.ExtraResources = append(runtimecomponent.Spec.ExtraResources,
+		runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "my-namespace"}}`)})
+	scopeErr := p.reconcileExtraResources(runtimecomponent, &ReconcileState{})
+	namespaceErr := cl.Get(context.TODO(), types.NamespacedName{Name: "my-namespace"}, &corev1.Namespace{})
+
+	testRER := []Test{
+		{"no error on create", nil, createErr},
+		{"data", "value", first.Data["key"]},
+		{"owned by the instance", true, len(first.OwnerReferences) == 1 && first.OwnerReferences[0].Name == name},
+		{"recorded in status", 2, len(created)},
+		{"no error on prune", nil, pruneErr},
+		{"removed resource is pruned", true, apierrors.IsNotFound(secondErr)},
+		{"status after prune", []corev1.ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Namespace: namespace, Name: "first"}}, runtimecomponent.Status.GetExtraResources()},
+		{"applied keys", "data", first.Annotations["rc.app.stacks/extra-resource-keys"]},
+		{"no error on keys change", nil, keysErr},
+		{"removed key", 0, len(updated.Data)},
+		{"added key", "value", string(updated.BinaryData["key"])},
+		{"updated applied keys", "binaryData", updated.Annotations["rc.app.stacks/extra-resource-keys"]},
+		{"cluster-scoped kind", true, scopeErr != nil && strings.Contains(scopeErr.Error(), "cluster-scoped")},
+		{"cluster-scoped resource not created", true, apierrors.IsNotFound(namespaceErr)},
+	}
+	verifyTests(testRER, t)
+	common.Config = common.DefaultOpConfig()
+}
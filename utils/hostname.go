@@ -0,0 +1,144 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// HostnameTemplateData holds the values available to the defaultHostnameTemplate of the operator ConfigMap
+type HostnameTemplateData struct {
+	Name            string
+	Namespace       string
+	ApplicationName string
+	Labels          map[string]string
+	// Domain is the domain of the namespace from namespaceDomains, or else defaultHostname
+	Domain string
+}
+
+// GetHostnameDomain returns the domain of the default hostnames of the namespace. It is the domain
+// of the namespace in namespaceDomains, or else defaultHostname
+func GetHostnameDomain(namespace string) string {
+	for _, pair := range strings.Split(common.LoadFromConfig(common.Config, common.OpConfigNamespaceDomains), ",") {
+		ns, domain, found := strings.Cut(pair, "=")
+		if found && strings.TrimSpace(ns) == namespace && strings.TrimSpace(domain) != "" {
+			return strings.TrimSpace(domain)
+		}
+	}
+	return common.LoadFromConfig(common.Config, common.OpConfigDefaultHostname)
+}
+
+// GetDefaultHostname returns the hostname generated from defaultHostnameTemplate, or "" if no domain is
+// configured for the namespace of the instance
+func GetDefaultHostname(ba common.BaseComponent) (string, error) {
+	obj := ba.(metav1.Object)
+	domain := GetHostnameDomain(obj.GetNamespace())
+	if domain == "" {
+		return "", nil
+	}
+
+	text := common.LoadFromConfig(common.Config, common.OpConfigDefaultHostnameTemplate)
+	if strings.TrimSpace(text) == "" {
+		text = "{{ .Name }}-{{ .Namespace }}.{{ .Domain }}"
+	}
+	tmpl, err := template.New(common.OpConfigDefaultHostnameTemplate).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s in the operator ConfigMap: %w", common.OpConfigDefaultHostnameTemplate, err)
+	}
+	data := HostnameTemplateData{
+		Name:            obj.GetName(),
+		Namespace:       obj.GetNamespace(),
+		ApplicationName: ba.GetApplicationName(),
+		Labels:          ba.GetLabels(),
+		Domain:          domain,
+	}
+	host := &strings.Builder{}
+	if err := tmpl.Execute(host, data); err != nil {
+		return "", fmt.Errorf("failed to execute %s in the operator ConfigMap: %w", common.OpConfigDefaultHostnameTemplate, err)
+	}
+	if errs := validation.IsDNS1123Subdomain(host.String()); len(errs) > 0 {
+		return "", fmt.Errorf("the hostname %q generated from %s in the operator ConfigMap is not valid: %s", host.String(), common.OpConfigDefaultHostnameTemplate, strings.Join(errs, ", "))
+	}
+	return host.String(), nil
+}
+
+// GetHostname returns the hostname of the Route or Ingress of the instance, which is .spec.route.host
+// or else the default hostname
+func GetHostname(ba common.BaseComponent) (string, error) {
+	if rt := ba.GetRoute(); rt != nil && rt.GetHost() != "" {
+		return rt.GetHost(), nil
+	}
+	return GetDefaultHostname(ba)
+}
+
+// getHostnameOrEmpty returns the hostname of the instance, or "" if the default hostname cannot be generated.
+// The error is reported by the Exposure step of the ReconcilePipeline
+func getHostnameOrEmpty(ba common.BaseComponent) string {
+	host, err := GetHostname(ba)
+	if err != nil {
+		obj := ba.(metav1.Object)
+		log.Error(err, "Failed to generate the default hostname", "Request.Namespace", obj.GetNamespace(), "Request.Name", obj.GetName())
+		return ""
+	}
+	return host
+}
+
+// GetHostClaim returns the host and path of the instance, such as app.example.com/api, that no other instance
+// can use, or "" if the host is empty
+func GetHostClaim(ba common.BaseComponent, host string) string {
+	if host == "" {
+		return ""
+	}
+	if rt := ba.GetRoute(); rt != nil && rt.GetPath() != "" && rt.GetPath() != "/" {
+		return host + "/" + strings.TrimPrefix(rt.GetPath(), "/")
+	}
+	return host
+}
+
+// SetHostClaim records the host and path used by the instance in its status references. Other instances
+// cannot use it until it is released
+func SetHostClaim(ba common.BaseComponent, hostPath string) {
+	if hostPath == "" {
+		delete(ba.GetStatus().GetReferences(), common.StatusReferenceHost)
+		return
+	}
+	ba.GetStatus().SetReference(common.StatusReferenceHost, hostPath)
+}
+
+// HasHostPrecedence returns true if instance a keeps a host and path also claimed by instance b. The instance that
+// already recorded the host and path in its status references keeps it, so that a working instance is not taken over by
+// an instance created later or earlier. When both recorded it, the oldest instance wins, and the UID breaks the tie
+// between instances created in the same second
+func HasHostPrecedence(a, b common.BaseComponent, hostPath string) bool {
+	claimedByA := a.GetStatus().GetReferences()[common.StatusReferenceHost] == hostPath
+	claimedByB := b.GetStatus().GetReferences()[common.StatusReferenceHost] == hostPath
+	if claimedByA != claimedByB {
+		return claimedByA
+	}
+	objA, objB := a.(metav1.Object), b.(metav1.Object)
+	ta, tb := objA.GetCreationTimestamp(), objB.GetCreationTimestamp()
+	if !ta.Equal(&tb) {
+		return ta.Before(&tb)
+	}
+	return objA.GetUID() < objB.GetUID()
+}
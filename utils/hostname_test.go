@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	routev1 "github.com/openshift/api/route/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestGetDefaultHostname(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, ApplicationName: "my-application"}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.Labels = map[string]string{"team": "payments"}
+
+	noDomainHost, noDomainErr := GetDefaultHostname(runtimecomponent)
+	common.Config.Store(common.OpConfigDefaultHostname, "apps.example.com")
+	defaultHost, _ := GetDefaultHostname(runtimecomponent)
+	common.Config.Store(common.OpConfigNamespaceDomains, "other=other.example.com, runtime=runtime.example.com")
+	namespaceHost, _ := GetDefaultHostname(runtimecomponent)
+	common.Config.Store(common.OpConfigDefaultHostnameTemplate, "{{ .ApplicationName }}-{{ index .Labels \"team\" }}.{{ .Domain }}")
+	templateHost, _ := GetDefaultHostname(runtimecomponent)
+	common.Config.Store(common.OpConfigDefaultHostnameTemplate, "{{ .Unknown }}.{{ .Domain }}")
+	_, templateErr := GetDefaultHostname(runtimecomponent)
+	common.Config.Store(common.OpConfigDefaultHostnameTemplate, "{{ .Name }}_{{ .Domain }}")
+	_, invalidErr := GetDefaultHostname(runtimecomponent)
+
+	runtimecomponent.Spec.Route = &appstacksv1.RuntimeComponentRoute{Host: "my-app.example.com", Path: "/api"}
+	routeHost, _ := GetHostname(runtimecomponent)
+
+	testGDH := []Test{
+		{"no domain", "", noDomainHost},
+		{"no domain error", nil, noDomainErr},
+		{"default template", "my-app-runtime.apps.example.com", defaultHost},
+		{"namespace domain", "my-app-runtime.runtime.example.com", namespaceHost},
+		{"custom template", "my-application-payments.runtime.example.com", templateHost},
+		{"template error", true, templateErr != nil && strings.Contains(templateErr.Error(), common.OpConfigDefaultHostnameTemplate)},
+		{"invalid hostname", true, invalidErr != nil && strings.Contains(invalidErr.Error(), "my-app_runtime.example.com")},
+		{"route host", "my-app.example.com", routeHost},
+		{"host claim", "my-app.example.com/api", GetHostClaim(runtimecomponent, routeHost)},
+	}
+	verifyTests(testGDH, t)
+	common.Config = common.DefaultOpConfig()
+}
+
+func TestReconcileExposureHostOwner(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	manageTLS := false
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, Expose: &expose, ManageTLS: &manageTLS,
+		Route: &appstacksv1.RuntimeComponentRoute{Host: "my-app.example.com"}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	routev1.AddToScheme(s)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	r.SetDiscoveryClient(createFakeDiscoveryClient())
+
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	owner := "other/other-app"
+	p.FindHostOwner = func(ba common.BaseComponent, hostPath string) (string, error) { return owner, nil }
+	state := &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	ownedErr := p.reconcileExposure(runtimecomponent, state)
+	ownedClaim := runtimecomponent.Status.References[common.StatusReferenceHost]
+
+	owner = ""
+	err := p.reconcileExposure(runtimecomponent, state)
+	claim := runtimecomponent.Status.References[common.StatusReferenceHost]
+
+	runtimecomponent.Spec.Expose = nil
+	releaseErr := p.reconcileExposure(runtimecomponent, state)
+
+	testREHO := []Test{
+		{"host used by another instance", true, ownedErr != nil && strings.Contains(ownedErr.Error(), "other/other-app")},
+		{"host not claimed", "", ownedClaim},
+		{"no error", nil, err},
+		{"host claimed", "my-app.example.com", claim},
+		{"release error", nil, releaseErr},
+		{"host released", "", runtimecomponent.Status.References[common.StatusReferenceHost]},
+	}
+	verifyTests(testREHO, t)
+}
+
+func TestReconcileExposureHostPrecedence(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	manageTLS := false
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, Expose: &expose, ManageTLS: &manageTLS,
+		Route: &appstacksv1.RuntimeComponentRoute{Host: "my-app.example.com"}}
+	older := createRuntimeComponent(name, namespace, spec)
+	older.UID, older.CreationTimestamp = "b", metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := createRuntimeComponent("my-app-2", namespace, *spec.DeepCopy())
+	newer.UID, newer.CreationTimestamp = "a", metav1.NewTime(time.Now())
+	objs, s := []runtime.Object{older, newer}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, older)
+	routev1.AddToScheme(s)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	r.SetDiscoveryClient(createFakeDiscoveryClient())
+
+	// The newer instance already serves the host, and the older instance claims it later
+	route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: older.Name, Namespace: namespace}}
+	cl.Create(context.TODO(), route)
+	SetHostClaim(newer, "my-app.example.com")
+	instances := []*appstacksv1.RuntimeComponent{older, newer}
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	p.FindHostOwner = func(ba common.BaseComponent, hostPath string) (string, error) {
+		for _, instance := range instances {
+			if instance.UID != ba.(metav1.Object).GetUID() && instance.Status.References[common.StatusReferenceHost] == hostPath && HasHostPrecedence(instance, ba, hostPath) {
+				return instance.Namespace + "/" + instance.Name, nil
+			}
+		}
+		return "", nil
+	}
+	olderErr := p.reconcileExposure(older, &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: older.Name, Namespace: namespace}})
+	newerErr := p.reconcileExposure(newer, &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: newer.Name, Namespace: namespace}})
+	routeErr := cl.Get(context.TODO(), client.ObjectKeyFromObject(route), &routev1.Route{})
+
+	sameTime := createRuntimeComponent("my-app-3", namespace, spec)
+	sameTime.UID, sameTime.CreationTimestamp = "c", older.CreationTimestamp
+	claimed := older.DeepCopy()
+	SetHostClaim(claimed, "my-app.example.com")
+
+	testREHP := []Test{
+		{"older instance error", true, olderErr != nil && strings.Contains(olderErr.Error(), "runtime/my-app-2\"")},
+		{"older instance not claimed", "", older.Status.References[common.StatusReferenceHost]},
+		{"older instance route deleted", true, kerrors.IsNotFound(routeErr)},
+		{"newer instance no error", nil, newerErr},
+		{"newer instance keeps the claim", "my-app.example.com", newer.Status.References[common.StatusReferenceHost]},
+		{"recorded instance precedence", true, HasHostPrecedence(newer, older, "my-app.example.com")},
+		{"unrecorded instance precedence", false, HasHostPrecedence(older, newer, "my-app.example.com")},
+		{"oldest recorded instance precedence", true, HasHostPrecedence(claimed, newer, "my-app.example.com")},
+		{"UID tiebreak", true, HasHostPrecedence(older, sameTime, "my-app.example.com")},
+	}
+	verifyTests(testREHP, t)
+}
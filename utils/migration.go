@@ -0,0 +1,129 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetWorkloadKind returns the kind of workload of the instance: Deployment, StatefulSet or KnativeService
+func GetWorkloadKind(ba common.BaseComponent) string {
+	if ba.GetCreateKnativeService() != nil && *ba.GetCreateKnativeService() {
+		return common.WorkloadKindKnativeService
+	}
+	if ba.GetStatefulSet() != nil {
+		return common.WorkloadKindStatefulSet
+	}
+	return common.WorkloadKindDeployment
+}
+
+// reconcileMigration detects a switch of the workload of the instance to another kind. The previous
+// workload is kept until the new one is ready, see reconcileKnativeService and reconcileWorkload
+func (p *ReconcilePipeline) reconcileMigration(ba common.BaseComponent, state *ReconcileState) error {
+	previous, err := p.getPreviousWorkloadKind(ba, state)
+	if err != nil {
+		return err
+	}
+	state.PreviousWorkloadKind = previous
+	if previous == "" {
+		return nil
+	}
+
+	phase := common.MigrationPhaseWaitingForWorkload
+	if m := ba.GetStatus().GetMigration(); m != nil && m.GetFrom() == previous && m.GetTo() == GetWorkloadKind(ba) {
+		phase = m.GetPhase()
+	}
+	p.setMigrationPhase(ba, state, previous, phase)
+	return nil
+}
+
+// getPreviousWorkloadKind returns the kind of an existing workload of the instance that is not of its
+// current kind, or "" if there is none
+func (p *ReconcilePipeline) getPreviousWorkloadKind(ba common.BaseComponent, state *ReconcileState) (string, error) {
+	workloads := map[string]client.Object{
+		common.WorkloadKindDeployment:  &appsv1.Deployment{},
+		common.WorkloadKindStatefulSet: &appsv1.StatefulSet{},
+	}
+	if state.IsKnativeSupported {
+		workloads[common.WorkloadKindKnativeService] = &servingv1.Service{}
+	}
+
+	kind := GetWorkloadKind(ba)
+	for _, previous := range []string{common.WorkloadKindDeployment, common.WorkloadKindStatefulSet, common.WorkloadKindKnativeService} {
+		workload, ok := workloads[previous]
+		if previous == kind || !ok {
+			continue
+		}
+		err := p.r.GetClient().Get(state.Context, client.ObjectKey{Name: state.DefaultMeta.Name, Namespace: state.DefaultMeta.Namespace}, workload)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if metav1.IsControlledBy(workload, ba.(metav1.Object)) {
+			return previous, nil
+		}
+	}
+	return "", nil
+}
+
+// setMigrationPhase reports the phase of the switch from the previous workload kind in the status
+func (p *ReconcilePipeline) setMigrationPhase(ba common.BaseComponent, state *ReconcileState, from string, phase string) {
+	state.Migrating = true
+	to := GetWorkloadKind(ba)
+	if m := ba.GetStatus().GetMigration(); m == nil || m.GetFrom() != from || m.GetTo() != to || m.GetPhase() != phase {
+		log.Info("Switching workload", "from", from, "to", to, "phase", phase)
+		ba.GetStatus().SetMigration(from, to, phase)
+	}
+}
+
+// finishMigration removes the migration from the status once no step reports it in progress
+func (p *ReconcilePipeline) finishMigration(ba common.BaseComponent, state *ReconcileState) {
+	m := ba.GetStatus().GetMigration()
+	if state.Migrating || m == nil {
+		return
+	}
+	p.r.GetRecorder().Event(ba.(client.Object), "Normal", "WorkloadMigrated", fmt.Sprintf("Switched the workload from %s to %s.", m.GetFrom(), m.GetTo()))
+	ba.GetStatus().UnsetMigration()
+}
+
+// isKnativeConfigurationReady returns true if the latest revision of the Knative Service is ready. Its route
+// may not be ready yet, as the Service of the previous workload still holds the name of the Knative Service
+func isKnativeConfigurationReady(ksvc *servingv1.Service) bool {
+	for _, condition := range ksvc.Status.Conditions {
+		if condition.Type == servingv1.ServiceConditionConfigurationsReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isServiceOwnedByKnative returns true if the Service is the one that Knative creates for a Knative Service
+func isServiceOwnedByKnative(svc *corev1.Service) bool {
+	owner := metav1.GetControllerOf(svc)
+	return owner != nil && strings.HasPrefix(owner.APIVersion, servingv1.SchemeGroupVersion.Group+"/")
+}
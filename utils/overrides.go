@@ -0,0 +1,198 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Fields of the generated resources that overrides must not change
+var overrideProtectedFields = map[string][]string{
+	"Deployment":              {"spec.selector"},
+	"StatefulSet":             {"spec.selector"},
+	"Service":                 {"spec.selector"},
+	"NetworkPolicy":           {"spec.podSelector"},
+	"HorizontalPodAutoscaler": {"spec.scaleTargetRef"},
+	"ServiceMonitor":          {"spec.selector"},
+	"PodMonitor":              {"spec.selector"},
+}
+
+// Label maps of the generated resources in which overrides may only add new labels
+var overrideProtectedLabels = map[string][]string{
+	"Deployment":     {"spec.template.metadata.labels"},
+	"StatefulSet":    {"spec.template.metadata.labels"},
+	"KnativeService": {"spec.template.metadata.labels"},
+}
+
+// ApplyOverrides applies the patches in .spec.overrides for the given kind to obj, in the order they are listed.
+// Patches that change the name, namespace, owner references, selectors or labels set by the operator are rejected.
+func ApplyOverrides(obj client.Object, kind string, ba common.BaseComponent) error {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	patched := original
+	applied := false
+	for i, override := range ba.GetOverrides() {
+		if override.GetKind() != kind {
+			continue
+		}
+		patched, err = applyOverride(patched, override, obj)
+		if err != nil {
+			return fmt.Errorf("failed to apply .spec.overrides[%d] to %s: %w", i, kind, err)
+		}
+		applied = true
+	}
+	if !applied {
+		return nil
+	}
+
+	if err := checkOverrideProtectedFields(kind, original, patched); err != nil {
+		return err
+	}
+
+	// Decode into a new object, so that fields removed by the patches are cleared
+	newObj := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+	if err := json.Unmarshal(patched, newObj); err != nil {
+		return fmt.Errorf("failed to apply .spec.overrides to %s: %w", kind, err)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(newObj).Elem())
+	return nil
+}
+
+func applyOverride(doc []byte, override common.BaseComponentOverride, dataStruct interface{}) ([]byte, error) {
+	patch, err := yaml.YAMLToJSON([]byte(override.GetPatch()))
+	if err != nil {
+		return nil, err
+	}
+	switch override.GetPatchType() {
+	case common.OverridePatchTypeStrategic:
+		return strategicpatch.StrategicMergePatch(doc, patch, dataStruct)
+	case common.OverridePatchTypeMerge:
+		return jsonpatch.MergePatch(doc, patch)
+	case common.OverridePatchTypeJSON:
+		jsonPatch, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIdempotentJSONPatch(doc, jsonPatch); err != nil {
+			return nil, err
+		}
+		// A field removed by the previous reconcile stays removed when the operator does not set it again
+		options := jsonpatch.NewApplyOptions()
+		options.AllowMissingPathOnRemove = true
+		return jsonPatch.ApplyWithOptions(doc, options)
+	default:
+		return nil, fmt.Errorf("unsupported patch type %s", override.GetPatchType())
+	}
+}
+
+// checkIdempotentJSONPatch rejects the operations of a JSON patch that would change the resource again each time it is
+// reconciled. The patches are applied to the resource of the cluster, which keeps the fields that the operator does not
+// set, so adding, removing or copying an element of a list would be repeated on every reconcile
+func checkIdempotentJSONPatch(doc []byte, patch jsonpatch.Patch) error {
+	var parsed interface{}
+	if err := json.Unmarshal(doc, &parsed); err != nil {
+		return err
+	}
+	for _, op := range patch {
+		path, err := op.Path()
+		if err != nil {
+			return err
+		}
+		switch op.Kind() {
+		case "move":
+			return fmt.Errorf("the JSON patch operation move of %s is not supported, use remove and add instead", path)
+		case "add", "remove", "copy":
+			if isJSONPatchListElement(parsed, path) {
+				return fmt.Errorf("the JSON patch operation %s of the list element %s is not supported because it is repeated on every reconcile, use replace or a strategic merge patch instead", op.Kind(), path)
+			}
+		}
+	}
+	return nil
+}
+
+// isJSONPatchListElement returns true if the JSON pointer path refers to an element of a list of doc
+func isJSONPatchListElement(doc interface{}, path string) bool {
+	tokens := strings.Split(path, "/")
+	if len(tokens) < 2 {
+		return false
+	}
+	parent := doc
+	for _, token := range tokens[1 : len(tokens)-1] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch value := parent.(type) {
+		case map[string]interface{}:
+			parent = value[token]
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(value) {
+				return false
+			}
+			parent = value[i]
+		default:
+			return false
+		}
+	}
+	_, isList := parent.([]interface{})
+	return isList
+}
+
+func checkOverrideProtectedFields(kind string, original []byte, patched []byte) error {
+	before, after := map[string]interface{}{}, map[string]interface{}{}
+	if err := json.Unmarshal(original, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return err
+	}
+
+	fields := append([]string{"metadata.name", "metadata.namespace", "metadata.ownerReferences"}, overrideProtectedFields[kind]...)
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		beforeValue, _, _ := unstructured.NestedFieldNoCopy(before, path...)
+		afterValue, _, _ := unstructured.NestedFieldNoCopy(after, path...)
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			return fmt.Errorf(".spec.overrides must not change %s of %s", field, kind)
+		}
+	}
+
+	labelFields := append([]string{"metadata.labels"}, overrideProtectedLabels[kind]...)
+	for _, field := range labelFields {
+		path := strings.Split(field, ".")
+		beforeLabels, _, _ := unstructured.NestedStringMap(before, path...)
+		afterLabels, _, _ := unstructured.NestedStringMap(after, path...)
+		for key, value := range beforeLabels {
+			if afterValue, ok := afterLabels[key]; !ok || afterValue != value {
+				return fmt.Errorf(".spec.overrides must not change label %s in %s of %s", key, field, kind)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	jsonPatch, mergePatch := "json", "merge"
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Status.ImageReference = appImage
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Deployment", Patch: "spec:\n  minReadySeconds: 10\n  revisionHistoryLimit: 3\n"},
+		{Kind: "Deployment", PatchType: &mergePatch, Patch: `{"spec": {"template": {"spec": {"shareProcessNamespace": true}}}}`},
+		{Kind: "Service", PatchType: &jsonPatch, Patch: `[{"op": "add", "path": "/spec/externalTrafficPolicy", "value": "Local"}]`},
+	}
+
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	CustomizeDeployment(deploy, runtime)
+	CustomizePodSpec(&deploy.Spec.Template, runtime)
+	deployErr := ApplyOverrides(deploy, "Deployment", runtime)
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	CustomizeService(svc, runtime)
+	svcErr := ApplyOverrides(svc, "Service", runtime)
+
+	// Overrides of other kinds are not applied
+	headless := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name + "-headless", Namespace: namespace}}
+	CustomizeService(headless, runtime)
+	ApplyOverrides(headless, "ServiceAccount", runtime)
+
+	// Changing the selector or an operator label is rejected
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Deployment", Patch: "spec:\n  selector:\n    matchLabels:\n      app: other\n"},
+	}
+	selectorErr := ApplyOverrides(deploy, "Deployment", runtime)
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Service", PatchType: &mergePatch, Patch: `{"metadata": {"labels": {"app.kubernetes.io/instance": null}}}`},
+	}
+	labelErr := ApplyOverrides(svc, "Service", runtime)
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Service", PatchType: &jsonPatch, Patch: `{"op": "add"}`},
+	}
+	invalidErr := ApplyOverrides(svc, "Service", runtime)
+
+	// Operations on list elements would be repeated on every reconcile
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Deployment", PatchType: &jsonPatch, Patch: `[{"op": "add", "path": "/spec/template/spec/containers/0/args/-", "value": "--debug"}]`},
+	}
+	appendErr := ApplyOverrides(deploy, "Deployment", runtime)
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Deployment", PatchType: &jsonPatch, Patch: `[{"op": "remove", "path": "/spec/template/spec/containers/0"}]`},
+	}
+	removeElementErr := ApplyOverrides(deploy, "Deployment", runtime)
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Service", PatchType: &jsonPatch, Patch: `[{"op": "move", "from": "/spec/externalTrafficPolicy", "path": "/spec/internalTrafficPolicy"}]`},
+	}
+	moveErr := ApplyOverrides(svc, "Service", runtime)
+
+	// Removing a field that is already removed succeeds on the next reconcile
+	runtime.Spec.Overrides = []appstacksv1.RuntimeComponentOverride{
+		{Kind: "Service", PatchType: &jsonPatch, Patch: `[{"op": "remove", "path": "/spec/externalTrafficPolicy"}]`},
+	}
+	removed := svc.DeepCopy()
+	removeErr := ApplyOverrides(removed, "Service", runtime)
+	removedAgainErr := ApplyOverrides(removed, "Service", runtime)
+
+	testAO := []Test{
+		{"no error for Deployment", nil, deployErr},
+		{"strategic merge patch", int32(10), deploy.Spec.MinReadySeconds},
+		{"strategic merge patch second field", int32(3), *deploy.Spec.RevisionHistoryLimit},
+		{"merge patch", true, *deploy.Spec.Template.Spec.ShareProcessNamespace},
+		{"generated fields are kept", appImage, deploy.Spec.Template.Spec.Containers[0].Image},
+		{"no error for Service", nil, svcErr},
+		{"json patch", corev1.ServiceExternalTrafficPolicyLocal, svc.Spec.ExternalTrafficPolicy},
+		{"other kinds are not patched", corev1.ServiceExternalTrafficPolicy(""), headless.Spec.ExternalTrafficPolicy},
+		{"selector change is rejected", true, selectorErr != nil && strings.Contains(selectorErr.Error(), "spec.selector")},
+		{"label change is rejected", true, labelErr != nil && strings.Contains(labelErr.Error(), "app.kubernetes.io/instance")},
+		{"invalid patch is reported", true, invalidErr != nil && strings.Contains(invalidErr.Error(), ".spec.overrides[0]")},
+		{"list append is rejected", true, appendErr != nil && strings.Contains(appendErr.Error(), "/spec/template/spec/containers/0/args/-")},
+		{"list element removal is rejected", true, removeElementErr != nil && strings.Contains(removeElementErr.Error(), "list element")},
+		{"move is rejected", true, moveErr != nil && strings.Contains(moveErr.Error(), "move")},
+		{"no error on removal", nil, removeErr},
+		{"field removed", corev1.ServiceExternalTrafficPolicy(""), removed.Spec.ExternalTrafficPolicy},
+		{"no error on repeated removal", nil, removedAgainErr},
+	}
+	verifyTests(testAO, t)
+}
@@ -0,0 +1,881 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	routev1 "github.com/openshift/api/route/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ReconcileStepName identifies a step of a ReconcilePipeline
+type ReconcileStepName string
+
+const (
+	ReconcileStepServiceAccount    ReconcileStepName = "ServiceAccount"
+	ReconcileStepMigration         ReconcileStepName = "Migration"
+	ReconcileStepKnativeService    ReconcileStepName = "KnativeService"
+	ReconcileStepCertificates      ReconcileStepName = "Certificates"
+	ReconcileStepService           ReconcileStepName = "Service"
+	ReconcileStepCertificateExpiry ReconcileStepName = "CertificateExpiry"
+	ReconcileStepNetworkPolicy     ReconcileStepName = "NetworkPolicy"
+	ReconcileStepBindings          ReconcileStepName = "Bindings"
+	ReconcileStepWorkload          ReconcileStepName = "Workload"
+	ReconcileStepAutoscaling       ReconcileStepName = "Autoscaling"
+	ReconcileStepExposure          ReconcileStepName = "Exposure"
+	ReconcileStepMonitoring        ReconcileStepName = "Monitoring"
+	ReconcileStepExtraResources    ReconcileStepName = "ExtraResources"
+)
+
+// Steps of the resources of the Deployment or StatefulSet of an instance, which are skipped when the workload of the
+// instance is a Knative Service, see ReconcileState.IsKnativeService
+var workloadReconcileSteps = map[ReconcileStepName]bool{
+	ReconcileStepCertificates:      true,
+	ReconcileStepService:           true,
+	ReconcileStepCertificateExpiry: true,
+	ReconcileStepNetworkPolicy:     true,
+	ReconcileStepBindings:          true,
+	ReconcileStepWorkload:          true,
+	ReconcileStepAutoscaling:       true,
+	ReconcileStepExposure:          true,
+}
+
+// ReconcileState holds the state shared by the steps of a single run of a ReconcilePipeline
+type ReconcileState struct {
+	Context context.Context
+	// DefaultMeta holds the name and namespace shared by most resources of the instance
+	DefaultMeta metav1.ObjectMeta
+	// IsKnativeSupported is true if Knative Serving is installed on the cluster
+	IsKnativeSupported bool
+	// IsOpenTelemetrySupported is true if the OpenTelemetry Operator is installed on the cluster
+	IsOpenTelemetrySupported bool
+	// UseCertManager is true if the service certificate is issued by cert-manager. It is set by the Certificates step
+	UseCertManager bool
+	// CertificateProvider is the name of the CertificateProvider that issued the secret of the service certificate,
+	// or "". It is set by the Certificates step
+	CertificateProvider string
+	// Complete stops the pipeline after the current step and reports the instance as reconciled
+	Complete bool
+	// IsKnativeService is true if the workload of the instance is a Knative Service. The built-in steps of the resources
+	// of a Deployment or StatefulSet are then skipped, and the other steps run. It is set by the KnativeService step
+	IsKnativeService bool
+	// PreviousWorkloadKind is the kind of the workload being replaced when the workload of the instance
+	// switches to another kind, or "". It is set by the Migration step
+	PreviousWorkloadKind string
+	// Migrating is true while the switch to another workload kind is in progress
+	Migrating bool
+	// Values lets custom steps pass data to later steps
+	Values map[string]interface{}
+}
+
+// ReconcileStepFunc reconciles part of the resources of an instance. A returned error stops the
+// pipeline and is reported in the Reconciled condition
+type ReconcileStepFunc func(ba common.BaseComponent, state *ReconcileState) error
+
+// ReconcileStep is a named step of a ReconcilePipeline
+type ReconcileStep struct {
+	Name ReconcileStepName
+	Run  ReconcileStepFunc
+}
+
+// ReconcilePipeline reconciles the resources of a BaseComponent through ordered, named steps.
+// Operators built on BaseComponent can insert, replace or skip steps before running it.
+type ReconcilePipeline struct {
+	r     *ReconcilerBase
+	steps []ReconcileStep
+
+	prefix       string
+	caCommonName string
+	operatorName string
+
+	// OnReconciled is called once all steps succeed, before the status of the instance is updated
+	OnReconciled func(ba common.BaseComponent)
+
+	// CertificateProviders issue the service certificate. The Certificates step uses the first available one
+	CertificateProviders []CertificateProvider
+
+	// IsOperatorCAInUse returns true if an instance of the namespace uses the operator CA, see UsesOperatorCA. When set,
+	// the operator CA of the namespace is deleted once it is no longer used, see CleanupOperatorCA
+	IsOperatorCAInUse func(namespace string) (bool, error)
+
+	// ScheduleOperatorCACleanup is called with the time left before the unused operator CA of a namespace is deleted,
+	// see OperatorCACleanup
+	ScheduleOperatorCACleanup func(after time.Duration)
+
+	// FindHostOwner returns the namespace/name of another instance that claims the host and path and has precedence,
+	// see HasHostPrecedence, or "". When set, the Exposure step refuses and releases a host and path owned by another instance
+	FindHostOwner func(ba common.BaseComponent, hostPath string) (string, error)
+}
+
+// NewReconcilePipeline returns a pipeline with the default steps. prefix, CACommonName and operatorName
+// are used for the cert-manager resources shared by the namespace, see GenerateSvcCertSecret
+func (r *ReconcilerBase) NewReconcilePipeline(prefix string, CACommonName string, operatorName string) *ReconcilePipeline {
+	p := &ReconcilePipeline{r: r, prefix: prefix, caCommonName: CACommonName, operatorName: operatorName}
+	p.CertificateProviders = []CertificateProvider{
+		&CertManagerCertificateProvider{Prefix: prefix, CACommonName: CACommonName, OperatorName: operatorName},
+		&OpenShiftCertificateProvider{},
+		&CSRCertificateProvider{},
+	}
+	p.steps = []ReconcileStep{
+		{Name: ReconcileStepServiceAccount, Run: p.reconcileServiceAccount},
+		{Name: ReconcileStepMigration, Run: p.reconcileMigration},
+		{Name: ReconcileStepKnativeService, Run: p.reconcileKnativeService},
+		{Name: ReconcileStepCertificates, Run: p.reconcileCertificates},
+		{Name: ReconcileStepService, Run: p.reconcileService},
+		{Name: ReconcileStepCertificateExpiry, Run: p.reconcileCertificateExpiry},
+		{Name: ReconcileStepNetworkPolicy, Run: p.reconcileNetworkPolicy},
+		{Name: ReconcileStepBindings, Run: p.reconcileBindings},
+		{Name: ReconcileStepWorkload, Run: p.reconcileWorkload},
+		{Name: ReconcileStepAutoscaling, Run: p.reconcileAutoscaling},
+		{Name: ReconcileStepExposure, Run: p.reconcileExposure},
+		{Name: ReconcileStepMonitoring, Run: p.reconcileMonitoring},
+		{Name: ReconcileStepExtraResources, Run: p.reconcileExtraResources},
+	}
+	return p
+}
+
+// Steps returns the names of the steps in the order they run
+func (p *ReconcilePipeline) Steps() []ReconcileStepName {
+	names := make([]ReconcileStepName, len(p.steps))
+	for i := range p.steps {
+		names[i] = p.steps[i].Name
+	}
+	return names
+}
+
+// Append adds a step at the end of the pipeline
+func (p *ReconcilePipeline) Append(step ReconcileStep) {
+	p.steps = append(p.steps, step)
+}
+
+// InsertBefore adds a step before the step with the given name
+func (p *ReconcilePipeline) InsertBefore(name ReconcileStepName, step ReconcileStep) error {
+	i, err := p.indexOf(name)
+	if err != nil {
+		return err
+	}
+	p.steps = append(p.steps[:i], append([]ReconcileStep{step}, p.steps[i:]...)...)
+	return nil
+}
+
+// InsertAfter adds a step after the step with the given name
+func (p *ReconcilePipeline) InsertAfter(name ReconcileStepName, step ReconcileStep) error {
+	i, err := p.indexOf(name)
+	if err != nil {
+		return err
+	}
+	p.steps = append(p.steps[:i+1], append([]ReconcileStep{step}, p.steps[i+1:]...)...)
+	return nil
+}
+
+// Replace replaces the function of the step with the given name
+func (p *ReconcilePipeline) Replace(name ReconcileStepName, run ReconcileStepFunc) error {
+	i, err := p.indexOf(name)
+	if err != nil {
+		return err
+	}
+	p.steps[i].Run = run
+	return nil
+}
+
+// Skip removes the step with the given name from the pipeline
+func (p *ReconcilePipeline) Skip(name ReconcileStepName) error {
+	i, err := p.indexOf(name)
+	if err != nil {
+		return err
+	}
+	p.steps = append(p.steps[:i], p.steps[i+1:]...)
+	return nil
+}
+
+func (p *ReconcilePipeline) indexOf(name ReconcileStepName) (int, error) {
+	for i := range p.steps {
+		if p.steps[i].Name == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("reconcile step %s is not in the pipeline", name)
+}
+
+// ReconcileBaseComponent runs the steps of the pipeline in order and updates the status of the instance.
+// The instance is expected to be initialized and validated.
+func (p *ReconcilePipeline) ReconcileBaseComponent(ctx context.Context, ba common.BaseComponent) (reconcile.Result, error) {
+	obj := ba.(metav1.Object)
+	logger := log.WithValues("ba.Namespace", obj.GetNamespace(), "ba.Name", obj.GetName())
+	state := &ReconcileState{
+		Context: ctx,
+		DefaultMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+		Values: map[string]interface{}{},
+	}
+
+	isKnativeSupported, err := p.r.IsGroupVersionSupported(servingv1.SchemeGroupVersion.String(), "Service")
+	if err != nil {
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+	}
+	state.IsKnativeSupported = isKnativeSupported
+
+	isOpenTelemetrySupported, err := p.r.IsGroupVersionSupported(OpenTelemetryInstrumentationGVK.GroupVersion().String(), OpenTelemetryInstrumentationGVK.Kind)
+	if err != nil {
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+	}
+	state.IsOpenTelemetrySupported = isOpenTelemetrySupported
+
+	for _, step := range p.steps {
+		if state.IsKnativeService && workloadReconcileSteps[step.Name] {
+			continue
+		}
+		if err := step.Run(ba, state); err != nil {
+			logger.Error(err, "Failed to reconcile", "step", step.Name)
+			return p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+		}
+		if state.Complete {
+			break
+		}
+	}
+	p.finishMigration(ba, state)
+
+	if p.OnReconciled != nil {
+		p.OnReconciled(ba)
+	}
+	return p.r.ManageSuccess(common.StatusConditionTypeReconciled, ba)
+}
+
+func (p *ReconcilePipeline) reconcileServiceAccount(ba common.BaseComponent, state *ReconcileState) error {
+	serviceAccountName := GetServiceAccountName(ba)
+	if serviceAccountName != state.DefaultMeta.Name {
+		serviceAccount := &corev1.ServiceAccount{ObjectMeta: state.DefaultMeta}
+		if serviceAccountName == "" {
+			err := p.r.CreateOrUpdate(serviceAccount, ba.(metav1.Object), func() error {
+				if err := CustomizeServiceAccount(serviceAccount, ba, p.r.GetClient()); err != nil {
+					return err
+				}
+				return ApplyOverrides(serviceAccount, "ServiceAccount", ba)
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			// delete our SA, as one has been specified
+			if err := p.r.DeleteResource(serviceAccount); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Check if the ServiceAccount has a valid pull secret before creating the deployment/statefulset
+	// or setting up knative. Otherwise the pods can go into an ImagePullBackOff loop
+	return ServiceAccountPullSecretExists(ba, p.r.GetClient())
+}
+
+func (p *ReconcilePipeline) reconcileKnativeService(ba common.BaseComponent, state *ReconcileState) error {
+	obj := ba.(metav1.Object)
+	if ba.GetCreateKnativeService() == nil || !*ba.GetCreateKnativeService() {
+		// A previous Knative Service keeps serving traffic until the new workload is ready, see reconcileWorkload
+		if state.IsKnativeSupported && state.PreviousWorkloadKind != common.WorkloadKindKnativeService {
+			ksvc := &servingv1.Service{ObjectMeta: state.DefaultMeta}
+			if err := p.r.DeleteResource(ksvc); err != nil {
+				log.Error(err, "Failed to delete Knative Service")
+				p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+			}
+		}
+		return nil
+	}
+
+	if !state.IsKnativeSupported {
+		return ErrKnativeNotSupported
+	}
+	ksvc := &servingv1.Service{ObjectMeta: state.DefaultMeta}
+	err := p.r.CreateOrUpdate(ksvc, obj, func() error {
+		CustomizeOpenTelemetryInstrumentation(&ksvc.Spec.Template.ObjectMeta, ba, "", state.IsOpenTelemetrySupported)
+		CustomizeKnativeService(ksvc, ba)
+		return ApplyOverrides(ksvc, "KnativeService", ba)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Nothing else is created for a Knative Service, apart from the monitoring and extra resources
+	state.IsKnativeService = true
+	if state.PreviousWorkloadKind != "" && !isKnativeConfigurationReady(ksvc) {
+		// The previous workload and its Service keep serving traffic until the Knative Service is ready
+		p.setMigrationPhase(ba, state, state.PreviousWorkloadKind, common.MigrationPhaseWaitingForWorkload)
+		return nil
+	}
+
+	// Clean up non-Knative resources. Knative then creates its own Service with the name of the instance
+	resources := []client.Object{
+		&corev1.Service{ObjectMeta: state.DefaultMeta},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: obj.GetName() + "-headless", Namespace: obj.GetNamespace()}},
+		&appsv1.Deployment{ObjectMeta: state.DefaultMeta},
+		&appsv1.StatefulSet{ObjectMeta: state.DefaultMeta},
+		&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: state.DefaultMeta},
+		&networkingv1.NetworkPolicy{ObjectMeta: state.DefaultMeta},
+	}
+	if err := p.r.DeleteResources(resources); err != nil {
+		return err
+	}
+	if err := p.reconcilePrometheusAdapterRules(ba, nil); err != nil {
+		return err
+	}
+
+	if ok, _ := p.r.IsGroupVersionSupported(networkingv1.SchemeGroupVersion.String(), "Ingress"); ok {
+		p.r.DeleteResource(&networkingv1.Ingress{ObjectMeta: state.DefaultMeta})
+	}
+
+	if p.r.IsOpenShift() {
+		if err := p.r.DeleteResource(&routev1.Route{ObjectMeta: state.DefaultMeta}); err != nil {
+			return err
+		}
+	}
+	SetHostClaim(ba, "")
+	state.Migrating = false
+	return nil
+}
+
+func (p *ReconcilePipeline) reconcileCertificates(ba common.BaseComponent, state *ReconcileState) error {
+	// The operator CA of the namespace is checked when the instance stops using it
+	if UsesOperatorCA(ba) {
+		ba.GetStatus().SetReference(common.StatusReferenceOperatorCAIssuer, p.prefix+"-ca-issuer")
+	} else if _, ok := ba.GetStatus().GetReferences()[common.StatusReferenceOperatorCAIssuer]; ok {
+		remaining, err := p.CleanupOperatorCA(state.DefaultMeta.Namespace, ba.GetGroupName())
+		if err != nil {
+			return err
+		}
+		if remaining > 0 && p.ScheduleOperatorCACleanup != nil {
+			p.ScheduleOperatorCACleanup(remaining)
+		}
+		delete(ba.GetStatus().GetReferences(), common.StatusReferenceOperatorCAIssuer)
+	}
+	delete(ba.GetStatus().GetReferences(), common.StatusReferenceCertSecretName)
+	delete(ba.GetStatus().GetReferences(), common.StatusReferenceClientCertSecretName)
+	var provider CertificateProvider
+	if shouldGenerateSvcCertificate(ba) {
+		// .spec.service.certificate is validated once for all the certificate providers
+		if ba.GetService() != nil && ba.GetService().GetCertificate() != nil {
+			if err := ValidateSvcCertificate(ba.GetService().GetCertificate()); err != nil {
+				return err
+			}
+		}
+		for _, cp := range p.CertificateProviders {
+			ok, err := cp.IsAvailable(p.r)
+			if err != nil {
+				return err
+			}
+			if ok {
+				provider = cp
+				break
+			}
+		}
+	}
+	for _, cp := range p.CertificateProviders {
+		if cp == provider {
+			continue
+		}
+		if err := cp.DeleteCertificate(p.r, ba); err != nil {
+			return err
+		}
+	}
+	state.CertificateProvider = ""
+	if provider != nil {
+		secretName, err := provider.ReconcileCertificate(p.r, ba, state)
+		if err != nil {
+			return err
+		}
+		if secretName != "" {
+			state.CertificateProvider = provider.Name()
+			ba.GetStatus().SetReference(common.StatusReferenceCertSecretName, secretName)
+		}
+	}
+	useCertmanager := state.CertificateProvider == CertificateProviderCertManager
+	state.UseCertManager = useCertmanager
+	if sc := ba.GetService().GetCertificate(); !useCertmanager && sc != nil && sc.GetKeystores() != nil {
+		return errors.New(".spec.service.certificate.keystores requires a service certificate generated by cert-manager. Install cert-manager, and do not set .spec.service.certificateSecretRef or disable .spec.manageTLS")
+	}
+	if !useCertmanager && IsClientCertificateEnabled(ba) {
+		return errors.New(".spec.service.certificate.clientAuth requires a service certificate generated by cert-manager. Install cert-manager, and do not set .spec.service.certificateSecretRef or disable .spec.manageTLS")
+	}
+	if ba.GetService().GetCertificateSecretRef() != nil {
+		ba.GetStatus().SetReference(common.StatusReferenceCertSecretName, *ba.GetService().GetCertificateSecretRef())
+	}
+
+	if !IsCABundleInjected(ba) {
+		delete(ba.GetStatus().GetReferences(), common.StatusReferenceCABundleName)
+		return nil
+	}
+	// The CA bundle is maintained with the operator CA, which may not be used by the instance itself
+	if err := p.r.GenerateCMIssuer(state.DefaultMeta.Namespace, p.prefix, p.caCommonName, p.operatorName); err != nil {
+		if errors.Is(err, APIVersionNotFoundError) {
+			return errors.New(".spec.trust.injectCABundle requires cert-manager to be installed")
+		}
+		return err
+	}
+	ba.GetStatus().SetReference(common.StatusReferenceCABundleName, GetCABundleName(p.prefix))
+	return nil
+}
+
+func (p *ReconcilePipeline) reconcileService(ba common.BaseComponent, state *ReconcileState) error {
+	// The name of the Service belongs to a previous Knative Service until the new workload is ready
+	if state.PreviousWorkloadKind == common.WorkloadKindKnativeService {
+		return nil
+	}
+	svc := &corev1.Service{ObjectMeta: state.DefaultMeta}
+	if err := p.r.GetClient().Get(state.Context, client.ObjectKeyFromObject(svc), svc); err == nil && isServiceOwnedByKnative(svc) {
+		// Wait for Knative to remove the Service of the previous Knative Service
+		p.setMigrationPhase(ba, state, common.WorkloadKindKnativeService, common.MigrationPhaseSwitchingTraffic)
+		state.Complete = true
+		return nil
+	}
+	return p.r.CreateOrUpdate(svc, ba.(metav1.Object), func() error {
+		CustomizeService(svc, ba)
+		svc.Annotations = MergeMaps(svc.Annotations, ba.GetService().GetAnnotations())
+		if state.CertificateProvider == "" && p.r.IsOpenShift() {
+			AddOCPCertAnnotation(ba, svc)
+		}
+		monitoringEnabledLabelName := GetMonitoringEnabledLabelName(ba)
+		if ba.GetMonitoring() != nil {
+			svc.Labels[monitoringEnabledLabelName] = "true"
+		} else {
+			delete(svc.Labels, monitoringEnabledLabelName)
+		}
+		return ApplyOverrides(svc, "Service", ba)
+	})
+}
+
+func (p *ReconcilePipeline) reconcileNetworkPolicy(ba common.BaseComponent, state *ReconcileState) error {
+	// A NetworkPolicy would block the traffic to a previous Knative Service
+	if state.PreviousWorkloadKind == common.WorkloadKindKnativeService {
+		return nil
+	}
+	networkPolicy := &networkingv1.NetworkPolicy{ObjectMeta: state.DefaultMeta}
+	if np := ba.GetNetworkPolicy(); np != nil && np.IsDisabled() {
+		return p.r.DeleteResource(networkPolicy)
+	}
+	return p.r.CreateOrUpdate(networkPolicy, ba.(metav1.Object), func() error {
+		CustomizeNetworkPolicy(networkPolicy, p.r.IsOpenShift(), ba)
+		return ApplyOverrides(networkPolicy, "NetworkPolicy", ba)
+	})
+}
+
+func (p *ReconcilePipeline) reconcileBindings(ba common.BaseComponent, state *ReconcileState) error {
+	return p.r.ReconcileBindings(ba)
+}
+
+func (p *ReconcilePipeline) reconcileWorkload(ba common.BaseComponent, state *ReconcileState) error {
+	obj := ba.(metav1.Object)
+	headlessMeta := metav1.ObjectMeta{Name: obj.GetName() + "-headless", Namespace: obj.GetNamespace()}
+
+	if ba.GetStatefulSet() != nil {
+		svc := &corev1.Service{ObjectMeta: headlessMeta}
+		err := p.r.CreateOrUpdate(svc, obj, func() error {
+			CustomizeService(svc, ba)
+			svc.Spec.ClusterIP = corev1.ClusterIPNone
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		statefulSet := &appsv1.StatefulSet{ObjectMeta: state.DefaultMeta}
+		err = p.r.CreateOrUpdate(statefulSet, obj, func() error {
+			CustomizeStatefulSet(statefulSet, ba)
+			CustomizeOpenTelemetryInstrumentation(&statefulSet.Spec.Template.ObjectMeta, ba, "app", state.IsOpenTelemetrySupported)
+			CustomizePodSpec(&statefulSet.Spec.Template, ba)
+			if err := CustomizePodWithSVCCertificate(&statefulSet.Spec.Template, ba, p.r.GetClient()); err != nil {
+				return err
+			}
+			CustomizePersistence(statefulSet, ba)
+			return ApplyOverrides(statefulSet, "StatefulSet", ba)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		deploy := &appsv1.Deployment{ObjectMeta: state.DefaultMeta}
+		err := p.r.CreateOrUpdate(deploy, obj, func() error {
+			CustomizeDeployment(deploy, ba)
+			CustomizeOpenTelemetryInstrumentation(&deploy.Spec.Template.ObjectMeta, ba, "app", state.IsOpenTelemetrySupported)
+			CustomizePodSpec(&deploy.Spec.Template, ba)
+			if err := CustomizePodWithSVCCertificate(&deploy.Spec.Template, ba, p.r.GetClient()); err != nil {
+				return err
+			}
+			return ApplyOverrides(deploy, "Deployment", ba)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The previous workload keeps serving traffic until the new one is ready. The Service selects the pods of both
+	if state.PreviousWorkloadKind != "" && !p.r.isWorkloadReady(ba) {
+		p.setMigrationPhase(ba, state, state.PreviousWorkloadKind, common.MigrationPhaseWaitingForWorkload)
+		if state.PreviousWorkloadKind == common.WorkloadKindKnativeService {
+			// The Service and the exposure are created once the Knative Service is removed
+			state.Complete = true
+		}
+		return nil
+	}
+
+	if ba.GetStatefulSet() != nil {
+		// Delete Deployment if exists
+		if err := p.r.DeleteResource(&appsv1.Deployment{ObjectMeta: state.DefaultMeta}); err != nil {
+			return err
+		}
+	} else {
+		// Delete StatefulSet and its headless Service if they exist
+		if err := p.r.DeleteResource(&appsv1.StatefulSet{ObjectMeta: state.DefaultMeta}); err != nil {
+			return err
+		}
+		if err := p.r.DeleteResource(&corev1.Service{ObjectMeta: headlessMeta}); err != nil {
+			return err
+		}
+	}
+	if state.PreviousWorkloadKind == common.WorkloadKindKnativeService {
+		// Knative removes its Service with the Knative Service. The Service of the instance is created once it is gone
+		if err := p.r.DeleteResource(&servingv1.Service{ObjectMeta: state.DefaultMeta}); err != nil {
+			return err
+		}
+		p.setMigrationPhase(ba, state, common.WorkloadKindKnativeService, common.MigrationPhaseSwitchingTraffic)
+		state.Complete = true
+		return nil
+	}
+	state.Migrating = false
+	return nil
+}
+
+func (p *ReconcilePipeline) reconcileAutoscaling(ba common.BaseComponent, state *ReconcileState) error {
+	obj := ba.(metav1.Object)
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: state.DefaultMeta}
+	if err := p.reconcilePrometheusAdapterRules(ba, getPrometheusAdapterRules(ba)); err != nil {
+		return err
+	}
+	if ba.GetAutoscaling() == nil {
+		return p.r.DeleteResource(hpa)
+	}
+	return p.r.CreateOrUpdate(hpa, obj, func() error {
+		CustomizeHPA(hpa, ba)
+		return ApplyOverrides(hpa, "HorizontalPodAutoscaler", ba)
+	})
+}
+
+// reconcilePrometheusAdapterRules sets the external rules of the instance in the ConfigMap of the configuration of the
+// Prometheus Adapter, and removes them from the ConfigMap referenced in the status when they are no longer needed or the
+// ConfigMap changed in the operator ConfigMap
+func (p *ReconcilePipeline) reconcilePrometheusAdapterRules(ba common.BaseComponent, rules []prometheusAdapterRule) error {
+	obj := ba.(metav1.Object)
+	key := types.NamespacedName{}
+	if len(rules) > 0 {
+		key = GetPrometheusAdapterConfigMap()
+	}
+	if previousKey, ok := getPrometheusAdapterReference(ba); ok && previousKey != key {
+		if err := p.r.RemovePrometheusAdapterRules(previousKey, obj.GetNamespace(), obj.GetName()); err != nil {
+			return err
+		}
+		delete(ba.GetStatus().GetReferences(), common.StatusReferencePrometheusAdapter)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	if err := p.r.updatePrometheusAdapterRules(key, obj.GetNamespace(), obj.GetName(), rules); err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("the ConfigMap %s of the configuration of the Prometheus Adapter is not found, set %s and %s in the operator ConfigMap",
+				key, common.OpConfigPrometheusAdapterConfigMapNamespace, common.OpConfigPrometheusAdapterConfigMapName)
+		}
+		return err
+	}
+	ba.GetStatus().SetReference(common.StatusReferencePrometheusAdapter, key.String())
+	return nil
+}
+
+func (p *ReconcilePipeline) reconcileExposure(ba common.BaseComponent, state *ReconcileState) error {
+	host, hostPath := "", ""
+	if ba.GetExpose() != nil && *ba.GetExpose() {
+		var err error
+		if host, err = GetHostname(ba); err != nil {
+			return err
+		}
+		if hostPath = GetHostClaim(ba, host); hostPath != "" && p.FindHostOwner != nil {
+			owner, err := p.FindHostOwner(ba, hostPath)
+			if err != nil {
+				return err
+			}
+			if owner != "" {
+				// The instance no longer serves the host, which is served by the owner
+				SetHostClaim(ba, "")
+				if err := p.reconcileRouteOrIngress(ba, state, false); err != nil {
+					return err
+				}
+				kind := "instance"
+				if gvk, err := apiutil.GVKForObject(ba.(client.Object), p.r.scheme); err == nil {
+					kind = gvk.Kind
+				}
+				return fmt.Errorf("host %q is already used by %s %q, set a different .spec.route.host or .spec.route.path", hostPath, kind, owner)
+			}
+		}
+	}
+	if err := p.r.ReconcileRouteCertificate(ba, host); err != nil {
+		return err
+	}
+	if err := p.reconcileRouteOrIngress(ba, state, ba.GetExpose() != nil && *ba.GetExpose()); err != nil {
+		return err
+	}
+	SetHostClaim(ba, hostPath)
+	return nil
+}
+
+// reconcileRouteOrIngress creates or updates the Route or Ingress of the instance, or deletes it when expose is false
+func (p *ReconcilePipeline) reconcileRouteOrIngress(ba common.BaseComponent, state *ReconcileState, expose bool) error {
+	obj := ba.(metav1.Object)
+
+	if ok, err := p.r.IsGroupVersionSupported(routev1.SchemeGroupVersion.String(), "Route"); err != nil {
+		log.Error(err, fmt.Sprintf("Failed to check if %s is supported", routev1.SchemeGroupVersion.String()))
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+	} else if ok {
+		route := &routev1.Route{ObjectMeta: state.DefaultMeta}
+		if !expose {
+			return p.r.DeleteResource(route)
+		}
+		if ShouldDeleteRoute(ba) {
+			log.Info("Custom hostname has been removed from route, deleting and recreating the route")
+			if err := p.r.DeleteResource(route); err != nil {
+				return err
+			}
+		}
+		route = &routev1.Route{ObjectMeta: state.DefaultMeta}
+		return p.r.CreateOrUpdate(route, obj, func() error {
+			key, cert, caCert, destCACert, err := p.r.GetRouteTLSValues(ba)
+			if err != nil {
+				return err
+			}
+			CustomizeRoute(route, ba, key, cert, caCert, destCACert)
+			return ApplyOverrides(route, "Route", ba)
+		})
+	} else {
+		if ok, err := p.r.IsGroupVersionSupported(networkingv1.SchemeGroupVersion.String(), "Ingress"); err != nil {
+			log.Error(err, fmt.Sprintf("Failed to check if %s is supported", networkingv1.SchemeGroupVersion.String()))
+			p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+		} else if ok {
+			ing := &networkingv1.Ingress{ObjectMeta: state.DefaultMeta}
+			if !expose {
+				return p.r.DeleteResource(ing)
+			}
+			return p.r.CreateOrUpdate(ing, obj, func() error {
+				CustomizeIngress(ing, ba)
+				return ApplyOverrides(ing, "Ingress", ba)
+			})
+		}
+	}
+	return nil
+}
+
+func (p *ReconcilePipeline) reconcileMonitoring(ba common.BaseComponent, state *ReconcileState) error {
+	obj := ba.(metav1.Object)
+	monitoring := ba.GetMonitoring()
+	// The ServiceMonitor selects the Service, which is not created for Knative services
+	isKnative := ba.GetCreateKnativeService() != nil && *ba.GetCreateKnativeService()
+	if ok, err := p.r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "ServiceMonitor"); err != nil {
+		log.Error(err, fmt.Sprintf("Failed to check if %s is supported", prometheusv1.SchemeGroupVersion.String()))
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+	} else if ok {
+		sm := &prometheusv1.ServiceMonitor{ObjectMeta: state.DefaultMeta}
+		if monitoring == nil || monitoring.GetKind() != common.MonitoringKindServiceMonitor || isKnative {
+			if err := p.r.DeleteResource(sm); err != nil {
+				return err
+			}
+		} else {
+			// Validate the monitoring endpoints' configuration before creating/updating the ServiceMonitor
+			if err := ValidatePrometheusMonitoringEndpoints(ba, p.r.GetClient(), obj.GetNamespace()); err != nil {
+				return err
+			}
+			err := p.r.CreateOrUpdate(sm, obj, func() error {
+				CustomizeServiceMonitor(sm, ba)
+				return ApplyOverrides(sm, "ServiceMonitor", ba)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		log.V(common.LogLevelDebug).Info(fmt.Sprintf("%s is not supported", prometheusv1.SchemeGroupVersion.String()))
+	}
+
+	if ok, err := p.r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "PodMonitor"); err != nil {
+		log.Error(err, fmt.Sprintf("Failed to check if %s is supported", prometheusv1.SchemeGroupVersion.String()))
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+	} else if ok {
+		pm := &prometheusv1.PodMonitor{ObjectMeta: state.DefaultMeta}
+		if monitoring == nil || monitoring.GetKind() != common.MonitoringKindPodMonitor {
+			if err := p.r.DeleteResource(pm); err != nil {
+				return err
+			}
+		} else {
+			// Validate the monitoring endpoints' configuration before creating/updating the PodMonitor
+			if err := ValidatePrometheusMonitoringEndpoints(ba, p.r.GetClient(), obj.GetNamespace()); err != nil {
+				return err
+			}
+			err := p.r.CreateOrUpdate(pm, obj, func() error {
+				CustomizePodMonitor(pm, ba)
+				return ApplyOverrides(pm, "PodMonitor", ba)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if ok, err := p.r.IsGroupVersionSupported(prometheusv1.SchemeGroupVersion.String(), "PrometheusRule"); err != nil {
+		log.Error(err, fmt.Sprintf("Failed to check if %s is supported", prometheusv1.SchemeGroupVersion.String()))
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+	} else if ok {
+		pr := &prometheusv1.PrometheusRule{ObjectMeta: state.DefaultMeta}
+		if monitoring == nil || monitoring.GetAlerts() == nil {
+			if err := p.r.DeleteResource(pr); err != nil {
+				return err
+			}
+		} else {
+			err := p.r.CreateOrUpdate(pr, obj, func() error {
+				CustomizePrometheusRule(pr, ba)
+				return ApplyOverrides(pr, "PrometheusRule", ba)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return p.reconcileDashboard(ba, state)
+}
+
+// reconcileDashboard creates the Grafana dashboard of the instance as a GrafanaDashboard when the Grafana Operator is
+// installed, or as a ConfigMap loaded by the Grafana dashboard sidecar otherwise
+func (p *ReconcilePipeline) reconcileDashboard(ba common.BaseComponent, state *ReconcileState) error {
+	obj := ba.(metav1.Object)
+	useGrafanaOperator, err := p.r.IsGroupVersionSupported(GrafanaDashboardGVK.GroupVersion().String(), GrafanaDashboardGVK.Kind)
+	if err != nil {
+		// The dashboard is loaded by the Grafana dashboard sidecar instead
+		log.Error(err, fmt.Sprintf("Failed to check if %s is supported", GrafanaDashboardGVK.GroupVersion().String()))
+		p.r.ManageError(err, common.StatusConditionTypeReconciled, ba)
+		useGrafanaOperator = false
+	}
+	gd := &unstructured.Unstructured{}
+	gd.SetGroupVersionKind(GrafanaDashboardGVK)
+	gd.SetName(state.DefaultMeta.Name)
+	gd.SetNamespace(state.DefaultMeta.Namespace)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: GetDashboardConfigMapName(ba), Namespace: obj.GetNamespace()}}
+
+	if ba.GetMonitoring() == nil || ba.GetMonitoring().GetDashboard() == nil {
+		if useGrafanaOperator {
+			if err := p.r.DeleteResource(gd); err != nil {
+				return err
+			}
+		}
+		return p.deleteControlledConfigMap(cm, obj)
+	}
+
+	dashboardJSON, err := GetDashboardJSON(ba)
+	if err != nil {
+		return err
+	}
+	if useGrafanaOperator {
+		err := p.r.CreateOrUpdate(gd, obj, func() error {
+			return CustomizeGrafanaDashboard(gd, ba, dashboardJSON)
+		})
+		if err != nil {
+			return err
+		}
+		return p.deleteControlledConfigMap(cm, obj)
+	}
+	return p.r.CreateOrUpdate(cm, obj, func() error {
+		return CustomizeDashboardConfigMap(cm, ba, dashboardJSON)
+	})
+}
+
+// deleteControlledConfigMap deletes a generated ConfigMap, unless it is not controlled by the instance
+func (p *ReconcilePipeline) deleteControlledConfigMap(cm *corev1.ConfigMap, owner metav1.Object) error {
+	if err := p.r.GetClient().Get(context.TODO(), client.ObjectKeyFromObject(cm), cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(cm, owner) {
+		return nil
+	}
+	return p.r.DeleteResource(cm)
+}
+
+func (p *ReconcilePipeline) reconcileExtraResources(ba common.BaseComponent, state *ReconcileState) error {
+	resources, err := GetExtraResources(ba)
+	if err != nil {
+		return err
+	}
+
+	obj := ba.(metav1.Object)
+	current := []corev1.ObjectReference{}
+	for i, desired := range resources {
+		if err := CheckExtraResourceScope(p.r.GetClient().RESTMapper(), desired, i); err != nil {
+			ba.GetStatus().SetExtraResources(mergeObjectReferences(ba.GetStatus().GetExtraResources(), current))
+			return err
+		}
+		resource := &unstructured.Unstructured{}
+		resource.SetGroupVersionKind(desired.GroupVersionKind())
+		resource.SetName(desired.GetName())
+		resource.SetNamespace(desired.GetNamespace())
+		err := p.r.CreateOrUpdate(resource, nil, func() error {
+			// Refuse to take over a resource that is controlled by something else
+			if err := controllerutil.SetControllerReference(obj, resource, p.r.scheme); err != nil {
+				return err
+			}
+			CustomizeExtraResource(resource, desired, ba)
+			return nil
+		})
+		if err != nil {
+			// Keep track of the resources created so far, so that they can still be pruned
+			ba.GetStatus().SetExtraResources(mergeObjectReferences(ba.GetStatus().GetExtraResources(), current))
+			return fmt.Errorf("failed to reconcile %s %s from .spec.extraResources: %w", desired.GetKind(), desired.GetName(), err)
+		}
+		current = append(current, ExtraResourceReference(desired))
+	}
+
+	if err := p.r.PruneExtraResources(ba, current); err != nil {
+		ba.GetStatus().SetExtraResources(mergeObjectReferences(ba.GetStatus().GetExtraResources(), current))
+		return err
+	}
+	if len(current) == 0 {
+		current = nil
+	}
+	ba.GetStatus().SetExtraResources(current)
+	return nil
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	routev1 "github.com/openshift/api/route/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestReconcilePipelineSteps(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	r := NewReconcilerBase(nil, nil, nil, &rest.Config{}, record.NewFakeRecorder(10))
+	noop := func(ba common.BaseComponent, state *ReconcileState) error { return nil }
+
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	defaultSteps := p.Steps()
+	p.InsertBefore(ReconcileStepService, ReconcileStep{Name: "Before", Run: noop})
+	p.InsertAfter(ReconcileStepMonitoring, ReconcileStep{Name: "After", Run: noop})
+	p.Skip(ReconcileStepBindings)
+	replaceErr := p.Replace(ReconcileStepWorkload, noop)
+	unknownErr := p.Skip("Unknown")
+
+	testRPS := []Test{
+		{"default steps", []ReconcileStepName{ReconcileStepServiceAccount, ReconcileStepMigration, ReconcileStepKnativeService, ReconcileStepCertificates, ReconcileStepService,
+			ReconcileStepCertificateExpiry, ReconcileStepNetworkPolicy, ReconcileStepBindings, ReconcileStepWorkload, ReconcileStepAutoscaling, ReconcileStepExposure, ReconcileStepMonitoring, ReconcileStepExtraResources}, defaultSteps},
+		{"modified steps", []ReconcileStepName{ReconcileStepServiceAccount, ReconcileStepMigration, ReconcileStepKnativeService, ReconcileStepCertificates, "Before", ReconcileStepService,
+			ReconcileStepCertificateExpiry, ReconcileStepNetworkPolicy, ReconcileStepWorkload, ReconcileStepAutoscaling, ReconcileStepExposure, ReconcileStepMonitoring, "After", ReconcileStepExtraResources}, p.Steps()},
+		{"replace existing step", nil, replaceErr},
+		{"skip unknown step", "reconcile step Unknown is not in the pipeline", fmt.Sprint(unknownErr)},
+	}
+	verifyTests(testRPS, t)
+}
+
+func TestReconcileBaseComponent(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	rcl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(rcl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	r.SetDiscoveryClient(createFakeDiscoveryClient())
+
+	// Only run custom steps
+	ran := []ReconcileStepName{}
+	newPipeline := func(steps ...ReconcileStepName) *ReconcilePipeline {
+		p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+		for _, step := range p.Steps() {
+			p.Skip(step)
+		}
+		for _, step := range steps {
+			p.Append(ReconcileStep{Name: step, Run: func(ba common.BaseComponent, state *ReconcileState) error {
+				ran = append(ran, step)
+				switch step {
+				case "Complete":
+					state.Complete = true
+				case "Fail":
+					return fmt.Errorf("step failed")
+				}
+				return nil
+			}})
+		}
+		return p
+	}
+
+	p := newPipeline("First", "Complete", "Skipped")
+	reconciled := false
+	p.OnReconciled = func(ba common.BaseComponent) { reconciled = true }
+	p.ReconcileBaseComponent(context.TODO(), runtimecomponent)
+	completedSteps := ran
+	successStatus := runtimecomponent.Status.GetCondition(common.StatusConditionTypeReconciled).GetStatus()
+
+	ran = []ReconcileStepName{}
+	newPipeline("Fail", "Skipped").ReconcileBaseComponent(context.TODO(), runtimecomponent)
+	failedSteps := ran
+	failedCondition := runtimecomponent.Status.GetCondition(common.StatusConditionTypeReconciled)
+
+	testRBC := []Test{
+		{"steps run until complete", []ReconcileStepName{"First", "Complete"}, completedSteps},
+		{"OnReconciled is called", true, reconciled},
+		{"reconciled on success", corev1.ConditionTrue, successStatus},
+		{"steps run until failure", []ReconcileStepName{"Fail"}, failedSteps},
+		{"not reconciled on failure", corev1.ConditionFalse, failedCondition.GetStatus()},
+		{"failure message", "step failed", failedCondition.GetMessage()},
+	}
+	verifyTests(testRBC, t)
+}
+
+func TestReconcileKnativeMonitoring(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	manageTLS, podMonitor := false, common.MonitoringKindPodMonitor
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ManageTLS: &manageTLS, CreateKnativeService: &createKNS,
+		PullPolicy: &pullPolicy, Monitoring: &appstacksv1.RuntimeComponentMonitoring{Kind: &podMonitor}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	servingv1.AddToScheme(s)
+	prometheusv1.AddToScheme(s)
+	routev1.AddToScheme(s)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	discoveryClient := createFakeDiscoveryClient()
+	discoveryClient.(*fakediscovery.FakeDiscovery).Resources = append(discoveryClient.(*fakediscovery.FakeDiscovery).Resources, &metav1.APIResourceList{
+		GroupVersion: prometheusv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "podmonitors", Namespaced: true, Kind: "PodMonitor"}},
+	})
+	r.SetDiscoveryClient(discoveryClient)
+
+	// Only run the steps of a Knative Service that do not depend on other components of the cluster
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	for _, step := range p.Steps() {
+		if step != ReconcileStepKnativeService && step != ReconcileStepMonitoring && step != ReconcileStepExtraResources {
+			p.Skip(step)
+		}
+	}
+	p.ReconcileBaseComponent(context.TODO(), runtimecomponent)
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	ksvcErr := cl.Get(context.TODO(), key, &servingv1.Service{})
+	pmErr := cl.Get(context.TODO(), key, &prometheusv1.PodMonitor{})
+
+	testRKM := []Test{
+		{"reconciled", corev1.ConditionTrue, runtimecomponent.Status.GetCondition(common.StatusConditionTypeReconciled).GetStatus()},
+		{"Knative Service created", nil, ksvcErr},
+		{"PodMonitor created", nil, pmErr},
+	}
+	verifyTests(testRKM, t)
+}
+
+func TestReconcileKnativeSteps(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	manageTLS := false
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ManageTLS: &manageTLS, CreateKnativeService: &createKNS, PullPolicy: &pullPolicy}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	servingv1.AddToScheme(s)
+	routev1.AddToScheme(s)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	r.SetDiscoveryClient(createFakeDiscoveryClient())
+
+	// The steps replaced by a downstream operator run instead of the built-in steps
+	ran := []ReconcileStepName{}
+	record := func(name ReconcileStepName) ReconcileStepFunc {
+		return func(ba common.BaseComponent, state *ReconcileState) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	for _, step := range p.Steps() {
+		switch step {
+		case ReconcileStepKnativeService:
+		case ReconcileStepService, ReconcileStepMonitoring, ReconcileStepExtraResources:
+			p.Replace(step, record(step))
+		default:
+			p.Skip(step)
+		}
+	}
+	p.Append(ReconcileStep{Name: "Custom", Run: record("Custom")})
+	p.ReconcileBaseComponent(context.TODO(), runtimecomponent)
+
+	testRKS := []Test{
+		{"reconciled", corev1.ConditionTrue, runtimecomponent.Status.GetCondition(common.StatusConditionTypeReconciled).GetStatus()},
+		{"workload steps skipped", []ReconcileStepName{ReconcileStepMonitoring, ReconcileStepExtraResources, "Custom"}, ran},
+	}
+	verifyTests(testRKS, t)
+}
+
+func TestReconcileWorkloadMigration(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	manageTLS := false
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ManageTLS: &manageTLS, StatefulSet: &appstacksv1.RuntimeComponentStatefulSet{}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.UID = "my-app-uid"
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	controllerutil.SetControllerReference(runtimecomponent, deploy, s)
+	cl := fakeclient.NewFakeClient(append(objs, deploy)...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	reconcileSteps := func() *ReconcileState {
+		state := &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if err := p.reconcileMigration(runtimecomponent, state); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.reconcileWorkload(runtimecomponent, state); err != nil {
+			t.Fatal(err)
+		}
+		p.finishMigration(runtimecomponent, state)
+		return state
+	}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+
+	// The Deployment is kept until the StatefulSet is ready
+	waitingState := reconcileSteps()
+	waitingMigration := runtimecomponent.Status.Migration.DeepCopy()
+	deployErr := cl.Get(context.TODO(), key, &appsv1.Deployment{})
+
+	statefulSet := &appsv1.StatefulSet{}
+	cl.Get(context.TODO(), key, statefulSet)
+	statefulSet.Status = appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1}
+	cl.Status().Update(context.TODO(), statefulSet)
+	readyState := reconcileSteps()
+	deletedErr := cl.Get(context.TODO(), key, &appsv1.Deployment{})
+
+	testRWM := []Test{
+		{"previous workload kind", common.WorkloadKindDeployment, waitingState.PreviousWorkloadKind},
+		{"waiting for the new workload", &appstacksv1.StatusMigration{From: "Deployment", To: "StatefulSet", Phase: common.MigrationPhaseWaitingForWorkload}, waitingMigration},
+		{"previous workload kept", nil, deployErr},
+		{"migration complete", false, readyState.Migrating},
+		{"migration removed from status", (*appstacksv1.StatusMigration)(nil), runtimecomponent.Status.Migration},
+		{"previous workload deleted", true, apierrors.IsNotFound(deletedErr)},
+	}
+	verifyTests(testRWM, t)
+}
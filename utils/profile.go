@@ -0,0 +1,112 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Lists of the spec that are merged with the list of the profile instead of replacing it
+var profileMergedLists = map[string]bool{
+	"tolerations": true,
+}
+
+// MergeProfile deep-merges the profile spec under spec, which must be a pointer to a struct.
+// Objects are merged field by field and values set in spec take precedence. Lists set in spec
+// replace the list of the profile, except tolerations, which are added to the tolerations of the profile.
+func MergeProfile(spec interface{}, profileSpec interface{}) error {
+	specMap, err := toJSONMap(spec)
+	if err != nil {
+		return err
+	}
+	profileMap, err := toJSONMap(profileSpec)
+	if err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergeProfileValue(profileMap, specMap, ""))
+	if err != nil {
+		return err
+	}
+	newSpec := reflect.New(reflect.TypeOf(spec).Elem()).Interface()
+	if err := json.Unmarshal(merged, newSpec); err != nil {
+		return err
+	}
+	reflect.ValueOf(spec).Elem().Set(reflect.ValueOf(newSpec).Elem())
+	return nil
+}
+
+func mergeProfileValue(profileValue interface{}, value interface{}, path string) interface{} {
+	if value == nil {
+		return profileValue
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		profileMap, ok := profileValue.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		merged := map[string]interface{}{}
+		for key, profileFieldValue := range profileMap {
+			merged[key] = profileFieldValue
+		}
+		for key, fieldValue := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			merged[key] = mergeProfileValue(profileMap[key], fieldValue, fieldPath)
+		}
+		return merged
+	case []interface{}:
+		profileList, ok := profileValue.([]interface{})
+		if !ok || !profileMergedLists[path] {
+			return v
+		}
+		merged := append([]interface{}{}, profileList...)
+		for _, item := range v {
+			if !containsJSONValue(merged, item) {
+				merged = append(merged, item)
+			}
+		}
+		return merged
+	default:
+		return v
+	}
+}
+
+func containsJSONValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toJSONMap(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestMergeProfile(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	mountToken, runAsNonRoot := false, true
+
+	profile := appstacksv1.RuntimeComponentProfileSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+		Tolerations:     []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "apps", Effect: corev1.TaintEffectNoSchedule}},
+		Affinity:        &appstacksv1.RuntimeComponentAffinity{Architecture: []string{"amd64", "arm64"}},
+		SecurityContext: &corev1.SecurityContext{RunAsNonRoot: &runAsNonRoot},
+		ServiceAccount:  &appstacksv1.RuntimeComponentServiceAccount{MountToken: &mountToken},
+	}
+
+	spec := appstacksv1.RuntimeComponentSpec{
+		ApplicationImage: appImage,
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		Tolerations: []corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "apps", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "gpu", Operator: corev1.TolerationOpExists},
+		},
+		Affinity: &appstacksv1.RuntimeComponentAffinity{Architecture: []string{"s390x"}},
+	}
+	err := MergeProfile(&spec, &profile)
+
+	testMP := []Test{
+		{"no error", nil, err},
+		{"image is kept", appImage, spec.ApplicationImage},
+		{"profile value under unset field", "100m", spec.Resources.Requests.Cpu().String()},
+		{"spec value takes precedence", "1Gi", spec.Resources.Requests.Memory().String()},
+		{"tolerations are added", []corev1.Toleration{profile.Tolerations[0], {Key: "gpu", Operator: corev1.TolerationOpExists}}, spec.Tolerations},
+		{"other lists are replaced", []string{"s390x"}, spec.Affinity.Architecture},
+		{"profile object", true, *spec.SecurityContext.RunAsNonRoot},
+		{"profile service account", false, *spec.ServiceAccount.MountToken},
+		{"profile not changed", 1, len(profile.Tolerations)},
+	}
+	verifyTests(testMP, t)
+}
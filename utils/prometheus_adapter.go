@@ -0,0 +1,222 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// prometheusAdapterConfigKey is the key of the configuration in the ConfigMap of the Prometheus Adapter
+	prometheusAdapterConfigKey = "config.yaml"
+
+	// PrometheusAdapterFinalizer is the finalizer of the instances that have rules in the configuration of the
+	// Prometheus Adapter, which is not owned by the instances. The rules are removed before the instance is deleted
+	PrometheusAdapterFinalizer = "rc.app.stacks/prometheus-adapter"
+)
+
+// prometheusAdapterRule is a rule of the Prometheus Adapter that serves the result of a query as an external metric
+type prometheusAdapterRule struct {
+	SeriesQuery string `json:"seriesQuery"`
+	Resources   struct {
+		Overrides map[string]map[string]string `json:"overrides"`
+	} `json:"resources"`
+	Name struct {
+		Matches string `json:"matches"`
+		As      string `json:"as"`
+	} `json:"name"`
+	MetricsQuery string `json:"metricsQuery"`
+}
+
+// GetPrometheusAdapterConfigMap returns the ConfigMap of the configuration of the Prometheus Adapter set in the operator ConfigMap
+func GetPrometheusAdapterConfigMap() types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: common.LoadFromConfig(common.Config, common.OpConfigPrometheusAdapterConfigMapNamespace),
+		Name:      common.LoadFromConfig(common.Config, common.OpConfigPrometheusAdapterConfigMapName),
+	}
+}
+
+// getPrometheusAdapterReference returns the ConfigMap of the configuration of the Prometheus Adapter that holds the
+// rules of the instance, which is referenced in its status
+func getPrometheusAdapterReference(ba common.BaseComponent) (types.NamespacedName, bool) {
+	reference := ba.GetStatus().GetReferences()[common.StatusReferencePrometheusAdapter]
+	if reference == "" {
+		return types.NamespacedName{}, false
+	}
+	namespace, name, _ := strings.Cut(reference, "/")
+	return types.NamespacedName{Namespace: namespace, Name: name}, true
+}
+
+// HasPrometheusAdapterRules returns whether the instance has rules in the configuration of the Prometheus Adapter, or
+// is going to add them, so that it needs PrometheusAdapterFinalizer
+func HasPrometheusAdapterRules(ba common.BaseComponent) bool {
+	_, ok := getPrometheusAdapterReference(ba)
+	return ok || len(getPrometheusAdapterRules(ba)) > 0
+}
+
+// getPrometheusAdapterSeriesQuery returns the series query of the rules of an instance. The metrics are discovered
+// from the targets of the instance scraped by Prometheus, and the query identifies the rules of the instance in the
+// configuration of the Prometheus Adapter
+func getPrometheusAdapterSeriesQuery(namespace, name string) string {
+	return fmt.Sprintf(`up{namespace=%q,pod=~"%s-.+"}`, namespace, quotePromQLRegex(name))
+}
+
+// getPrometheusAdapterRules returns the external rules of the Prometheus Adapter of the metrics of .spec.autoscaling.prometheus
+func getPrometheusAdapterRules(ba common.BaseComponent) []prometheusAdapterRule {
+	if ba.GetAutoscaling() == nil {
+		return nil
+	}
+	obj := ba.(metav1.Object)
+	replacer := newQueryReplacer(ba)
+	seriesQuery := getPrometheusAdapterSeriesQuery(obj.GetNamespace(), obj.GetName())
+
+	var rules []prometheusAdapterRule
+	for _, metric := range ba.GetAutoscaling().GetPrometheus() {
+		rule := prometheusAdapterRule{SeriesQuery: seriesQuery, MetricsQuery: replacer.Replace(metric.GetQuery())}
+		rule.Resources.Overrides = map[string]map[string]string{"namespace": {"resource": "namespace"}}
+		rule.Name.Matches = "^up$"
+		rule.Name.As = GetPrometheusMetricName(ba, metric)
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// mergePrometheusAdapterRules replaces the external rules of the instance with the given namespace and name in the
+// configuration of the Prometheus Adapter, and returns the configuration and whether it changed. The rules of the
+// other instances and the other settings of the configuration are kept
+func mergePrometheusAdapterRules(config, namespace, name string, rules []prometheusAdapterRule) (string, bool, error) {
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(config), &cfg); err != nil {
+		return "", false, err
+	}
+	// The rules are compared in the same form as the rules of the configuration
+	newRules := []interface{}{}
+	if len(rules) > 0 {
+		data, err := yaml.Marshal(rules)
+		if err != nil {
+			return "", false, err
+		}
+		if err := yaml.Unmarshal(data, &newRules); err != nil {
+			return "", false, err
+		}
+	}
+
+	// The rules of the instance are replaced where they are, so that the order of the rules is stable
+	seriesQuery := getPrometheusAdapterSeriesQuery(namespace, name)
+	existing, _ := cfg["externalRules"].([]interface{})
+	externalRules, oldRules, index := []interface{}{}, []interface{}{}, -1
+	for _, rule := range existing {
+		if r, ok := rule.(map[string]interface{}); ok && r["seriesQuery"] == seriesQuery {
+			if index < 0 {
+				index = len(externalRules)
+			}
+			oldRules = append(oldRules, rule)
+			continue
+		}
+		externalRules = append(externalRules, rule)
+	}
+	if reflect.DeepEqual(oldRules, newRules) {
+		return config, false, nil
+	}
+	if index < 0 {
+		index = len(externalRules)
+	}
+	externalRules = append(externalRules[:index], append(newRules, externalRules[index:]...)...)
+	if len(externalRules) == 0 {
+		delete(cfg, "externalRules")
+	} else {
+		cfg["externalRules"] = externalRules
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// updatePrometheusAdapterRules sets the external rules of the instance with the given namespace and name in the
+// ConfigMap of the configuration of the Prometheus Adapter, and removes them when rules is empty
+func (r *ReconcilerBase) updatePrometheusAdapterRules(key types.NamespacedName, namespace, name string, rules []prometheusAdapterRule) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// The ConfigMap is usually in a namespace that is not watched by the operator
+		cm := &corev1.ConfigMap{}
+		if err := r.GetAPIReader().Get(context.TODO(), key, cm); err != nil {
+			return checkPrometheusAdapterAccess(key, err)
+		}
+		config, changed, err := mergePrometheusAdapterRules(cm.Data[prometheusAdapterConfigKey], namespace, name, rules)
+		if err != nil {
+			return fmt.Errorf("failed to parse the configuration of the Prometheus Adapter in ConfigMap %s: %w", key, err)
+		}
+		if !changed {
+			return nil
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[prometheusAdapterConfigKey] = config
+		return checkPrometheusAdapterAccess(key, r.GetClient().Update(context.TODO(), cm))
+	})
+}
+
+// checkPrometheusAdapterAccess explains the error when the operator is not allowed to get or update the ConfigMap of
+// the configuration of the Prometheus Adapter, which is usually in another namespace than the instances
+func checkPrometheusAdapterAccess(key types.NamespacedName, err error) error {
+	if !kerrors.IsForbidden(err) {
+		return err
+	}
+	return fmt.Errorf("the operator is not allowed to get and update the ConfigMap %s of the configuration of the Prometheus Adapter, "+
+		"bind its service account to a Role that allows it in namespace %s: %w", key, key.Namespace, err)
+}
+
+// RemovePrometheusAdapterRules removes the external rules of the instance with the given namespace and name from the
+// ConfigMap of the configuration of the Prometheus Adapter. A missing ConfigMap has no rules of the instance
+func (r *ReconcilerBase) RemovePrometheusAdapterRules(key types.NamespacedName, namespace, name string) error {
+	err := r.updatePrometheusAdapterRules(key, namespace, name, nil)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// FinalizePrometheusAdapterRules removes the external rules of an instance that is being deleted from the ConfigMap
+// referenced in its status
+func (r *ReconcilerBase) FinalizePrometheusAdapterRules(ba common.BaseComponent) error {
+	key, ok := getPrometheusAdapterReference(ba)
+	if !ok {
+		return nil
+	}
+	obj := ba.(metav1.Object)
+	return r.RemovePrometheusAdapterRules(key, obj.GetNamespace(), obj.GetName())
+}
+
+// GetPrometheusMetricName returns the name of the external metric served by the Prometheus Adapter for a metric of
+// the instance, which is unique in its namespace
+func GetPrometheusMetricName(ba common.BaseComponent, metric common.BaseComponentPrometheusMetric) string {
+	return invalidLabelNameChars.ReplaceAllString(ba.(metav1.Object).GetName(), "_") + "_" + metric.GetName()
+}
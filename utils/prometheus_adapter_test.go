@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCustomizePrometheusAdapter(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	valueType := "Value"
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtimeComponent := createRuntimeComponent(name, namespace, spec)
+	runtimeComponent.Spec.Autoscaling = &appstacksv1.RuntimeComponentAutoScaling{MaxReplicas: 3, Prometheus: []appstacksv1.RuntimeComponentPrometheusMetric{
+		{Name: "requests_per_second", Query: `sum(rate(http_server_request_duration_seconds_count{namespace="$(NAMESPACE)",pod=~"$(POD_REGEX)"}[2m]))`,
+			Target: resource.MustParse("100")},
+		{Name: "queue_depth", Query: `max(queue_depth{queue="$(NAME)"})`, Target: resource.MustParse("50"), TargetType: &valueType},
+	}}
+	rules := getPrometheusAdapterRules(runtimeComponent)
+
+	// The rules of the other instances and the other settings are kept, and the rules of the instance are replaced
+	config := `rules:
+- seriesQuery: http_requests_total
+externalRules:
+- seriesQuery: up{namespace="other",pod=~"other-app-.+"}
+- seriesQuery: up{namespace="runtime",pod=~"my-app-.+"}
+- seriesQuery: up{namespace="runtime",pod=~"my-app-2-.+"}
+`
+	merged, changed, mergeErr := mergePrometheusAdapterRules(config, namespace, name, rules)
+	mergedConfig := map[string][]prometheusAdapterRule{}
+	unmarshalErr := yaml.Unmarshal([]byte(merged), &mergedConfig)
+	_, unchanged, _ := mergePrometheusAdapterRules(merged, namespace, name, rules)
+	removed, _, _ := mergePrometheusAdapterRules(merged, namespace, name, nil)
+	_, removedChanged, _ := mergePrometheusAdapterRules("rules: []\n", namespace, name, nil)
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	CustomizeHPA(hpa, runtimeComponent)
+
+	testCPA := []Test{
+		{"rules", 2, len(rules)},
+		{"series query", `up{namespace="runtime",pod=~"my-app-.+"}`, rules[0].SeriesQuery},
+		{"metric name", "my_app_requests_per_second", rules[0].Name.As},
+		{"namespaced metric", "namespace", rules[0].Resources.Overrides["namespace"]["resource"]},
+		{"metrics query", `sum(rate(http_server_request_duration_seconds_count{namespace="runtime",pod=~"my-app-[a-z0-9]+-[a-z0-9]+"}[2m]))`, rules[0].MetricsQuery},
+		{"name replaced", `max(queue_depth{queue="my-app"})`, rules[1].MetricsQuery},
+		{"no merge error", nil, mergeErr},
+		{"valid configuration", nil, unmarshalErr},
+		{"configuration changed", true, changed},
+		{"other settings kept", 1, len(mergedConfig["rules"])},
+		{"external rules", 4, len(mergedConfig["externalRules"])},
+		{"other instance rule kept", `up{namespace="other",pod=~"other-app-.+"}`, mergedConfig["externalRules"][0].SeriesQuery},
+		{"rules replaced in place", "my_app_queue_depth", mergedConfig["externalRules"][2].Name.As},
+		{"instance with the same prefix kept", `up{namespace="runtime",pod=~"my-app-2-.+"}`, mergedConfig["externalRules"][3].SeriesQuery},
+		{"configuration unchanged", false, unchanged},
+		{"rules removed", false, strings.Contains(removed, `pod=~\"my-app-.+\"`)},
+		{"other rules not removed", true, strings.Contains(removed, "my-app-2-.+")},
+		{"no rules to remove", false, removedChanged},
+		{"HPA metrics", 2, len(hpa.Spec.Metrics)},
+		{"HPA metric type", autoscalingv2.ExternalMetricSourceType, hpa.Spec.Metrics[0].Type},
+		{"HPA metric name", "my_app_requests_per_second", hpa.Spec.Metrics[0].External.Metric.Name},
+		{"default target type", autoscalingv2.AverageValueMetricType, hpa.Spec.Metrics[0].External.Target.Type},
+		{"average value", "100", hpa.Spec.Metrics[0].External.Target.AverageValue.String()},
+		{"value", "50", hpa.Spec.Metrics[1].External.Target.Value.String()},
+	}
+	verifyTests(testCPA, t)
+}
+
+func TestReconcilePrometheusAdapterRules(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service}
+	runtimeComponent := createRuntimeComponent(name, namespace, spec)
+	runtimeComponent.Spec.Autoscaling = &appstacksv1.RuntimeComponentAutoScaling{MaxReplicas: 3, Prometheus: []appstacksv1.RuntimeComponentPrometheusMetric{
+		{Name: "queue_depth", Query: `max(queue_depth{queue="$(NAME)"})`, Target: resource.MustParse("50")},
+	}}
+	adapterCM := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "adapter-config", Namespace: "monitoring"},
+		Data: map[string]string{"config.yaml": "externalRules:\n- seriesQuery: up{namespace=\"other\",pod=~\"other-app-.+\"}\n"}}
+	objs, s := []runtime.Object{runtimeComponent, adapterCM}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimeComponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	r.SetDiscoveryClient(createFakeDiscoveryClient())
+	p := r.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator")
+	state := &ReconcileState{Context: context.TODO(), DefaultMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+
+	getRules := func() []prometheusAdapterRule {
+		cm := &corev1.ConfigMap{}
+		cl.Get(context.TODO(), GetPrometheusAdapterConfigMap(), cm)
+		config := map[string][]prometheusAdapterRule{}
+		yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config)
+		return config["externalRules"]
+	}
+
+	hasRules := HasPrometheusAdapterRules(runtimeComponent)
+	addErr := p.reconcileAutoscaling(runtimeComponent, state)
+	added := getRules()
+	reference := runtimeComponent.Status.References[common.StatusReferencePrometheusAdapter]
+
+	// The rules are removed from the referenced ConfigMap when the instance is deleted
+	deleted := runtimeComponent.DeepCopy()
+	deleted.Spec.Autoscaling = nil
+	hasReferencedRules := HasPrometheusAdapterRules(deleted)
+
+	// The operator must be allowed to update the ConfigMap
+	forbiddenCl := interceptor.NewClient(cl, interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			return kerrors.NewForbidden(corev1.Resource("configmaps"), obj.GetName(), errors.New("no RBAC policy matched"))
+		},
+	})
+	forbiddenR := NewReconcilerBase(forbiddenCl, forbiddenCl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	forbiddenR.SetDiscoveryClient(createFakeDiscoveryClient())
+	changed := runtimeComponent.DeepCopy()
+	changed.Spec.Autoscaling.Prometheus[0].Query = "max(queue_depth)"
+	forbiddenErr := forbiddenR.NewReconcilePipeline("rco", "Runtime Component Operator", "runtime-component-operator").reconcileAutoscaling(changed, state)
+	forbiddenRemoveErr := forbiddenR.FinalizePrometheusAdapterRules(deleted)
+
+	finalizeErr := r.FinalizePrometheusAdapterRules(deleted)
+	finalized := getRules()
+	p.reconcileAutoscaling(runtimeComponent, state)
+
+	runtimeComponent.Spec.Autoscaling.Prometheus = nil
+	removeErr := p.reconcileAutoscaling(runtimeComponent, state)
+	remaining := getRules()
+	_, referenceKept := runtimeComponent.Status.References[common.StatusReferencePrometheusAdapter]
+	noRules := HasPrometheusAdapterRules(runtimeComponent)
+
+	// The ConfigMap of the Prometheus Adapter must exist
+	common.Config.Store(common.OpConfigPrometheusAdapterConfigMapNamespace, "missing")
+	runtimeComponent.Spec.Autoscaling.Prometheus = []appstacksv1.RuntimeComponentPrometheusMetric{{Name: "queue_depth", Query: "max(queue_depth)", Target: resource.MustParse("50")}}
+	missingErr := p.reconcileAutoscaling(runtimeComponent, state)
+	deletedErr := r.RemovePrometheusAdapterRules(GetPrometheusAdapterConfigMap(), namespace, name)
+
+	testRPAR := []Test{
+		{"no error", nil, addErr},
+		{"rules added", 2, len(added)},
+		{"other instance rule kept", `up{namespace="other",pod=~"other-app-.+"}`, added[0].SeriesQuery},
+		{"instance rule", "my_app_queue_depth", added[1].Name.As},
+		{"ConfigMap reference", "monitoring/adapter-config", reference},
+		{"instance with rules", true, hasRules},
+		{"instance with referenced rules", true, hasReferencedRules},
+		{"no error on finalization", nil, finalizeErr},
+		{"rules removed on finalization", 1, len(finalized)},
+		{"no error on removal", nil, removeErr},
+		{"rules removed", 1, len(remaining)},
+		{"reference removed", false, referenceKept},
+		{"instance without rules", false, noRules},
+		{"forbidden ConfigMap", true, kerrors.IsForbidden(forbiddenErr) && strings.Contains(forbiddenErr.Error(), "monitoring/adapter-config")},
+		{"forbidden ConfigMap on deletion", true, kerrors.IsForbidden(forbiddenRemoveErr)},
+		{"missing ConfigMap", true, missingErr != nil && strings.Contains(missingErr.Error(), common.OpConfigPrometheusAdapterConfigMapNamespace)},
+		{"missing ConfigMap on deletion", nil, deletedErr},
+	}
+	verifyTests(testRPAR, t)
+	common.Config = common.DefaultOpConfig()
+}
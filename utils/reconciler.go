@@ -6,7 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	networkingv1 "k8s.io/api/networking/v1"
@@ -21,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -222,6 +226,8 @@ func (r *ReconcilerBase) GetOpConfigMap(name string, ns string) (*corev1.ConfigM
 type StatusWarning struct {
 	GetCondition func(ba common.BaseComponent) bool
 	Message      string
+	// GetMessage returns the message of the warning, when it depends on the instance. It takes precedence over Message
+	GetMessage func(ba common.BaseComponent) string
 }
 
 func getDefaultWarnings() []StatusWarning {
@@ -234,6 +240,13 @@ func getDefaultWarnings() []StatusWarning {
 			},
 			Message: "ManageTLS is true but port is set to 9080",
 		},
+		{
+			GetCondition: func(ba common.BaseComponent) bool {
+				e := ba.GetStatus().GetCertificateExpiry()
+				return e != nil && isCertificateExpiring(e.GetNotAfter().Time, time.Now())
+			},
+			GetMessage: getCertificateExpiryMessage,
+		},
 	}
 }
 
@@ -254,7 +267,11 @@ func addStatusWarnings(ba common.BaseComponent, statusWarnings []StatusWarning)
 	if hasWarning && firstWarning != nil {
 		statusCondition := s.NewCondition(common.StatusConditionTypeWarning)
 		statusCondition.SetReason("")
-		statusCondition.SetMessage(firstWarning.Message)
+		if firstWarning.GetMessage != nil {
+			statusCondition.SetMessage(firstWarning.GetMessage(ba))
+		} else {
+			statusCondition.SetMessage(firstWarning.Message)
+		}
 		statusCondition.SetStatus(corev1.ConditionTrue)
 		s.SetCondition(statusCondition)
 	} else {
@@ -477,6 +494,44 @@ func (r *ReconcilerBase) ManageSuccess(conditionType common.StatusConditionType,
 	return reconcile.Result{RequeueAfter: retryInterval}, nil
 }
 
+// ManagePaused sets the Paused condition and reports the application status without modifying any child resources
+func (r *ReconcilerBase) ManagePaused(message string, ba common.BaseComponent) (reconcile.Result, error) {
+	s := ba.GetStatus()
+	obj := ba.(client.Object)
+
+	oldCondition := s.GetCondition(common.StatusConditionTypePaused)
+	if oldCondition == nil || oldCondition.GetStatus() != corev1.ConditionTrue {
+		r.GetRecorder().Event(obj, "Normal", "ReconciliationPaused", message)
+	}
+	newCondition := s.NewCondition(common.StatusConditionTypePaused)
+	newCondition.SetConditionFields(message, "ReconciliationPaused", corev1.ConditionTrue)
+	s.SetCondition(newCondition)
+
+	// Keep reporting the readiness of the resources as they are while paused
+	r.CheckApplicationStatus(ba)
+
+	err := r.UpdateStatus(obj)
+	if err != nil {
+		log.Error(err, "Unable to update status")
+		return reconcile.Result{
+			RequeueAfter: time.Second,
+			Requeue:      true,
+		}, nil
+	}
+
+	// Requeue so that lifting an operator-wide pause is picked up without any change to the instance
+	return reconcile.Result{RequeueAfter: time.Duration(getMaxReconcileInterval(true)) * time.Second}, nil
+}
+
+// UnsetPaused removes the Paused condition once reconciliation resumes
+func (r *ReconcilerBase) UnsetPaused(ba common.BaseComponent) {
+	s := ba.GetStatus()
+	if condition := s.GetCondition(common.StatusConditionTypePaused); condition != nil {
+		s.UnsetCondition(condition)
+		r.GetRecorder().Event(ba.(client.Object), "Normal", "ReconciliationResumed", "Reconciliation is resumed.")
+	}
+}
+
 // IsGroupVersionSupported ...
 func (r *ReconcilerBase) IsGroupVersionSupported(groupVersion string, kind string) (bool, error) {
 	cli, err := r.GetDiscoveryClient()
@@ -534,12 +589,8 @@ func (r *ReconcilerBase) GetRouteTLSValues(ba common.BaseComponent) (key string,
 			destCa = string(caCrt)
 		}
 	}
-	if ba.GetRoute() != nil && ba.GetRoute().GetCertificateSecretRef() != nil {
+	if secretName := GetRouteCertificateSecretName(ba); secretName != "" {
 		tlsSecret := &corev1.Secret{}
-		secretName := mObj.GetName() + "-route-tls"
-		if ba.GetRoute().GetCertificateSecretRef() != nil {
-			secretName = *ba.GetRoute().GetCertificateSecretRef()
-		}
 		err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: mObj.GetNamespace()}, tlsSecret)
 		if err != nil {
 			r.ManageError(err, common.StatusConditionTypeReconciled, ba)
@@ -640,8 +691,7 @@ func (r *ReconcilerBase) GenerateCMIssuer(namespace string, prefix string, CACom
 		Namespace: namespace,
 	}}
 	err := r.CreateOrUpdate(issuer, nil, func() error {
-		issuer.Spec.SelfSigned = &certmanagerv1.SelfSignedIssuer{}
-		issuer.Labels = MergeMaps(issuer.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
+		CustomizeSelfSignedIssuer(issuer, operatorName)
 		return nil
 	})
 	if err != nil {
@@ -658,21 +708,7 @@ func (r *ReconcilerBase) GenerateCMIssuer(namespace string, prefix string, CACom
 
 	caCertSecretName := prefix + "-ca-tls"
 	err = r.CreateOrUpdate(caCert, nil, func() error {
-		caCert.Labels = MergeMaps(caCert.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
-		caCert.Spec.CommonName = CACommonName
-		caCert.Spec.IsCA = true
-		caCert.Spec.SecretName = caCertSecretName
-		caCert.Spec.IssuerRef = certmanagermetav1.ObjectReference{
-			Name: prefix + "-self-signed",
-		}
-
-		duration, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCMCADuration))
-		if err != nil {
-			return err
-		}
-
-		caCert.Spec.Duration = &metav1.Duration{Duration: duration}
-		return nil
+		return CustomizeCACertificate(caCert, prefix, CACommonName, operatorName)
 	})
 
 	if err != nil {
@@ -703,12 +739,7 @@ func (r *ReconcilerBase) GenerateCMIssuer(namespace string, prefix string, CACom
 		Namespace: namespace,
 	}}
 	err = r.CreateOrUpdate(issuer, nil, func() error {
-		issuer.Labels = MergeMaps(issuer.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
-		issuer.Spec.CA = &certmanagerv1.CAIssuer{}
-		issuer.Spec.CA.SecretName = caCertSecretName
-		if issuer.Annotations == nil {
-			issuer.Annotations = map[string]string{}
-		}
+		CustomizeCAIssuer(issuer, caCertSecretName, operatorName)
 		if customCACertFound {
 			issuer.Spec.CA.SecretName = CustomCACert.Name
 
@@ -727,56 +758,111 @@ func (r *ReconcilerBase) GenerateCMIssuer(namespace string, prefix string, CACom
 			return errors.New("Certificate Issuer is not ready")
 		}
 	}
-	return nil
+	return r.reconcileCABundle(namespace, prefix, issuer.Spec.CA.SecretName, operatorName)
 }
 
-func (r *ReconcilerBase) GenerateSvcCertSecret(ba common.BaseComponent, prefix string, CACommonName string, operatorName string) (bool, error) {
-	delete(ba.GetStatus().GetReferences(), common.StatusReferenceCertSecretName)
-	cleanup := func() {
-		if ok, err := r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Certificate"); err != nil {
-			return
-		} else if ok {
-			obj := ba.(metav1.Object)
-			svcCert := &certmanagerv1.Certificate{}
-			svcCert.Name = obj.GetName() + "-svc-tls-cm"
-			svcCert.Namespace = obj.GetNamespace()
-			r.client.Delete(context.Background(), svcCert)
-		}
+const (
+	// CABundleKey is the key of the current and previous CA in the CA bundle ConfigMap
+	CABundleKey = "ca-bundle.crt"
+	// CABundleCurrentKey is the key of the current CA in the CA bundle ConfigMap
+	CABundleCurrentKey = "ca.crt"
+	// CABundlePreviousKey is the key of the CA replaced by the current CA in the CA bundle ConfigMap
+	CABundlePreviousKey = "previous-ca.crt"
+	// CABundleMountPath is the directory where the CA bundle is mounted in the application container
+	CABundleMountPath = "/etc/x509/ca-bundle"
+)
+
+// GetCABundleName returns the name of the ConfigMap with the CA bundle of the operator in each namespace
+func GetCABundleName(prefix string) string {
+	return prefix + "-ca-bundle"
+}
+
+// IsCABundleInjected returns true if the CA bundle of the operator is mounted in the pods of the instance
+func IsCABundleInjected(ba common.BaseComponent) bool {
+	return ba.GetTrust() != nil && ba.GetTrust().GetInjectCABundle() != nil && *ba.GetTrust().GetInjectCABundle()
+}
+
+// reconcileCABundle updates the CA bundle ConfigMap of the namespace with the CA in caSecretName
+func (r *ReconcilerBase) reconcileCABundle(namespace string, prefix string, caSecretName string, operatorName string) error {
+	caSecret := &corev1.Secret{}
+	if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: caSecretName, Namespace: namespace}, caSecret); err != nil {
+		return err
 	}
+	ca := caSecret.Data["tls.crt"]
+	if len(ca) == 0 {
+		return fmt.Errorf("the CA secret %q does not have a tls.crt key", caSecretName)
+	}
+	bundle := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      GetCABundleName(prefix),
+		Namespace: namespace,
+	}}
+	return r.CreateOrUpdate(bundle, nil, func() error {
+		CustomizeCABundle(bundle, string(ca), operatorName)
+		return nil
+	})
+}
 
+// CustomizeCABundle sets the current CA of the CA bundle. The CA that it replaces is kept in the bundle until the next
+// rotation, so that the certificates issued by it are trusted until they are renewed
+func CustomizeCABundle(bundle *corev1.ConfigMap, ca string, operatorName string) {
+	bundle.Labels = MergeMaps(bundle.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
+	if bundle.Data == nil {
+		bundle.Data = map[string]string{}
+	}
+	ca = strings.TrimSpace(ca) + "\n"
+	if current := bundle.Data[CABundleCurrentKey]; current != "" && current != ca {
+		bundle.Data[CABundlePreviousKey] = current
+	}
+	bundle.Data[CABundleCurrentKey] = ca
+	bundle.Data[CABundleKey] = ca + bundle.Data[CABundlePreviousKey]
+}
+
+// shouldGenerateSvcCertificate returns true if a cert-manager service certificate is generated for the instance
+func shouldGenerateSvcCertificate(ba common.BaseComponent) bool {
 	if ba.GetCreateKnativeService() != nil && *ba.GetCreateKnativeService() {
-		cleanup()
-		return false, nil
+		return false
 	}
 	if ba.GetService() != nil && ba.GetService().GetCertificateSecretRef() != nil {
-		cleanup()
-		return false, nil
+		return false
 	}
 	if ba.GetManageTLS() != nil && !*ba.GetManageTLS() {
-		cleanup()
-		return false, nil
+		return false
 	}
 	if ba.GetService() != nil && ba.GetService().GetAnnotations() != nil {
 		if _, ok := ba.GetService().GetAnnotations()["service.beta.openshift.io/serving-cert-secret-name"]; ok {
-			cleanup()
-			return false, nil
+			return false
 		}
 		if _, ok := ba.GetService().GetAnnotations()["service.alpha.openshift.io/serving-cert-secret-name"]; ok {
-			cleanup()
-			return false, nil
+			return false
 		}
 	}
+	return true
+}
+
+func (r *ReconcilerBase) GenerateSvcCertSecret(ba common.BaseComponent, prefix string, CACommonName string, operatorName string) (bool, error) {
+	delete(ba.GetStatus().GetReferences(), common.StatusReferenceCertSecretName)
+	delete(ba.GetStatus().GetReferences(), common.StatusReferenceClientCertSecretName)
+	if !shouldGenerateSvcCertificate(ba) {
+		return false, r.deleteSvcCertificates(ba)
+	}
 	if ok, err := r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Certificate"); err != nil {
 		return false, err
 	} else if ok {
 		bao := ba.(metav1.Object)
 
-		err = r.GenerateCMIssuer(bao.GetNamespace(), prefix, CACommonName, operatorName)
-		if err != nil {
-			if errors.Is(err, APIVersionNotFoundError) {
-				return false, nil
+		// A certificate issued by the issuer of .spec.service.certificate does not need the operator CA
+		var issuerRef common.BaseComponentIssuerReference
+		if ba.GetService() != nil && ba.GetService().GetCertificate() != nil {
+			issuerRef = ba.GetService().GetCertificate().GetIssuerRef()
+		}
+		if issuerRef == nil {
+			err = r.GenerateCMIssuer(bao.GetNamespace(), prefix, CACommonName, operatorName)
+			if err != nil {
+				if errors.Is(err, APIVersionNotFoundError) {
+					return false, nil
+				}
+				return true, err
 			}
-			return true, err
 		}
 		svcCertSecretName := bao.GetName() + "-svc-tls-cm"
 
@@ -797,41 +883,19 @@ func (r *ReconcilerBase) GenerateSvcCertSecret(ba common.BaseComponent, prefix s
 			customIssuerFound = true
 		}
 
+		keystorePasswordVersion, err := r.getKeystorePasswordVersion(ba)
+		if err != nil {
+			return true, err
+		}
+
 		shouldRefreshCertSecret := false
 		err = r.CreateOrUpdate(svcCert, bao, func() error {
-			svcCert.Labels = ba.GetLabels()
-			svcCert.Annotations = MergeMaps(svcCert.Annotations, ba.GetAnnotations())
-			if ba.GetService() != nil {
-				if ba.GetService().GetCertificate() != nil {
-					if ba.GetService().GetCertificate().GetAnnotations() != nil {
-						svcCert.Annotations = MergeMaps(svcCert.Annotations, ba.GetService().GetCertificate().GetAnnotations())
-					}
-				}
-			}
-
-			svcCert.Spec.CommonName = trimCommonName(bao.GetName(), bao.GetNamespace())
-			svcCert.Spec.DNSNames = make([]string, 4)
-			svcCert.Spec.DNSNames[0] = bao.GetName() + "." + bao.GetNamespace() + ".svc"
-			svcCert.Spec.DNSNames[1] = bao.GetName() + "." + bao.GetNamespace() + ".svc.cluster.local"
-			svcCert.Spec.DNSNames[2] = bao.GetName() + "." + bao.GetNamespace()
-			svcCert.Spec.DNSNames[3] = bao.GetName()
-			if ba.GetStatefulSet() != nil {
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, bao.GetName()+"-headless."+bao.GetNamespace()+".svc")
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, bao.GetName()+"-headless."+bao.GetNamespace()+".svc.cluster.local")
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, bao.GetName()+"-headless."+bao.GetNamespace())
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, bao.GetName()+"-headless")
-				// Wildcard entries for the pods
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, "*."+bao.GetName()+"-headless."+bao.GetNamespace()+".svc")
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, "*."+bao.GetName()+"-headless."+bao.GetNamespace()+".svc.cluster.local")
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, "*."+bao.GetName()+"-headless."+bao.GetNamespace())
-				svcCert.Spec.DNSNames = append(svcCert.Spec.DNSNames, "*."+bao.GetName()+"-headless")
-			}
-			svcCert.Spec.IsCA = false
-			svcCert.Spec.IssuerRef = certmanagermetav1.ObjectReference{
-				Name: prefix + "-ca-issuer",
-			}
+			issuerName := prefix + "-ca-issuer"
 			if customIssuerFound {
-				svcCert.Spec.IssuerRef.Name = customIssuer.Name
+				issuerName = customIssuer.Name
+			}
+			if err := CustomizeSvcCertificate(svcCert, ba, issuerName); err != nil {
+				return err
 			}
 
 			rVersion, _ := GetIssuerResourceVersion(r.client, svcCert)
@@ -848,14 +912,13 @@ func (r *ReconcilerBase) GenerateSvcCertSecret(ba common.BaseComponent, prefix s
 				svcCert.Spec.SecretTemplate.Annotations[ba.GetGroupName()+"/cm-issuer-version"] = rVersion
 				shouldRefreshCertSecret = true
 			}
-
-			svcCert.Spec.SecretName = svcCertSecretName
-
-			duration, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCMCertDuration))
-			if err != nil {
-				return err
+			if svcCert.Spec.SecretTemplate.Annotations[ba.GetGroupName()+"/keystore-password-version"] != keystorePasswordVersion {
+				if svcCert.Spec.SecretTemplate.Annotations == nil {
+					svcCert.Spec.SecretTemplate.Annotations = map[string]string{}
+				}
+				svcCert.Spec.SecretTemplate.Annotations[ba.GetGroupName()+"/keystore-password-version"] = keystorePasswordVersion
+				shouldRefreshCertSecret = true
 			}
-			svcCert.Spec.Duration = &metav1.Duration{Duration: duration}
 
 			return nil
 		})
@@ -863,15 +926,404 @@ func (r *ReconcilerBase) GenerateSvcCertSecret(ba common.BaseComponent, prefix s
 			return true, err
 		}
 		if shouldRefreshCertSecret {
-			r.DeleteResource(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: svcCertSecretName, Namespace: svcCert.Namespace}})
+			if err := r.DeleteResource(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: svcCertSecretName, Namespace: svcCert.Namespace}}); err != nil {
+				return true, err
+			}
 		}
 		ba.GetStatus().SetReference(common.StatusReferenceCertSecretName, svcCertSecretName)
+
+		clientCert := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{
+			Name:      getClientCertName(ba),
+			Namespace: bao.GetNamespace(),
+		}}
+		if !IsClientCertificateEnabled(ba) {
+			return true, r.DeleteResource(clientCert)
+		}
+		err = r.CreateOrUpdate(clientCert, bao, func() error {
+			CustomizeClientCertificate(clientCert, svcCert, ba)
+			return nil
+		})
+		if err != nil {
+			return true, err
+		}
+		if shouldRefreshCertSecret {
+			if err := r.DeleteResource(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: clientCert.Spec.SecretName, Namespace: clientCert.Namespace}}); err != nil {
+				return true, err
+			}
+		}
+		ba.GetStatus().SetReference(common.StatusReferenceClientCertSecretName, clientCert.Spec.SecretName)
 	} else {
 		return false, nil
 	}
 	return true, nil
 }
 
+// deleteSvcCertificates deletes the cert-manager service and client certificates of the instance
+func (r *ReconcilerBase) deleteSvcCertificates(ba common.BaseComponent) error {
+	if ok, err := r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Certificate"); err != nil || !ok {
+		return err
+	}
+	obj := ba.(metav1.Object)
+	svcCert := &certmanagerv1.Certificate{}
+	svcCert.Name = obj.GetName() + "-svc-tls-cm"
+	svcCert.Namespace = obj.GetNamespace()
+	return r.DeleteResources([]client.Object{
+		svcCert,
+		&certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: getClientCertName(ba), Namespace: obj.GetNamespace()}},
+	})
+}
+
+// CustomizeSelfSignedIssuer configures the self-signed issuer used to sign the operator CA
+func CustomizeSelfSignedIssuer(issuer *certmanagerv1.Issuer, operatorName string) {
+	issuer.Spec.SelfSigned = &certmanagerv1.SelfSignedIssuer{}
+	issuer.Labels = MergeMaps(issuer.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
+}
+
+// CustomizeCACertificate configures the operator CA certificate issued by the self-signed issuer
+func CustomizeCACertificate(caCert *certmanagerv1.Certificate, prefix string, CACommonName string, operatorName string) error {
+	caCert.Labels = MergeMaps(caCert.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
+	caCert.Spec.CommonName = CACommonName
+	caCert.Spec.IsCA = true
+	caCert.Spec.SecretName = prefix + "-ca-tls"
+	caCert.Spec.IssuerRef = certmanagermetav1.ObjectReference{
+		Name: prefix + "-self-signed",
+	}
+
+	duration, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCMCADuration))
+	if err != nil {
+		return err
+	}
+
+	caCert.Spec.Duration = &metav1.Duration{Duration: duration}
+	return nil
+}
+
+// CustomizeCAIssuer configures the issuer that signs service certificates with the CA in caSecretName
+func CustomizeCAIssuer(issuer *certmanagerv1.Issuer, caSecretName string, operatorName string) {
+	issuer.Labels = MergeMaps(issuer.Labels, map[string]string{"app.kubernetes.io/managed-by": operatorName})
+	issuer.Spec.CA = &certmanagerv1.CAIssuer{}
+	issuer.Spec.CA.SecretName = caSecretName
+	if issuer.Annotations == nil {
+		issuer.Annotations = map[string]string{}
+	}
+}
+
+// CustomizeSvcCertificate configures the service certificate of the component issued by issuerName
+func CustomizeSvcCertificate(svcCert *certmanagerv1.Certificate, ba common.BaseComponent, issuerName string) error {
+	bao := ba.(metav1.Object)
+	svcCert.Labels = ba.GetLabels()
+	svcCert.Annotations = MergeMaps(svcCert.Annotations, ba.GetAnnotations())
+	if ba.GetService() != nil {
+		if ba.GetService().GetCertificate() != nil {
+			if ba.GetService().GetCertificate().GetAnnotations() != nil {
+				svcCert.Annotations = MergeMaps(svcCert.Annotations, ba.GetService().GetCertificate().GetAnnotations())
+			}
+		}
+	}
+
+	svcCert.Spec.CommonName = trimCommonName(bao.GetName(), bao.GetNamespace())
+	svcCert.Spec.DNSNames = getSvcCertificateDNSNames(ba)
+	svcCert.Spec.IsCA = false
+	svcCert.Spec.IssuerRef = certmanagermetav1.ObjectReference{
+		Name: issuerName,
+	}
+
+	svcCert.Spec.SecretName = bao.GetName() + "-svc-tls-cm"
+
+	duration, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCMCertDuration))
+	if err != nil {
+		return err
+	}
+	svcCert.Spec.Duration = &metav1.Duration{Duration: duration}
+	svcCert.Spec.RenewBefore = nil
+	svcCert.Spec.PrivateKey = nil
+	svcCert.Spec.IPAddresses = nil
+	svcCert.Spec.Usages = nil
+	svcCert.Spec.Keystores = nil
+
+	if ba.GetService() == nil || ba.GetService().GetCertificate() == nil {
+		return nil
+	}
+	sc := ba.GetService().GetCertificate()
+	if sc.GetIssuerRef() != nil {
+		svcCert.Spec.IssuerRef = certmanagermetav1.ObjectReference{
+			Name:  sc.GetIssuerRef().GetName(),
+			Kind:  sc.GetIssuerRef().GetKind(),
+			Group: certmanagerv1.SchemeGroupVersion.Group,
+		}
+	}
+	if sc.GetDuration() != nil {
+		svcCert.Spec.Duration = sc.GetDuration()
+	}
+	svcCert.Spec.RenewBefore = sc.GetRenewBefore()
+	svcCert.Spec.IPAddresses = sc.GetIPAddresses()
+	for _, usage := range sc.GetUsages() {
+		svcCert.Spec.Usages = append(svcCert.Spec.Usages, certmanagerv1.KeyUsage(usage))
+	}
+	if pk := sc.GetPrivateKey(); pk != nil {
+		svcCert.Spec.PrivateKey = &certmanagerv1.CertificatePrivateKey{}
+		if pk.GetAlgorithm() != nil {
+			svcCert.Spec.PrivateKey.Algorithm = certmanagerv1.PrivateKeyAlgorithm(*pk.GetAlgorithm())
+		}
+		if pk.GetSize() != nil {
+			svcCert.Spec.PrivateKey.Size = *pk.GetSize()
+		}
+		if pk.GetRotationPolicy() != nil {
+			svcCert.Spec.PrivateKey.RotationPolicy = certmanagerv1.PrivateKeyRotationPolicy(*pk.GetRotationPolicy())
+		}
+	}
+	if ks := sc.GetKeystores(); ks != nil {
+		password := certmanagermetav1.SecretKeySelector{
+			LocalObjectReference: certmanagermetav1.LocalObjectReference{Name: ks.GetPasswordSecretRef()},
+			Key:                  ks.GetPasswordSecretKey(),
+		}
+		svcCert.Spec.Keystores = &certmanagerv1.CertificateKeystores{}
+		if ks.GetFormat() == common.KeystoreFormatJKS {
+			svcCert.Spec.Keystores.JKS = &certmanagerv1.JKSKeystore{Create: true, PasswordSecretRef: password, Alias: ks.GetAlias()}
+		} else {
+			svcCert.Spec.Keystores.PKCS12 = &certmanagerv1.PKCS12Keystore{Create: true, PasswordSecretRef: password}
+		}
+	}
+	return nil
+}
+
+// IsClientCertificateEnabled returns true if a client certificate is requested for the instance
+func IsClientCertificateEnabled(ba common.BaseComponent) bool {
+	if ba.GetService() == nil || ba.GetService().GetCertificate() == nil || ba.GetService().GetCertificate().GetClientAuth() == nil {
+		return false
+	}
+	enabled := ba.GetService().GetCertificate().GetClientAuth().GetCertificate()
+	return enabled == nil || *enabled
+}
+
+// IsClientAuthRequired returns true if the clients of the instance must present a client certificate
+func IsClientAuthRequired(ba common.BaseComponent) bool {
+	if ba.GetService() == nil || ba.GetService().GetCertificate() == nil || ba.GetService().GetCertificate().GetClientAuth() == nil {
+		return false
+	}
+	required := ba.GetService().GetCertificate().GetClientAuth().GetRequired()
+	return required != nil && *required
+}
+
+func getClientCertName(ba common.BaseComponent) string {
+	return ba.(metav1.Object).GetName() + "-client-tls-cm"
+}
+
+// CustomizeClientCertificate configures the client certificate of the component. It is issued by the issuer of the
+// service certificate, with the same duration and private key settings, so that servers trust it with the same CA
+func CustomizeClientCertificate(clientCert *certmanagerv1.Certificate, svcCert *certmanagerv1.Certificate, ba common.BaseComponent) {
+	bao := ba.(metav1.Object)
+	clientCert.Labels = ba.GetLabels()
+	clientCert.Annotations = MergeMaps(clientCert.Annotations, svcCert.Annotations)
+	clientCert.Spec.CommonName = trimCommonName(bao.GetName(), bao.GetNamespace())
+	clientCert.Spec.IsCA = false
+	clientCert.Spec.IssuerRef = svcCert.Spec.IssuerRef
+	clientCert.Spec.Duration = svcCert.Spec.Duration
+	clientCert.Spec.RenewBefore = svcCert.Spec.RenewBefore
+	clientCert.Spec.PrivateKey = svcCert.Spec.PrivateKey
+	clientCert.Spec.Usages = []certmanagerv1.KeyUsage{certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageKeyEncipherment, certmanagerv1.UsageClientAuth}
+	clientCert.Spec.SecretName = getClientCertName(ba)
+}
+
+// getSvcCertificateDNSNames returns the DNS names of the service certificate: the names of the Service, the names of the
+// headless Service and its pods for a StatefulSet, and the names in .spec.service.certificate.dnsNames
+func getSvcCertificateDNSNames(ba common.BaseComponent) []string {
+	bao := ba.(metav1.Object)
+	dnsNames := []string{
+		bao.GetName() + "." + bao.GetNamespace() + ".svc",
+		bao.GetName() + "." + bao.GetNamespace() + ".svc.cluster.local",
+		bao.GetName() + "." + bao.GetNamespace(),
+		bao.GetName(),
+	}
+	if ba.GetStatefulSet() != nil {
+		dnsNames = append(dnsNames, bao.GetName()+"-headless."+bao.GetNamespace()+".svc")
+		dnsNames = append(dnsNames, bao.GetName()+"-headless."+bao.GetNamespace()+".svc.cluster.local")
+		dnsNames = append(dnsNames, bao.GetName()+"-headless."+bao.GetNamespace())
+		dnsNames = append(dnsNames, bao.GetName()+"-headless")
+		// Wildcard entries for the pods
+		dnsNames = append(dnsNames, "*."+bao.GetName()+"-headless."+bao.GetNamespace()+".svc")
+		dnsNames = append(dnsNames, "*."+bao.GetName()+"-headless."+bao.GetNamespace()+".svc.cluster.local")
+		dnsNames = append(dnsNames, "*."+bao.GetName()+"-headless."+bao.GetNamespace())
+		dnsNames = append(dnsNames, "*."+bao.GetName()+"-headless")
+	}
+	if ba.GetService() != nil && ba.GetService().GetCertificate() != nil {
+		for _, dnsName := range ba.GetService().GetCertificate().GetDNSNames() {
+			if !slices.Contains(dnsNames, dnsName) {
+				dnsNames = append(dnsNames, dnsName)
+			}
+		}
+	}
+	return dnsNames
+}
+
+// validSvcCertificateUsages are the key usages accepted by cert-manager
+var validSvcCertificateUsages = []certmanagerv1.KeyUsage{
+	certmanagerv1.UsageSigning, certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageContentCommitment,
+	certmanagerv1.UsageKeyEncipherment, certmanagerv1.UsageKeyAgreement, certmanagerv1.UsageDataEncipherment,
+	certmanagerv1.UsageCertSign, certmanagerv1.UsageCRLSign, certmanagerv1.UsageEncipherOnly, certmanagerv1.UsageDecipherOnly,
+	certmanagerv1.UsageAny, certmanagerv1.UsageServerAuth, certmanagerv1.UsageClientAuth, certmanagerv1.UsageCodeSigning,
+	certmanagerv1.UsageEmailProtection, certmanagerv1.UsageSMIME, certmanagerv1.UsageIPsecEndSystem, certmanagerv1.UsageIPsecTunnel,
+	certmanagerv1.UsageIPsecUser, certmanagerv1.UsageTimestamping, certmanagerv1.UsageOCSPSigning, certmanagerv1.UsageMicrosoftSGC,
+	certmanagerv1.UsageNetscapeSGC,
+}
+
+// ValidateSvcCertificate returns an error if .spec.service.certificate would be rejected by cert-manager
+func ValidateSvcCertificate(sc common.BaseComponentCertificate) error {
+	duration, err := time.ParseDuration(common.LoadFromConfig(common.Config, common.OpConfigCMCertDuration))
+	if err != nil {
+		return err
+	}
+	if sc.GetDuration() != nil {
+		duration = sc.GetDuration().Duration
+		if duration < time.Hour {
+			return fmt.Errorf(".spec.service.certificate.duration %s must be at least 1h", duration)
+		}
+	}
+	if rb := sc.GetRenewBefore(); rb != nil && (rb.Duration <= 0 || rb.Duration >= duration) {
+		return fmt.Errorf(".spec.service.certificate.renewBefore %s must be positive and less than the duration %s", rb.Duration, duration)
+	}
+	for _, dnsName := range sc.GetDNSNames() {
+		if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(dnsName, "*.")); len(errs) > 0 {
+			return fmt.Errorf(".spec.service.certificate.dnsNames contains an invalid DNS name %q: %s", dnsName, strings.Join(errs, ", "))
+		}
+	}
+	for _, ip := range sc.GetIPAddresses() {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf(".spec.service.certificate.ipAddresses contains an invalid IP address %q", ip)
+		}
+	}
+	for _, usage := range sc.GetUsages() {
+		if !slices.Contains(validSvcCertificateUsages, certmanagerv1.KeyUsage(usage)) {
+			return fmt.Errorf(".spec.service.certificate.usages contains an unknown key usage %q", usage)
+		}
+	}
+	if pk := sc.GetPrivateKey(); pk != nil && pk.GetSize() != nil {
+		algorithm := string(certmanagerv1.RSAKeyAlgorithm)
+		if pk.GetAlgorithm() != nil {
+			algorithm = *pk.GetAlgorithm()
+		}
+		validSizes := map[string][]int{
+			string(certmanagerv1.RSAKeyAlgorithm):   {2048, 4096, 8192},
+			string(certmanagerv1.ECDSAKeyAlgorithm): {256, 384, 521},
+		}
+		if sizes, ok := validSizes[algorithm]; ok && !slices.Contains(sizes, *pk.GetSize()) {
+			return fmt.Errorf(".spec.service.certificate.privateKey.size %d is not valid for the %s algorithm. Use one of %v", *pk.GetSize(), algorithm, sizes)
+		}
+	}
+	if ks := sc.GetKeystores(); ks != nil && ks.GetAlias() != nil && ks.GetFormat() != common.KeystoreFormatJKS {
+		return fmt.Errorf(".spec.service.certificate.keystores.alias is only supported for the %s format", common.KeystoreFormatJKS)
+	}
+	return nil
+}
+
+// getKeystorePasswordVersion returns the hash of the password of the keystores of the service certificate, or "" if
+// no keystores are generated. The certificate secret is reissued when it changes, so the keystores use the new password
+func (r *ReconcilerBase) getKeystorePasswordVersion(ba common.BaseComponent) (string, error) {
+	if ba.GetService() == nil || ba.GetService().GetCertificate() == nil || ba.GetService().GetCertificate().GetKeystores() == nil {
+		return "", nil
+	}
+	ks := ba.GetService().GetCertificate().GetKeystores()
+	secret := &corev1.Secret{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ks.GetPasswordSecretRef(), Namespace: ba.(metav1.Object).GetNamespace()}, secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the keystore password secret %q: %w", ks.GetPasswordSecretRef(), err)
+	}
+	password, ok := secret.Data[ks.GetPasswordSecretKey()]
+	if !ok {
+		return "", fmt.Errorf("the keystore password secret %q does not have the key %q", ks.GetPasswordSecretRef(), ks.GetPasswordSecretKey())
+	}
+	return HashData(map[string][]byte{ks.GetPasswordSecretKey(): password}), nil
+}
+
+// GetRouteCertificateSecretName returns the name of the secret with the TLS certificate of the Route or Ingress. It is
+// .spec.route.certificateSecretRef, or else the secret of the certificate requested by .spec.route.certificate, or ""
+func GetRouteCertificateSecretName(ba common.BaseComponent) string {
+	rt := ba.GetRoute()
+	if rt == nil {
+		return ""
+	}
+	if rt.GetCertificateSecretRef() != nil {
+		return *rt.GetCertificateSecretRef()
+	}
+	if rt.GetCertificate() != nil {
+		return ba.(metav1.Object).GetName() + "-route-tls-cm"
+	}
+	return ""
+}
+
+// ReconcileRouteCertificate requests the cert-manager certificate of .spec.route.certificate for the host of the
+// Route or Ingress, or deletes it and its secret when it is no longer needed. An error is returned until the
+// certificate is ready, so that the Route or Ingress is not created without it
+func (r *ReconcilerBase) ReconcileRouteCertificate(ba common.BaseComponent, host string) error {
+	obj := ba.(metav1.Object)
+	routeCert := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{
+		Name:      obj.GetName() + "-route-tls-cm",
+		Namespace: obj.GetNamespace(),
+	}}
+	rt := ba.GetRoute()
+	expose := ba.GetExpose() != nil && *ba.GetExpose()
+	certManagerSupported, err := r.IsGroupVersionSupported(certmanagerv1.SchemeGroupVersion.String(), "Certificate")
+	if err != nil {
+		return err
+	}
+
+	if !expose || rt == nil || rt.GetCertificate() == nil || rt.GetCertificateSecretRef() != nil {
+		if !certManagerSupported {
+			return nil
+		}
+		err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: routeCert.Name, Namespace: routeCert.Namespace}, routeCert)
+		if err != nil || !metav1.IsControlledBy(routeCert, obj) {
+			return client.IgnoreNotFound(err)
+		}
+		if err := r.DeleteResource(routeCert); err != nil {
+			return err
+		}
+		return r.DeleteResource(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: routeCert.Spec.SecretName, Namespace: routeCert.Namespace}})
+	}
+
+	if !certManagerSupported {
+		return errors.New("cert-manager must be installed to request the certificate of .spec.route.certificate")
+	}
+	if host == "" {
+		return errors.New(".spec.route.certificate requires a host. Set .spec.route.host, or a domain for default hostnames in the operator ConfigMap")
+	}
+	err = r.CreateOrUpdate(routeCert, obj, func() error {
+		CustomizeRouteCertificate(routeCert, ba, host)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return r.checkCertificateReady(routeCert)
+}
+
+// CustomizeRouteCertificate configures the certificate of .spec.route.certificate for the host of the Route or Ingress
+func CustomizeRouteCertificate(routeCert *certmanagerv1.Certificate, ba common.BaseComponent, host string) {
+	rc := ba.GetRoute().GetCertificate()
+	routeCert.Labels = ba.GetLabels()
+	routeCert.Annotations = MergeMaps(routeCert.Annotations, ba.GetAnnotations())
+
+	routeCert.Spec.DNSNames = []string{host}
+	for _, dnsName := range rc.GetDNSNames() {
+		if dnsName != host {
+			routeCert.Spec.DNSNames = append(routeCert.Spec.DNSNames, dnsName)
+		}
+	}
+	// The common name is limited to 64 characters and is optional when DNS names are set
+	routeCert.Spec.CommonName = ""
+	if len(host) <= 64 {
+		routeCert.Spec.CommonName = host
+	}
+	routeCert.Spec.IsCA = false
+	routeCert.Spec.IssuerRef = certmanagermetav1.ObjectReference{
+		Name:  rc.GetIssuerRef().GetName(),
+		Kind:  rc.GetIssuerRef().GetKind(),
+		Group: certmanagerv1.SchemeGroupVersion.Group,
+	}
+	routeCert.Spec.SecretName = GetRouteCertificateSecretName(ba)
+	routeCert.Spec.Duration = rc.GetDuration()
+}
+
 func (r *ReconcilerBase) GetIngressInfo(ba common.BaseComponent) (host string, path string, protocol string) {
 	mObj := ba.(metav1.Object)
 	protocol = "http"
@@ -6,14 +6,18 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
 
 	"github.com/application-stacks/runtime-component-operator/common"
 
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certmanagermetav1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,6 +30,7 @@ import (
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -508,6 +513,304 @@ func TestTrimCommonName(t *testing.T) {
 
 }
 
+func TestReconcileRouteCertificate(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	issuerKind := "ClusterIssuer"
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, Expose: &expose,
+		Route: &appstacksv1.RuntimeComponentRoute{Certificate: &appstacksv1.RuntimeComponentRouteCertificate{
+			IssuerRef: appstacksv1.RuntimeComponentIssuerReference{Name: "letsencrypt", Kind: &issuerKind},
+			DNSNames:  []string{"my-app.example.com", "www.example.com"},
+		}}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	certmanagerv1.AddToScheme(s)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	dc := createFakeDiscoveryClient().(*fakediscovery.FakeDiscovery)
+	r.SetDiscoveryClient(dc)
+	noCertManagerErr := r.ReconcileRouteCertificate(runtimecomponent, "my-app.example.com")
+	dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+		GroupVersion: certmanagerv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "certificates", Namespaced: true, Kind: "Certificate"}},
+	})
+
+	noHostErr := r.ReconcileRouteCertificate(runtimecomponent, "")
+	notReadyErr := r.ReconcileRouteCertificate(runtimecomponent, "my-app.example.com")
+	routeCert := &certmanagerv1.Certificate{}
+	cl.Get(context.TODO(), types.NamespacedName{Name: name + "-route-tls-cm", Namespace: namespace}, routeCert)
+
+	routeCert.Status.Conditions = []certmanagerv1.CertificateCondition{{Type: certmanagerv1.CertificateConditionReady, Status: certmanagermetav1.ConditionTrue}}
+	cl.Update(context.TODO(), routeCert)
+	readyErr := r.ReconcileRouteCertificate(runtimecomponent, "my-app.example.com")
+	secretName := GetRouteCertificateSecretName(runtimecomponent)
+
+	secretRef := "my-app-tls"
+	runtimecomponent.Spec.Route.CertificateSecretRef = &secretRef
+	secretRefName := GetRouteCertificateSecretName(runtimecomponent)
+	deleteErr := r.ReconcileRouteCertificate(runtimecomponent, "my-app.example.com")
+	deleted := cl.Get(context.TODO(), types.NamespacedName{Name: name + "-route-tls-cm", Namespace: namespace}, &certmanagerv1.Certificate{})
+
+	testRRC := []Test{
+		{"cert-manager not installed", true, noCertManagerErr != nil && strings.Contains(noCertManagerErr.Error(), "cert-manager")},
+		{"no host", true, noHostErr != nil && strings.Contains(noHostErr.Error(), ".spec.route.host")},
+		{"certificate not ready", true, notReadyErr != nil && strings.Contains(notReadyErr.Error(), "not ready")},
+		{"DNS names", []string{"my-app.example.com", "www.example.com"}, routeCert.Spec.DNSNames},
+		{"common name", "my-app.example.com", routeCert.Spec.CommonName},
+		{"issuer kind", "ClusterIssuer", routeCert.Spec.IssuerRef.Kind},
+		{"issuer name", "letsencrypt", routeCert.Spec.IssuerRef.Name},
+		{"certificate secret", name + "-route-tls-cm", routeCert.Spec.SecretName},
+		{"certificate ready", nil, readyErr},
+		{"route secret name", name + "-route-tls-cm", secretName},
+		{"certificateSecretRef precedence", "my-app-tls", secretRefName},
+		{"delete error", nil, deleteErr},
+		{"certificate deleted", true, kerrors.IsNotFound(deleted)},
+	}
+	verifyTests(testRRC, t)
+}
+
+func TestCustomizeSvcCertificate(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	issuerKind, algorithm, size, rotationPolicy := "ClusterIssuer", "ECDSA", 384, "Never"
+	certificate := &appstacksv1.RuntimeComponentCertificate{
+		IssuerRef:   &appstacksv1.RuntimeComponentIssuerReference{Name: "internal-ca", Kind: &issuerKind},
+		Duration:    &metav1.Duration{Duration: 720 * time.Hour},
+		RenewBefore: &metav1.Duration{Duration: 240 * time.Hour},
+		PrivateKey:  &appstacksv1.RuntimeComponentCertificatePrivateKey{Algorithm: &algorithm, Size: &size, RotationPolicy: &rotationPolicy},
+		DNSNames:    []string{"my-app.internal.example.com", name},
+		IPAddresses: []string{"10.0.0.10"},
+		Usages:      []string{"server auth", "client auth"},
+	}
+	spec := appstacksv1.RuntimeComponentSpec{Service: &appstacksv1.RuntimeComponentService{Port: 9443, Certificate: certificate}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+
+	defaultCert := &certmanagerv1.Certificate{}
+	runtimecomponent.Spec.Service.Certificate = nil
+	defaultErr := CustomizeSvcCertificate(defaultCert, runtimecomponent, "rco-ca-issuer")
+
+	svcCert := &certmanagerv1.Certificate{}
+	runtimecomponent.Spec.Service.Certificate = certificate
+	err := CustomizeSvcCertificate(svcCert, runtimecomponent, "rco-ca-issuer")
+
+	certificate.RenewBefore = &metav1.Duration{Duration: 1000 * time.Hour}
+	renewBeforeErr := ValidateSvcCertificate(certificate)
+	certificate.RenewBefore = nil
+	size = 2048
+	sizeErr := ValidateSvcCertificate(certificate)
+	size = 384
+	certificate.IPAddresses = []string{"10.0.0"}
+	ipErr := ValidateSvcCertificate(certificate)
+	certificate.IPAddresses = nil
+	certificate.Usages = []string{"server"}
+	usageErr := ValidateSvcCertificate(certificate)
+
+	testCSC := []Test{
+		{"default error", nil, defaultErr},
+		{"default issuer", "rco-ca-issuer", defaultCert.Spec.IssuerRef.Name},
+		{"default duration", 2160 * time.Hour, defaultCert.Spec.Duration.Duration},
+		{"default private key", (*certmanagerv1.CertificatePrivateKey)(nil), defaultCert.Spec.PrivateKey},
+		{"no error", nil, err},
+		{"issuer", certmanagermetav1.ObjectReference{Name: "internal-ca", Kind: "ClusterIssuer", Group: "cert-manager.io"}, svcCert.Spec.IssuerRef},
+		{"duration", 720 * time.Hour, svcCert.Spec.Duration.Duration},
+		{"renew before", 240 * time.Hour, svcCert.Spec.RenewBefore.Duration},
+		{"private key", certmanagerv1.CertificatePrivateKey{Algorithm: "ECDSA", Size: 384, RotationPolicy: "Never"}, *svcCert.Spec.PrivateKey},
+		{"extra DNS name", "my-app.internal.example.com", svcCert.Spec.DNSNames[len(svcCert.Spec.DNSNames)-1]},
+		{"DNS names not duplicated", 5, len(svcCert.Spec.DNSNames)},
+		{"IP addresses", []string{"10.0.0.10"}, svcCert.Spec.IPAddresses},
+		{"usages", []certmanagerv1.KeyUsage{certmanagerv1.UsageServerAuth, certmanagerv1.UsageClientAuth}, svcCert.Spec.Usages},
+		{"renewBefore exceeds duration", true, renewBeforeErr != nil && strings.Contains(renewBeforeErr.Error(), "renewBefore")},
+		{"invalid key size", true, sizeErr != nil && strings.Contains(sizeErr.Error(), "ECDSA")},
+		{"invalid IP address", true, ipErr != nil && strings.Contains(ipErr.Error(), "10.0.0")},
+		{"unknown usage", true, usageErr != nil && strings.Contains(usageErr.Error(), "server")},
+	}
+	verifyTests(testCSC, t)
+}
+
+func TestSvcCertificateKeystores(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	keystores := &appstacksv1.RuntimeComponentKeystores{PasswordSecretRef: "my-app-keystore"}
+	spec := appstacksv1.RuntimeComponentSpec{Service: &appstacksv1.RuntimeComponentService{Port: 9443,
+		Certificate: &appstacksv1.RuntimeComponentCertificate{Keystores: keystores}}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	password := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-app-keystore", Namespace: namespace}, Data: map[string][]byte{"pass": []byte("changeit")}}
+	objs, s := []runtime.Object{runtimecomponent, password}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+
+	pkcs12Cert := &certmanagerv1.Certificate{}
+	pkcs12Err := CustomizeSvcCertificate(pkcs12Cert, runtimecomponent, "rco-ca-issuer")
+	_, missingKeyErr := r.getKeystorePasswordVersion(runtimecomponent)
+
+	format, alias, key := common.KeystoreFormatJKS, "my-app", "pass"
+	keystores.Format, keystores.Alias, keystores.PasswordSecretKey = &format, &alias, &key
+	jksCert := &certmanagerv1.Certificate{}
+	jksErr := CustomizeSvcCertificate(jksCert, runtimecomponent, "rco-ca-issuer")
+	version, versionErr := r.getKeystorePasswordVersion(runtimecomponent)
+
+	keystores.Format = nil
+	aliasErr := ValidateSvcCertificate(runtimecomponent.Spec.Service.Certificate)
+
+	testSCK := []Test{
+		{"PKCS12 error", nil, pkcs12Err},
+		{"PKCS12 keystore", true, pkcs12Cert.Spec.Keystores.PKCS12 != nil && pkcs12Cert.Spec.Keystores.PKCS12.Create && pkcs12Cert.Spec.Keystores.JKS == nil},
+		{"default password key", "password", pkcs12Cert.Spec.Keystores.PKCS12.PasswordSecretRef.Key},
+		{"missing password key", true, missingKeyErr != nil && strings.Contains(missingKeyErr.Error(), "password")},
+		{"JKS error", nil, jksErr},
+		{"JKS keystore", true, jksCert.Spec.Keystores.JKS != nil && jksCert.Spec.Keystores.PKCS12 == nil},
+		{"JKS alias", "my-app", *jksCert.Spec.Keystores.JKS.Alias},
+		{"JKS password secret", certmanagermetav1.SecretKeySelector{LocalObjectReference: certmanagermetav1.LocalObjectReference{Name: "my-app-keystore"}, Key: "pass"}, jksCert.Spec.Keystores.JKS.PasswordSecretRef},
+		{"password version error", nil, versionErr},
+		{"password version", HashData(map[string][]byte{"pass": []byte("changeit")}), version},
+		{"alias with PKCS12", true, aliasErr != nil && strings.Contains(aliasErr.Error(), "alias")},
+	}
+	verifyTests(testSCK, t)
+}
+
+func TestCABundle(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	bundle := &corev1.ConfigMap{}
+	CustomizeCABundle(bundle, "first-ca", "runtime-component-operator")
+	firstBundle := bundle.Data[CABundleKey]
+	CustomizeCABundle(bundle, "first-ca\n", "runtime-component-operator")
+	unchangedBundle := bundle.Data[CABundleKey]
+	CustomizeCABundle(bundle, "second-ca", "runtime-component-operator")
+	rotatedBundle := bundle.Data[CABundleKey]
+	CustomizeCABundle(bundle, "third-ca", "runtime-component-operator")
+
+	inject, manageTLS := true, false
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ManageTLS: &manageTLS, Trust: &appstacksv1.RuntimeComponentTrust{InjectCABundle: &inject},
+		Env: []corev1.EnvVar{{Name: "NODE_EXTRA_CA_CERTS", Value: "/opt/ca.crt"}}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	runtimecomponent.Status.SetReference(common.StatusReferenceCABundleName, GetCABundleName("rco"))
+	pts := &corev1.PodTemplateSpec{}
+	CustomizePodSpec(pts, runtimecomponent)
+	sslCertFile, _ := GetEnvVarValue(pts.Spec.Containers[0].Env, "SSL_CERT_FILE", "")
+	nodeExtraCACerts, _ := GetEnvVarValue(pts.Spec.Containers[0].Env, "NODE_EXTRA_CA_CERTS", "")
+	bundleVolume := pts.Spec.Volumes[len(pts.Spec.Volumes)-1]
+
+	testCAB := []Test{
+		{"first CA", "first-ca\n", firstBundle},
+		{"same CA", "first-ca\n", unchangedBundle},
+		{"rotated CA", "second-ca\nfirst-ca\n", rotatedBundle},
+		{"previous CA replaced", "third-ca\nsecond-ca\n", bundle.Data[CABundleKey]},
+		{"managed by label", "runtime-component-operator", bundle.Labels["app.kubernetes.io/managed-by"]},
+		{"SSL_CERT_FILE", "/etc/x509/ca-bundle/ca-bundle.crt", sslCertFile},
+		{"NODE_EXTRA_CA_CERTS not overridden", "/opt/ca.crt", nodeExtraCACerts},
+		{"CA bundle volume", "rco-ca-bundle", bundleVolume.ConfigMap.Name},
+		{"CA bundle mount", CABundleMountPath, pts.Spec.Containers[0].VolumeMounts[len(pts.Spec.Containers[0].VolumeMounts)-1].MountPath},
+	}
+	verifyTests(testCAB, t)
+}
+
+func TestClientCertificate(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	issuerKind, required, manageTLS := "ClusterIssuer", true, false
+	certificate := &appstacksv1.RuntimeComponentCertificate{
+		IssuerRef:  &appstacksv1.RuntimeComponentIssuerReference{Name: "internal-ca", Kind: &issuerKind},
+		Duration:   &metav1.Duration{Duration: 720 * time.Hour},
+		ClientAuth: &appstacksv1.RuntimeComponentClientAuth{Required: &required},
+	}
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, ManageTLS: &manageTLS,
+		Service: &appstacksv1.RuntimeComponentService{Port: 9443, Certificate: certificate}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	objs, s := []runtime.Object{runtimecomponent}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	cl := fakeclient.NewFakeClient(objs...)
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+
+	svcCert, clientCert := &certmanagerv1.Certificate{}, &certmanagerv1.Certificate{}
+	CustomizeSvcCertificate(svcCert, runtimecomponent, "rco-ca-issuer")
+	CustomizeClientCertificate(clientCert, svcCert, runtimecomponent)
+
+	runtimecomponent.Status.SetReference(common.StatusReferenceClientCertSecretName, clientCert.Spec.SecretName)
+	pts := &corev1.PodTemplateSpec{}
+	CustomizePodSpec(pts, runtimecomponent)
+	clientTLSDir, _ := GetEnvVarValue(pts.Spec.Containers[0].Env, "CLIENT_TLS_DIR", "")
+
+	bindingSecret := &corev1.Secret{}
+	r.applyDefaultValuesToExpose(bindingSecret, runtimecomponent)
+
+	disabled := false
+	certificate.ClientAuth.Certificate = &disabled
+	disabledCert := IsClientCertificateEnabled(runtimecomponent)
+
+	testCC := []Test{
+		{"same issuer", svcCert.Spec.IssuerRef, clientCert.Spec.IssuerRef},
+		{"same duration", 720 * time.Hour, clientCert.Spec.Duration.Duration},
+		{"client auth usage", []certmanagerv1.KeyUsage{certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageKeyEncipherment, certmanagerv1.UsageClientAuth}, clientCert.Spec.Usages},
+		{"client secret", name + "-client-tls-cm", clientCert.Spec.SecretName},
+		{"CLIENT_TLS_DIR", "/etc/x509/client-certs", clientTLSDir},
+		{"client certificate volume", name + "-client-tls-cm", pts.Spec.Volumes[len(pts.Spec.Volumes)-1].Secret.SecretName},
+		{"client auth advertised", "required", string(bindingSecret.Data["clientAuth"])},
+		{"client certificate disabled", false, disabledCert},
+		{"client auth still required", true, IsClientAuthRequired(runtimecomponent)},
+	}
+	verifyTests(testCC, t)
+}
+
+func TestDeleteSvcCertificates(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: &appstacksv1.RuntimeComponentService{Port: 9443}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	clientCert := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: getClientCertName(runtimecomponent), Namespace: namespace}}
+	objs, s := []runtime.Object{runtimecomponent, clientCert}, scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, runtimecomponent)
+	certmanagerv1.AddToScheme(s)
+
+	// The client certificate cannot be deleted
+	deleteErr, failDelete := kerrors.NewForbidden(certmanagerv1.Resource("certificates"), clientCert.Name, fmt.Errorf("not allowed")), true
+	cl := fakeclient.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if failDelete && obj.GetName() == clientCert.Name {
+				return deleteErr
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+	}).Build()
+	r := NewReconcilerBase(cl, cl, s, &rest.Config{}, record.NewFakeRecorder(10))
+	dc := createFakeDiscoveryClient().(*fakediscovery.FakeDiscovery)
+	dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+		GroupVersion: certmanagerv1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "certificates", Namespaced: true, Kind: "Certificate"}},
+	})
+	r.SetDiscoveryClient(dc)
+
+	provider := &CertManagerCertificateProvider{Prefix: "rco", CACommonName: "Runtime Component Operator", OperatorName: "runtime-component-operator"}
+	providerErr := provider.DeleteCertificate(&r, runtimecomponent)
+	manageTLS := false
+	runtimecomponent.Spec.ManageTLS = &manageTLS
+	_, generateErr := r.GenerateSvcCertSecret(runtimecomponent, "rco", "Runtime Component Operator", "runtime-component-operator")
+
+	// The certificates that do not exist are ignored
+	failDelete = false
+	cl.Delete(context.TODO(), clientCert)
+	notFoundErr := r.deleteSvcCertificates(runtimecomponent)
+
+	testDSC := []Test{
+		{"provider deletion error", deleteErr, providerErr},
+		{"deletion error when TLS is not managed", deleteErr, generateErr},
+		{"certificates not found", nil, notFoundErr},
+	}
+	verifyTests(testDSC, t)
+}
+
 func createFakeDiscoveryClient() discovery.DiscoveryInterface {
 	fakeDiscoveryClient := &fakediscovery.FakeDiscovery{Fake: &coretesting.Fake{}}
 	fakeDiscoveryClient.Resources = []*metav1.APIResourceList{
@@ -0,0 +1,245 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	certmanagermetav1 "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/rest"
+	coretesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// RenderOptions describes the platform that manifests are rendered for
+type RenderOptions struct {
+	// OpenShift renders Routes and OpenShift specific configuration instead of Ingresses
+	OpenShift bool
+	// CertManager renders cert-manager issuers and certificates for the service certificate
+	CertManager bool
+	// Knative allows rendering a Knative Service when it is requested by the instance
+	Knative bool
+	// ServiceMonitor renders a Prometheus Operator ServiceMonitor or PodMonitor when monitoring is configured
+	ServiceMonitor bool
+	// GrafanaOperator renders the dashboard as a GrafanaDashboard instead of a ConfigMap
+	GrafanaOperator bool
+	// OpenTelemetryOperator renders the annotations that request the OpenTelemetry Operator to inject the instrumentation
+	OpenTelemetryOperator bool
+
+	// Prefix, CACommonName and OperatorName are used for the cert-manager resources shared by the namespace
+	Prefix       string
+	CACommonName string
+	OperatorName string
+
+	// Scheme holds the types of the instance and of the rendered resources
+	Scheme *runtime.Scheme
+	// Objects already exist on the cluster, such as the Secrets and ServiceAccount referenced by the instance or the
+	// ConfigMap of the Prometheus Adapter
+	Objects []client.Object
+}
+
+// ErrKnativeNotSupported is returned when a Knative Service is requested on a platform without Knative
+var ErrKnativeNotSupported = errors.New("failed to reconcile Knative service as operator could not find Knative CRDs")
+
+// Placeholder of the certificates and keys issued on the cluster, see RenderResources
+var renderPlaceholderSecretData = map[string][]byte{
+	"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("placeholder")}),
+	"tls.key": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("placeholder")}),
+	"ca.crt":  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("placeholder")}),
+}
+
+// RenderResources runs the reconcile pipeline of the instance against a dry-run client, on the platform described by
+// opts, and returns the resources that it creates in the order they are created. The dry-run client starts with the
+// instance and opts.Objects. cert-manager and the OpenShift service CA are simulated: issuers and certificates are ready
+// once they are created, and the secrets that they issue hold placeholder certificates, which are also used for the
+// secret hash annotations and the TLS values of Routes. Owner references are left out, as the instance has no UID until
+// it is created, and so are the changes to objects that already exist. The instance is expected to be initialized and
+// its status is updated the same way reconciliation would.
+func RenderResources(ba common.BaseComponent, opts RenderOptions) ([]client.Object, error) {
+	rendered := &renderedResources{scheme: opts.Scheme}
+	objs := append([]client.Object{ba.(client.Object).DeepCopyObject().(client.Object)}, opts.Objects...)
+	// The kinds of the scheme are mapped with their scope, so that cluster-scoped extra resources are rejected
+	cl := fakeclient.NewClientBuilder().WithScheme(opts.Scheme).WithObjects(objs...).
+		WithRESTMapper(testrestmapper.TestOnlyStaticRESTMapper(opts.Scheme)).
+		WithInterceptorFuncs(interceptor.Funcs{Create: rendered.create, Update: rendered.update, Delete: rendered.delete}).Build()
+	r := NewReconcilerBase(cl, cl, opts.Scheme, &rest.Config{}, &record.FakeRecorder{})
+	r.SetDiscoveryClient(newRenderDiscoveryClient(opts))
+
+	p := r.NewReconcilePipeline(opts.Prefix, opts.CACommonName, opts.OperatorName)
+	// The placeholder certificates have no expiry
+	if err := p.Skip(ReconcileStepCertificateExpiry); err != nil {
+		return nil, err
+	}
+	// The pipeline reports the error of a step in the status of the instance
+	var stepErr error
+	for i := range p.steps {
+		run := p.steps[i].Run
+		p.steps[i].Run = func(ba common.BaseComponent, state *ReconcileState) error {
+			stepErr = run(ba, state)
+			return stepErr
+		}
+	}
+	if _, err := p.ReconcileBaseComponent(context.Background(), ba); err != nil {
+		return nil, err
+	}
+	if stepErr != nil {
+		return nil, stepErr
+	}
+	return rendered.objects, nil
+}
+
+// renderedResources records the resources created through the dry-run client of RenderResources
+type renderedResources struct {
+	scheme  *runtime.Scheme
+	objects []client.Object
+}
+
+func (rr *renderedResources) indexOf(obj client.Object) int {
+	gvk, err := apiutil.GVKForObject(obj, rr.scheme)
+	if err != nil {
+		return -1
+	}
+	for i, o := range rr.objects {
+		if ogvk, err := apiutil.GVKForObject(o, rr.scheme); err == nil && ogvk == gvk &&
+			o.GetNamespace() == obj.GetNamespace() && o.GetName() == obj.GetName() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (rr *renderedResources) create(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+	rendered := obj.DeepCopyObject().(client.Object)
+	rendered.SetOwnerReferences(nil)
+	// The issuers and certificates of cert-manager are ready and their secrets issued
+	var secretName string
+	switch o := obj.(type) {
+	case *certmanagerv1.Issuer:
+		o.Status.Conditions = []certmanagerv1.IssuerCondition{{Type: certmanagerv1.IssuerConditionReady, Status: certmanagermetav1.ConditionTrue}}
+	case *certmanagerv1.Certificate:
+		o.Status.Conditions = []certmanagerv1.CertificateCondition{{Type: certmanagerv1.CertificateConditionReady, Status: certmanagermetav1.ConditionTrue}}
+		secretName = o.Spec.SecretName
+	case *corev1.Service:
+		// The OpenShift service CA issues the secret in the annotation of the Service
+		secretName = o.Annotations["service.beta.openshift.io/serving-cert-secret-name"]
+		if name := o.Annotations["service.alpha.openshift.io/serving-cert-secret-name"]; name != "" {
+			secretName = name
+		}
+	}
+	if err := c.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	rr.objects = append(rr.objects, rendered)
+	if secretName == "" {
+		return nil
+	}
+	return createRenderPlaceholderSecret(ctx, c, secretName, obj.GetNamespace())
+}
+
+func (rr *renderedResources) update(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if i := rr.indexOf(obj); i >= 0 {
+		rendered := obj.DeepCopyObject().(client.Object)
+		rendered.SetOwnerReferences(nil)
+		rendered.SetResourceVersion("")
+		rr.objects[i] = rendered
+	}
+	return nil
+}
+
+func (rr *renderedResources) delete(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+	if i := rr.indexOf(obj); i >= 0 {
+		rr.objects = append(rr.objects[:i], rr.objects[i+1:]...)
+	}
+	// cert-manager issues the secret of a certificate again when it is deleted
+	if _, ok := obj.(*corev1.Secret); ok {
+		certs := &certmanagerv1.CertificateList{}
+		if err := c.List(ctx, certs, client.InNamespace(obj.GetNamespace())); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		for i := range certs.Items {
+			if certs.Items[i].Spec.SecretName == obj.GetName() {
+				return createRenderPlaceholderSecret(ctx, c, obj.GetName(), obj.GetNamespace())
+			}
+		}
+	}
+	return nil
+}
+
+// createRenderPlaceholderSecret creates a secret issued on the cluster with a placeholder certificate
+func createRenderPlaceholderSecret(ctx context.Context, c client.WithWatch, name string, namespace string) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Type: corev1.SecretTypeTLS,
+		Data: renderPlaceholderSecretData}
+	return client.IgnoreAlreadyExists(c.Create(ctx, secret))
+}
+
+// newRenderDiscoveryClient returns a discovery client with the APIs of the platform described by opts
+func newRenderDiscoveryClient(opts RenderOptions) *fakediscovery.FakeDiscovery {
+	apis := map[schema.GroupVersion][]string{
+		networkingv1.SchemeGroupVersion: {"Ingress"},
+	}
+	if opts.OpenShift {
+		apis[routev1.SchemeGroupVersion] = []string{"Route"}
+	}
+	if opts.CertManager {
+		apis[certmanagerv1.SchemeGroupVersion] = []string{"Issuer", "Certificate"}
+	}
+	if opts.Knative {
+		apis[servingv1.SchemeGroupVersion] = []string{"Service"}
+	}
+	if opts.ServiceMonitor {
+		apis[prometheusv1.SchemeGroupVersion] = []string{"ServiceMonitor", "PodMonitor", "PrometheusRule"}
+	}
+	if opts.GrafanaOperator {
+		apis[GrafanaDashboardGVK.GroupVersion()] = []string{GrafanaDashboardGVK.Kind}
+	}
+	if opts.OpenTelemetryOperator {
+		apis[OpenTelemetryInstrumentationGVK.GroupVersion()] = []string{OpenTelemetryInstrumentationGVK.Kind}
+	}
+	dc := &fakediscovery.FakeDiscovery{Fake: &coretesting.Fake{}}
+	for gv, kinds := range apis {
+		list := &metav1.APIResourceList{GroupVersion: gv.String()}
+		for _, kind := range kinds {
+			list.APIResources = append(list.APIResources, metav1.APIResource{Kind: kind, Namespaced: true})
+		}
+		dc.Resources = append(dc.Resources, list)
+	}
+	return dc
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	prometheusv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestRenderResources(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+	s := scheme.Scheme
+	s.AddKnownTypes(appstacksv1.GroupVersion, &appstacksv1.RuntimeComponent{})
+	routev1.AddToScheme(s)
+	servingv1.AddToScheme(s)
+	certmanagerv1.AddToScheme(s)
+	prometheusv1.AddToScheme(s)
+	opts := RenderOptions{Prefix: "rco", CACommonName: "Runtime Component Operator", OperatorName: "runtime-component-operator", Scheme: s}
+
+	// Kubernetes without any optional CRDs, where the service certificate is not managed
+	manageTLS := false
+	runtime := createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Expose: &expose, ManageTLS: &manageTLS})
+	runtime.Initialize()
+	kubernetes, _ := RenderResources(runtime, opts)
+
+	// Kubernetes without cert-manager, where the service certificate cannot be issued
+	runtime = createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage})
+	runtime.Initialize()
+	_, certificateErr := RenderResources(runtime, opts)
+
+	// OpenShift with cert-manager and monitoring
+	runtime = createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Expose: &expose,
+		Monitoring: &appstacksv1.RuntimeComponentMonitoring{}, StatefulSet: &appstacksv1.RuntimeComponentStatefulSet{}})
+	runtime.Initialize()
+	openShiftOpts := opts
+	openShiftOpts.OpenShift, openShiftOpts.CertManager, openShiftOpts.ServiceMonitor = true, true, true
+	openShift, openShiftErr := RenderResources(runtime, openShiftOpts)
+	certSecretName := runtime.Status.GetReferences()[common.StatusReferenceCertSecretName]
+	var podAnnotations map[string]string
+	for _, obj := range openShift {
+		if statefulSet, ok := obj.(*appsv1.StatefulSet); ok {
+			podAnnotations = statefulSet.Spec.Template.Annotations
+		}
+	}
+
+	// OpenShift service CA
+	openShiftOpts.CertManager = false
+	serviceCA, _ := RenderResources(runtime, openShiftOpts)
+
+	// Pull secret of the service account, which must exist
+	runtime = createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, ManageTLS: &manageTLS,
+		PullSecret: &pullSecret})
+	runtime.Initialize()
+	_, pullSecretErr := RenderResources(runtime, opts)
+	existingOpts := opts
+	existingOpts.Objects = []client.Object{&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: pullSecret, Namespace: namespace}}}
+	pullSecretResources, _ := RenderResources(runtime, existingOpts)
+
+	// Knative Service requested on a cluster without Knative
+	runtime = createRuntimeComponent(name, namespace, appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, CreateKnativeService: &createKNS})
+	runtime.Initialize()
+	_, knativeErr := RenderResources(runtime, opts)
+	knativeOpts := opts
+	knativeOpts.Knative = true
+	knativeResources, _ := RenderResources(runtime, knativeOpts)
+
+	testRR := []Test{
+		{"Kubernetes resources", []string{"ServiceAccount", "Service", "NetworkPolicy", "Deployment", "Ingress"}, renderedKinds(kubernetes)},
+		{"Kubernetes service certificate", "Service certifcate secret name must not be empty", fmt.Sprint(certificateErr)},
+		{"OpenShift error", nil, openShiftErr},
+		{"OpenShift resources", []string{"ServiceAccount", "Issuer", "Certificate", "Issuer", "ConfigMap", "Certificate", "Service", "NetworkPolicy", "Service", "StatefulSet", "Route", "ServiceMonitor"}, renderedKinds(openShift)},
+		{"OpenShift service certificate reference", name + "-svc-tls-cm", certSecretName},
+		{"OpenShift service certificate hash", HashData(renderPlaceholderSecretData), podAnnotations[runtime.GetGroupName()+"/secret-"+certSecretName]},
+
+		{"OpenShift owner references", true, len(openShift) > 0 && len(openShift[len(openShift)-1].GetOwnerReferences()) == 0},
+		{"OpenShift service CA resources", []string{"ServiceAccount", "Service", "NetworkPolicy", "Service", "StatefulSet", "Route", "ServiceMonitor"}, renderedKinds(serviceCA)},
+		{"Missing pull secret", true, pullSecretErr != nil},
+		{"Existing pull secret", []string{"ServiceAccount", "Service", "NetworkPolicy", "Deployment"}, renderedKinds(pullSecretResources)},
+		{"Knative not supported", ErrKnativeNotSupported, knativeErr},
+		{"Knative resources", []string{"ServiceAccount", "Service"}, renderedKinds(knativeResources)},
+	}
+	verifyTests(testRR, t)
+	common.Config = common.DefaultOpConfig()
+}
+
+// renderedKinds returns the Go type names of the rendered resources, as no GVK is set on them
+func renderedKinds(resources []client.Object) []string {
+	kinds := []string{}
+	for _, obj := range resources {
+		kind := fmt.Sprintf("%T", obj)
+		kinds = append(kinds, kind[strings.LastIndex(kind, ".")+1:])
+	}
+	return kinds
+}
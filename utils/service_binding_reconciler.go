@@ -139,6 +139,10 @@ func (r *ReconcilerBase) applyDefaultValuesToExpose(secret *corev1.Secret, ba co
 		}
 	}
 
+	if _, found = secretData["clientAuth"]; !found && IsClientAuthRequired(ba) {
+		secretData["clientAuth"] = []byte("required")
+	}
+
 	if _, found = secretData["ingress-uri"]; !found && ba.GetExpose() != nil && *ba.GetExpose() {
 
 		host, path, protocol := r.GetIngressInfo(ba)
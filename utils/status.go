@@ -79,14 +79,24 @@ func (r *ReconcilerBase) CheckResourcesStatus(ba common.BaseComponent) {
 	oldCondition := s.GetCondition(conditionType)
 	newCondition := s.NewCondition(conditionType)
 
+	newCondition = r.checkWorkloadStatus(ba, newCondition)
+
+	r.setCondition(ba, oldCondition, newCondition)
+}
+
+// checkWorkloadStatus sets the fields of the condition from the status of the workload of the instance
+func (r *ReconcilerBase) checkWorkloadStatus(ba common.BaseComponent, c common.StatusCondition) common.StatusCondition {
 	// Check for Deployment, StatefulSet replicas or Knative service status
 	if ba.GetCreateKnativeService() == nil || !*ba.GetCreateKnativeService() {
-		newCondition = r.areReplicasReady(ba, newCondition)
-	} else {
-		newCondition = r.isKnativeReady(ba, newCondition)
+		return r.areReplicasReady(ba, c)
 	}
+	return r.isKnativeReady(ba, c)
+}
 
-	r.setCondition(ba, oldCondition, newCondition)
+// isWorkloadReady returns true if the workload of the instance is ready, as reported in the ResourcesReady condition
+func (r *ReconcilerBase) isWorkloadReady(ba common.BaseComponent) bool {
+	c := r.checkWorkloadStatus(ba, ba.GetStatus().NewCondition(common.StatusConditionTypeResourcesReady))
+	return c.GetStatus() == corev1.ConditionTrue
 }
 
 func (r *ReconcilerBase) setCondition(ba common.BaseComponent, oldCondition common.StatusCondition, newCondition common.StatusCondition) {
@@ -0,0 +1,207 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// OpenTelemetryInstrumentationGVK is the kind of the instrumentations of the OpenTelemetry Operator
+var OpenTelemetryInstrumentationGVK = schema.GroupVersionKind{Group: "opentelemetry.io", Version: "v1alpha1", Kind: "Instrumentation"}
+
+const (
+	// Name of the OpenTelemetry Collector sidecar container
+	tracingCollectorContainerName = "otel-collector"
+	// Endpoints of the OTLP receivers of the collector sidecar
+	tracingCollectorGRPCEndpoint = "localhost:4317"
+	tracingCollectorHTTPEndpoint = "localhost:4318"
+	tracingProtocolGRPC          = "grpc"
+	// Annotations of the pods that request the injection of the instrumentation by the OpenTelemetry Operator
+	openTelemetryInjectAnnotationPrefix   = "instrumentation.opentelemetry.io/inject-"
+	openTelemetryContainerNamesAnnotation = "instrumentation.opentelemetry.io/container-names"
+)
+
+// getTracing returns the tracing settings of the instance, or nil when tracing is not configured
+func getTracing(ba common.BaseComponent) common.BaseComponentTracing {
+	if ba.GetObservability() == nil {
+		return nil
+	}
+	return ba.GetObservability().GetTracing()
+}
+
+// getTracingProtocol returns the protocol of the OTLP exporter of the application, or an empty string for the default
+// of the OpenTelemetry SDK. The collector sidecar receives gRPC by default
+func getTracingProtocol(tracing common.BaseComponentTracing) string {
+	if tracing.GetProtocol() != nil {
+		return *tracing.GetProtocol()
+	}
+	if tracing.GetCollector() != nil {
+		return tracingProtocolGRPC
+	}
+	return ""
+}
+
+// appendTracingEnv appends the OTEL_* environment variables of .spec.observability.tracing that are not already set
+func appendTracingEnv(env []corev1.EnvVar, ba common.BaseComponent) []corev1.EnvVar {
+	tracing := getTracing(ba)
+	if tracing == nil {
+		return env
+	}
+	obj := ba.(metav1.Object)
+
+	// Attributes set by the operator, which are overridden by the attributes of the instance
+	attributes := map[string]string{
+		"k8s.namespace.name": obj.GetNamespace(),
+		"k8s.pod.name":       "$(OTEL_K8S_POD_NAME)",
+	}
+	if ba.GetApplicationName() != "" {
+		attributes["service.namespace"] = ba.GetApplicationName()
+	}
+	attributes = MergeMaps(attributes, tracing.GetResourceAttributes())
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + attributes[k]
+	}
+
+	// The pod name is defined first so that it is expanded in OTEL_RESOURCE_ATTRIBUTES
+	vars := []corev1.EnvVar{
+		{Name: "OTEL_K8S_POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		{Name: "OTEL_SERVICE_NAME", Value: obj.GetName()},
+		{Name: "OTEL_RESOURCE_ATTRIBUTES", Value: strings.Join(pairs, ",")},
+	}
+	protocol := getTracingProtocol(tracing)
+	if tracing.GetCollector() != nil {
+		endpoint := tracingCollectorGRPCEndpoint
+		if protocol != tracingProtocolGRPC {
+			endpoint = tracingCollectorHTTPEndpoint
+		}
+		vars = append(vars, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://" + endpoint})
+	} else if tracing.GetEndpoint() != nil {
+		vars = append(vars, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: *tracing.GetEndpoint()})
+	}
+	if protocol != "" {
+		vars = append(vars, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: protocol})
+	}
+	vars = append(vars, corev1.EnvVar{Name: "OTEL_TRACES_SAMPLER", Value: tracing.GetSampler()})
+	if tracing.GetSamplerArgument() != nil {
+		vars = append(vars, corev1.EnvVar{Name: "OTEL_TRACES_SAMPLER_ARG", Value: *tracing.GetSamplerArgument()})
+	}
+	vars = append(vars, corev1.EnvVar{Name: "OTEL_PROPAGATORS", Value: strings.Join(tracing.GetPropagators(), ",")})
+
+	for _, v := range vars {
+		if _, found := GetEnvVarValue(env, v.Name, ""); !found {
+			env = append(env, v)
+		}
+	}
+	return env
+}
+
+// getTracingCollectorContainer returns the OpenTelemetry Collector sidecar of the instance, or nil when it is not
+// enabled. The collector receives OTLP on localhost and exports the traces to the endpoint of the tracing settings
+func getTracingCollectorContainer(ba common.BaseComponent) *corev1.Container {
+	tracing := getTracing(ba)
+	if tracing == nil || tracing.GetCollector() == nil {
+		return nil
+	}
+
+	exporter, exporterConfig := "debug", map[string]interface{}{}
+	if endpoint := tracing.GetEndpoint(); endpoint != nil {
+		exporterConfig["endpoint"] = *endpoint
+		if getTracingProtocol(tracing) == tracingProtocolGRPC {
+			exporter = "otlp"
+			if strings.HasPrefix(*endpoint, "http://") {
+				exporterConfig["tls"] = map[string]interface{}{"insecure": true}
+			}
+		} else {
+			exporter = "otlphttp"
+		}
+	}
+	config := map[string]interface{}{
+		"receivers": map[string]interface{}{"otlp": map[string]interface{}{"protocols": map[string]interface{}{
+			"grpc": map[string]interface{}{"endpoint": tracingCollectorGRPCEndpoint},
+			"http": map[string]interface{}{"endpoint": tracingCollectorHTTPEndpoint},
+		}}},
+		"processors": map[string]interface{}{"batch": map[string]interface{}{}},
+		"exporters":  map[string]interface{}{exporter: exporterConfig},
+		"service": map[string]interface{}{"pipelines": map[string]interface{}{"traces": map[string]interface{}{
+			"receivers": []string{"otlp"}, "processors": []string{"batch"}, "exporters": []string{exporter},
+		}}},
+	}
+	// The configuration only has strings, maps and lists, which are always marshalled
+	data, _ := yaml.Marshal(config)
+
+	valFalse, valTrue := false, true
+	container := &corev1.Container{
+		Name:  tracingCollectorContainerName,
+		Image: tracing.GetCollector().GetImage(),
+		Args:  []string{"--config=env:OTEL_COLLECTOR_CONFIG"},
+		Env:   []corev1.EnvVar{{Name: "OTEL_COLLECTOR_CONFIG", Value: string(data)}},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &valFalse,
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			Privileged:               &valFalse,
+			RunAsNonRoot:             &valTrue,
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+	}
+	if resources := tracing.GetCollector().GetResources(); resources != nil {
+		container.Resources = *resources
+	}
+	return container
+}
+
+// GetOpenTelemetryInstrumentationAnnotations returns the annotations of the pods that request the OpenTelemetry
+// Operator to inject the instrumentation of .spec.observability.tracing.instrumentation in the application container
+func GetOpenTelemetryInstrumentationAnnotations(ba common.BaseComponent, containerName string) map[string]string {
+	tracing := getTracing(ba)
+	if tracing == nil || tracing.GetInstrumentation() == nil {
+		return nil
+	}
+	instrumentation := tracing.GetInstrumentation()
+	annotations := map[string]string{openTelemetryInjectAnnotationPrefix + instrumentation.GetLanguage(): instrumentation.GetName()}
+	// The container of a Knative Service is named by Knative, and is the first container
+	if containerName != "" {
+		annotations[openTelemetryContainerNamesAnnotation] = containerName
+	}
+	return annotations
+}
+
+// CustomizeOpenTelemetryInstrumentation removes the instrumentation annotations from the metadata of the pod template, and
+// sets the annotations of the instance when injected is true. It is called before the annotations of the instance are
+// merged, so that the instrumentation annotations set by the user are kept
+func CustomizeOpenTelemetryInstrumentation(meta *metav1.ObjectMeta, ba common.BaseComponent, containerName string, injected bool) {
+	for key := range meta.Annotations {
+		if strings.HasPrefix(key, openTelemetryInjectAnnotationPrefix) || key == openTelemetryContainerNamesAnnotation {
+			delete(meta.Annotations, key)
+		}
+	}
+	if injected {
+		meta.Annotations = MergeMaps(meta.Annotations, GetOpenTelemetryInstrumentationAnnotations(ba, containerName))
+	}
+}
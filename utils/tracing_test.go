@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
+	"github.com/application-stacks/runtime-component-operator/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestCustomizeTracing(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	endpoint, ratio, userService := "http://otel-collector.observability:4317", "0.25", "user-service"
+	sampler := "parentbased_traceidratio"
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, Service: service, ApplicationName: "shop",
+		Env: []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: userService}}}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Spec.Observability = &appstacksv1.RuntimeComponentObservability{Tracing: &appstacksv1.RuntimeComponentTracing{
+		Endpoint: &endpoint, Sampler: &sampler, SamplerArgument: &ratio,
+		ResourceAttributes: map[string]string{"deployment.environment": "prod", "service.namespace": "store"},
+		Instrumentation:    &appstacksv1.RuntimeComponentTracingInstrumentation{Language: "java"},
+	}}
+
+	deploy := &appsv1.Deployment{}
+	CustomizePodSpec(&deploy.Spec.Template, runtime)
+	env := deploy.Spec.Template.Spec.Containers[0].Env
+	serviceName, _ := GetEnvVarValue(env, "OTEL_SERVICE_NAME", "")
+	attributes, _ := GetEnvVarValue(env, "OTEL_RESOURCE_ATTRIBUTES", "")
+	exporterEndpoint, _ := GetEnvVarValue(env, "OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	_, protocolSet := GetEnvVarValue(env, "OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	tracesSampler, _ := GetEnvVarValue(env, "OTEL_TRACES_SAMPLER", "")
+	samplerArg, _ := GetEnvVarValue(env, "OTEL_TRACES_SAMPLER_ARG", "")
+	propagators, _ := GetEnvVarValue(env, "OTEL_PROPAGATORS", "")
+	annotations := GetOpenTelemetryInstrumentationAnnotations(runtime, "app")
+
+	// The instrumentation annotations are removed with the instrumentation, and the annotations of the user are kept
+	instrumented := &appsv1.Deployment{}
+	CustomizeOpenTelemetryInstrumentation(&instrumented.Spec.Template.ObjectMeta, runtime, "app", true)
+	instrumentedAnnotations := len(instrumented.Spec.Template.Annotations)
+	runtime.Spec.Observability.Tracing.Instrumentation = nil
+	runtime.Annotations = map[string]string{"instrumentation.opentelemetry.io/inject-python": "true"}
+	CustomizeOpenTelemetryInstrumentation(&instrumented.Spec.Template.ObjectMeta, runtime, "app", true)
+	CustomizePodSpec(&instrumented.Spec.Template, runtime)
+	removedAnnotations := instrumented.Spec.Template.Annotations
+	runtime.Annotations = nil
+	noCollectorContainers := len(deploy.Spec.Template.Spec.Containers)
+
+	// Enable the collector sidecar with the defaults
+	runtime.Spec.Observability.Tracing = &appstacksv1.RuntimeComponentTracing{Endpoint: &endpoint, Collector: &appstacksv1.RuntimeComponentTracingCollector{}}
+	CustomizePodSpec(&deploy.Spec.Template, runtime)
+	containers := deploy.Spec.Template.Spec.Containers
+	collectorEnv := containers[0].Env
+	sidecarEndpoint, _ := GetEnvVarValue(collectorEnv, "OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	sidecarProtocol, _ := GetEnvVarValue(collectorEnv, "OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	defaultSampler, _ := GetEnvVarValue(collectorEnv, "OTEL_TRACES_SAMPLER", "")
+	defaultPropagators, _ := GetEnvVarValue(collectorEnv, "OTEL_PROPAGATORS", "")
+	defaultAttributes, _ := GetEnvVarValue(collectorEnv, "OTEL_RESOURCE_ATTRIBUTES", "")
+	collectorConfig := containers[len(containers)-1].Env[0].Value
+
+	ksvc := &servingv1.Service{}
+	runtime.Spec.CreateKnativeService, runtime.Spec.PullPolicy = &createKNS, &pullPolicy
+	CustomizeKnativeService(ksvc, runtime)
+	CustomizeKnativeService(ksvc, runtime)
+
+	testCT := []Test{
+		{"service name set by the user", userService, serviceName},
+		{"resource attributes", "deployment.environment=prod,k8s.namespace.name=runtime,k8s.pod.name=$(OTEL_K8S_POD_NAME),service.namespace=store", attributes},
+		{"exporter endpoint", endpoint, exporterEndpoint},
+		{"default protocol", false, protocolSet},
+		{"sampler", sampler, tracesSampler},
+		{"sampler argument", ratio, samplerArg},
+		{"default propagators", "tracecontext,baggage", propagators},
+		{"no collector", 1, noCollectorContainers},
+		{"instrumentation annotation", "true", annotations["instrumentation.opentelemetry.io/inject-java"]},
+		{"instrumentation container", "app", annotations["instrumentation.opentelemetry.io/container-names"]},
+		{"instrumentation annotations set", 2, instrumentedAnnotations},
+		{"instrumentation annotation removed", "", removedAnnotations["instrumentation.opentelemetry.io/inject-java"]},
+		{"instrumentation container removed", "", removedAnnotations["instrumentation.opentelemetry.io/container-names"]},
+		{"user instrumentation annotation", "true", removedAnnotations["instrumentation.opentelemetry.io/inject-python"]},
+		{"collector container", tracingCollectorContainerName, containers[1].Name},
+		{"collector image", "otel/opentelemetry-collector:0.111.0", containers[1].Image},
+		{"sidecar endpoint", "http://localhost:4317", sidecarEndpoint},
+		{"sidecar protocol", "grpc", sidecarProtocol},
+		{"default sampler", "parentbased_always_on", defaultSampler},
+		{"propagators", "tracecontext,baggage", defaultPropagators},
+		{"application name attribute", true, strings.HasSuffix(defaultAttributes, ",service.namespace=shop")},
+		{"collector exporter", true, strings.Contains(collectorConfig, "endpoint: "+endpoint)},
+		{"collector insecure", true, strings.Contains(collectorConfig, "insecure: true")},
+		{"Knative collector", 2, len(ksvc.Spec.Template.Spec.Containers)},
+		{"no instrumentation", 0, len(GetOpenTelemetryInstrumentationAnnotations(runtime, ""))},
+	}
+	verifyTests(testCT, t)
+}
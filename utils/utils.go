@@ -35,6 +35,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -117,12 +118,7 @@ func CustomizeRoute(route *routev1.Route, ba common.BaseComponent, key string, c
 		rt := ba.GetRoute()
 		route.Annotations = MergeMaps(route.Annotations, rt.GetAnnotations())
 
-		host := rt.GetHost()
-		defaultHostName := common.LoadFromConfig(common.Config, common.OpConfigDefaultHostname)
-		if host == "" && defaultHostName != "" {
-			host = obj.GetName() + "-" + obj.GetNamespace() + "." + defaultHostName
-		}
-
+		host := getHostnameOrEmpty(ba)
 		ba.GetStatus().SetReference(common.StatusReferenceRouteHost, host)
 		route.Spec.Host = host
 		route.Spec.Path = rt.GetPath()
@@ -347,6 +343,55 @@ func CustomizeNetworkPolicy(networkPolicy *networkingv1.NetworkPolicy, isOpenShi
 
 	customizeNetworkPolicyPorts(&rule, ba)
 	networkPolicy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{rule}
+	if config != nil {
+		networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, config.GetIngress()...)
+	}
+
+	networkPolicy.Spec.Egress = nil
+	if config != nil && config.GetEgress() != nil {
+		networkPolicy.Spec.PolicyTypes = append(networkPolicy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		networkPolicy.Spec.Egress = append(createDefaultNetworkPolicyEgressRules(ba, networkPolicy.Namespace, isOpenShift),
+			config.GetEgress().GetRules()...)
+	}
+}
+
+// createDefaultNetworkPolicyEgressRules returns the rules that allow the outgoing traffic to DNS and to the pods of
+// the dependencies of the instance. A dependency without a namespace is in the namespace of the instance
+func createDefaultNetworkPolicyEgressRules(ba common.BaseComponent, namespace string, isOpenShift bool) []networkingv1.NetworkPolicyEgressRule {
+	dnsPorts := []int32{53}
+	if isOpenShift {
+		// The DNS pods of OpenShift listen on port 5353
+		dnsPorts = append(dnsPorts, 5353)
+	}
+	dns := networkingv1.NetworkPolicyEgressRule{}
+	for _, port := range dnsPorts {
+		for _, protocol := range []corev1.Protocol{corev1.ProtocolUDP, corev1.ProtocolTCP} {
+			dnsPort := intstr.FromInt32(port)
+			dns.Ports = append(dns.Ports, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &dnsPort})
+		}
+	}
+	rules := []networkingv1.NetworkPolicyEgressRule{dns}
+
+	dependencies := ba.GetNetworkPolicy().GetEgress().GetDependencies()
+	if len(dependencies) == 0 {
+		return rules
+	}
+	dependencyRule := networkingv1.NetworkPolicyEgressRule{}
+	for _, dependency := range dependencies {
+		dependencyNamespace := dependency.GetNamespace()
+		if dependencyNamespace == "" {
+			dependencyNamespace = namespace
+		}
+		dependencyRule.To = append(dependencyRule.To, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": dependencyNamespace},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{common.GetComponentNameLabel(ba): dependency.GetName()},
+			},
+		})
+	}
+	return append(rules, dependencyRule)
 }
 
 func createOpenShiftNetworkPolicyIngressRule(appName string, namespace string, isExposed bool, config common.BaseComponentNetworkPolicy) networkingv1.NetworkPolicyIngressRule {
@@ -399,6 +444,18 @@ func createKubernetesNetworkPolicyIngressRule(appName string, namespace string,
 	rule.From = []networkingv1.NetworkPolicyPeer{
 		createNetworkPolicyPeer(appName, namespace, config),
 	}
+
+	// Add peer to allow traffic from the namespaces of monitoring, as on OpenShift
+	monitoringLabels, err := labels.ConvertSelectorToLabelsMap(common.LoadFromConfig(common.Config, common.OpConfigMonitoringNamespaceLabels))
+	if err != nil {
+		log.Error(err, "Failed to parse "+common.OpConfigMonitoringNamespaceLabels+" in the operator ConfigMap")
+	} else if len(monitoringLabels) > 0 {
+		rule.From = append(rule.From, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: monitoringLabels,
+			},
+		})
+	}
 	return rule
 }
 
@@ -636,7 +693,7 @@ func GetEnvVarValue(envs []corev1.EnvVar, name string, defaultValue string) (str
 func CustomizePodSpec(pts *corev1.PodTemplateSpec, ba common.BaseComponent) {
 	obj := ba.(metav1.Object)
 	pts.Labels = ba.GetLabels()
-	pts.Annotations = MergeMaps(pts.Annotations, ba.GetAnnotations())
+	pts.Annotations = mergePodTemplateAnnotations(pts.Annotations, ba)
 
 	// If they exist, add annotations from the StatefulSet or Deployment to the pods
 	// Both structs can exist, but if StatefulSet =! nil, then that is 'active' and the
@@ -720,13 +777,60 @@ func CustomizePodSpec(pts *corev1.PodTemplateSpec, ba common.BaseComponent) {
 		})
 	}
 
+	if secretName := ba.GetStatus().GetReferences()[common.StatusReferenceClientCertSecretName]; secretName != "" && IsClientCertificateEnabled(ba) {
+		clientTLSDir, found := GetEnvVarValue(appContainer.Env, "CLIENT_TLS_DIR", "/etc/x509/client-certs")
+		if !found {
+			appContainer.Env = append(appContainer.Env, corev1.EnvVar{Name: "CLIENT_TLS_DIR", Value: clientTLSDir})
+		}
+		pts.Spec.Volumes = append(pts.Spec.Volumes, corev1.Volume{
+			Name: "client-certificate",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+				},
+			},
+		})
+		appContainer.VolumeMounts = append(appContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      "client-certificate",
+			MountPath: clientTLSDir,
+			ReadOnly:  true,
+		})
+	}
+
+	if bundleName := ba.GetStatus().GetReferences()[common.StatusReferenceCABundleName]; bundleName != "" && IsCABundleInjected(ba) {
+		bundleFile := CABundleMountPath + "/" + CABundleKey
+		for _, name := range []string{"SSL_CERT_FILE", "NODE_EXTRA_CA_CERTS"} {
+			if _, found := GetEnvVarValue(appContainer.Env, name, bundleFile); !found {
+				appContainer.Env = append(appContainer.Env, corev1.EnvVar{Name: name, Value: bundleFile})
+			}
+		}
+		pts.Spec.Volumes = append(pts.Spec.Volumes, corev1.Volume{
+			Name: "ca-bundle",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: bundleName},
+				},
+			},
+		})
+		appContainer.VolumeMounts = append(appContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      "ca-bundle",
+			MountPath: CABundleMountPath,
+			ReadOnly:  true,
+		})
+	}
+
 	// This ensures that the pods are updated if the service account is updated
 	saRV := ba.GetStatus().GetReferences()[common.StatusReferenceSAResourceVersion]
 	if saRV != "" {
 		appContainer.Env = append(appContainer.Env, corev1.EnvVar{Name: "SA_RESOURCE_VERSION", Value: saRV})
 	}
 
+	appContainer.Env = appendTracingEnv(appContainer.Env, ba)
+
 	pts.Spec.Containers = append([]corev1.Container{appContainer}, ba.GetSidecarContainers()...)
+	if collector := getTracingCollectorContainer(ba); collector != nil {
+		pts.Spec.Containers = append(pts.Spec.Containers, *collector)
+	}
 
 	if name := GetServiceAccountName(ba); name != "" {
 		pts.Spec.ServiceAccountName = name
@@ -890,8 +994,12 @@ func CustomizeServiceAccount(sa *corev1.ServiceAccount, ba common.BaseComponent,
 		// secret from the service account
 		delete(ba.GetStatus().GetReferences(), common.StatusReferencePullSecretName)
 	} else {
-		// Add the pull secrets from the CR to the service account. First check that all are valid
+		// Add the pull secrets from the CR to the service account. First check that all are valid.
+		// The check is skipped without a client, e.g. when rendering manifests offline
 		for _, ps := range crPullSecrets {
+			if client == nil {
+				break
+			}
 			err := client.Get(context.TODO(), types.NamespacedName{Name: ps, Namespace: ba.(metav1.Object).GetNamespace()}, &corev1.Secret{})
 			if err != nil {
 				return err
@@ -969,7 +1077,7 @@ func CustomizeKnativeService(ksvc *servingv1.Service, ba common.BaseComponent) {
 		ksvc.Spec.Template.Spec.Containers[0].Ports = append(ksvc.Spec.Template.Spec.Containers[0].Ports, corev1.ContainerPort{})
 	}
 	ksvc.Spec.Template.ObjectMeta.Labels = ba.GetLabels()
-	ksvc.Spec.Template.ObjectMeta.Annotations = MergeMaps(ksvc.Spec.Template.ObjectMeta.Annotations, ba.GetAnnotations())
+	ksvc.Spec.Template.ObjectMeta.Annotations = mergePodTemplateAnnotations(ksvc.Spec.Template.ObjectMeta.Annotations, ba)
 
 	if ba.GetService().GetTargetPort() != nil {
 		ksvc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort = *ba.GetService().GetTargetPort()
@@ -990,6 +1098,7 @@ func CustomizeKnativeService(ksvc *servingv1.Service, ba common.BaseComponent) {
 	ksvc.Spec.Template.Spec.Containers[0].ImagePullPolicy = *ba.GetPullPolicy()
 	ksvc.Spec.Template.Spec.Containers[0].Env = ba.GetEnv()
 	ksvc.Spec.Template.Spec.Containers[0].EnvFrom = ba.GetEnvFrom()
+	ksvc.Spec.Template.Spec.Containers[0].Env = appendTracingEnv(ksvc.Spec.Template.Spec.Containers[0].Env, ba)
 
 	ksvc.Spec.Template.Spec.HostAliases = ba.GetHostAliases()
 
@@ -1036,6 +1145,11 @@ func CustomizeKnativeService(ksvc *servingv1.Service, ba common.BaseComponent) {
 	} else {
 		ksvc.Spec.Template.Spec.PriorityClassName = ""
 	}
+
+	ksvc.Spec.Template.Spec.Containers = ksvc.Spec.Template.Spec.Containers[:1]
+	if collector := getTracingCollectorContainer(ba); collector != nil {
+		ksvc.Spec.Template.Spec.Containers = append(ksvc.Spec.Template.Spec.Containers, *collector)
+	}
 }
 
 // CustomizeHPA for autoscaling/v2
@@ -1079,6 +1193,24 @@ func CustomizeHPA(hpa *autoscalingv2.HorizontalPodAutoscaler, ba common.BaseComp
 		metricsList = append(metricsList, metrics...)
 	}
 
+	// Metrics served by the Prometheus Adapter from the rules of the instance
+	for _, metric := range ba.GetAutoscaling().GetPrometheus() {
+		target := metric.GetTarget()
+		metricTarget := autoscalingv2.MetricTarget{Type: metric.GetTargetType()}
+		if metricTarget.Type == autoscalingv2.ValueMetricType {
+			metricTarget.Value = &target
+		} else {
+			metricTarget.AverageValue = &target
+		}
+		metricsList = append(metricsList, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: GetPrometheusMetricName(ba, metric)},
+				Target: metricTarget,
+			},
+		})
+	}
+
 	hpa.Spec.Metrics = metricsList
 	hpa.Spec.ScaleTargetRef.Name = obj.GetName()
 	hpa.Spec.ScaleTargetRef.APIVersion = "apps/v1"
@@ -1105,6 +1237,18 @@ func Validate(ba common.BaseComponent) (bool, error) {
 		}
 	}
 
+	// Monitoring endpoints validation
+	if ba.GetMonitoring() != nil && ba.GetMonitoring().GetKind() == common.MonitoringKindServiceMonitor && ba.GetService() != nil {
+		for i, endpoint := range ba.GetMonitoring().GetEndpoints() {
+			if endpoint.Port != "" && findServicePort(ba, &intstr.IntOrString{Type: intstr.String, StrVal: endpoint.Port}) == nil {
+				return false, fmt.Errorf("validation failed: spec.monitoring.endpoints[%d].port %q is not a port of the Service", i, endpoint.Port)
+			}
+			if endpoint.Port == "" && endpoint.TargetPort != nil && findServicePort(ba, endpoint.TargetPort) == nil {
+				return false, fmt.Errorf("validation failed: spec.monitoring.endpoints[%d].targetPort %q is not a port of the Service", i, endpoint.TargetPort.String())
+			}
+		}
+	}
+
 	return true, nil
 }
 
@@ -1209,103 +1353,230 @@ func requiredFieldMessage(fieldPaths ...string) string {
 
 // CustomizeServiceMonitor ...
 func CustomizeServiceMonitor(sm *prometheusv1.ServiceMonitor, ba common.BaseComponent) {
-	obj := ba.(metav1.Object)
 	sm.Labels = ba.GetLabels()
 	sm.Annotations = MergeMaps(sm.Annotations, ba.GetAnnotations())
 
 	sm.Spec.Selector = metav1.LabelSelector{
 		MatchLabels: map[string]string{
-			"app.kubernetes.io/instance":                obj.GetName(),
+			"app.kubernetes.io/instance":                ba.(metav1.Object).GetName(),
 			"monitor." + ba.GetGroupName() + "/enabled": "true",
 		},
 	}
-	if len(sm.Spec.Endpoints) == 0 {
+
+	// Every monitoring endpoint is mapped to a ServiceMonitor endpoint, the main Service port is scraped by default
+	endpoints := ba.GetMonitoring().GetEndpoints()
+	if len(endpoints) == 0 {
+		endpoints = []prometheusv1.Endpoint{{}}
+	}
+	for len(sm.Spec.Endpoints) < len(endpoints) {
 		sm.Spec.Endpoints = append(sm.Spec.Endpoints, prometheusv1.Endpoint{})
 	}
-	sm.Spec.Endpoints[0].Port = ""
-	sm.Spec.Endpoints[0].TargetPort = nil
-	sm.Spec.Endpoints[0].TLSConfig = nil
-	sm.Spec.Endpoints[0].Scheme = nil
+	sm.Spec.Endpoints = sm.Spec.Endpoints[:len(endpoints)]
+	for i := range endpoints {
+		customizeServiceMonitorEndpoint(&sm.Spec.Endpoints[i], &endpoints[i], ba)
+	}
 
-	if len(ba.GetMonitoring().GetEndpoints()) > 0 {
-		port := ba.GetMonitoring().GetEndpoints()[0].Port
-		targetPort := ba.GetMonitoring().GetEndpoints()[0].TargetPort
-		if port != "" {
-			sm.Spec.Endpoints[0].Port = port
-		}
-		if targetPort != nil {
-			sm.Spec.Endpoints[0].TargetPort = targetPort
-		}
-		if port != "" && targetPort != nil {
-			sm.Spec.Endpoints[0].TargetPort = nil
+	if len(ba.GetMonitoring().GetLabels()) > 0 {
+		for k, v := range ba.GetMonitoring().GetLabels() {
+			sm.Labels[k] = v
 		}
 	}
-	if sm.Spec.Endpoints[0].Port == "" && sm.Spec.Endpoints[0].TargetPort == nil {
-		if ba.GetService().GetPortName() != "" {
-			sm.Spec.Endpoints[0].Port = ba.GetService().GetPortName()
+}
+
+// customizeServiceMonitorEndpoint sets a ServiceMonitor endpoint from a monitoring endpoint of the instance. The port
+// of the endpoint is resolved against the ports of the Service, and the endpoint is scraped over TLS with the service
+// certificate when the operator manages TLS and the endpoint has no TLS configuration
+func customizeServiceMonitorEndpoint(smEndpoint *prometheusv1.Endpoint, endpoint *prometheusv1.Endpoint, ba common.BaseComponent) {
+	obj := ba.(metav1.Object)
+	smEndpoint.Port = ""
+	smEndpoint.TargetPort = nil
+	smEndpoint.TLSConfig = nil
+	smEndpoint.Scheme = nil
+
+	if endpoint.Port != "" {
+		smEndpoint.Port = endpoint.Port
+	} else if endpoint.TargetPort != nil {
+		if svcPort := findServicePort(ba, endpoint.TargetPort); svcPort != nil {
+			smEndpoint.Port = svcPort.Name
 		} else {
-			sm.Spec.Endpoints[0].Port = strconv.Itoa(int(ba.GetService().GetPort())) + "-tcp"
+			smEndpoint.TargetPort = endpoint.TargetPort
 		}
+	} else {
+		smEndpoint.Port = getServicePorts(ba)[0].Name
+	}
+
+	if endpoint.Scheme != nil {
+		smEndpoint.Scheme = endpoint.Scheme
+	}
+	if endpoint.Interval != "" {
+		smEndpoint.Interval = endpoint.Interval
+	}
+	if endpoint.Path != "" {
+		smEndpoint.Path = endpoint.Path
+	}
+
+	if endpoint.TLSConfig != nil {
+		smEndpoint.TLSConfig = endpoint.TLSConfig
+	}
+
+	if endpoint.BasicAuth != nil {
+		smEndpoint.BasicAuth = endpoint.BasicAuth
+	}
+
+	if endpoint.Params != nil {
+		smEndpoint.Params = endpoint.Params
+	}
+	if endpoint.ScrapeTimeout != "" {
+		smEndpoint.ScrapeTimeout = endpoint.ScrapeTimeout
+	}
+	if endpoint.BearerTokenFile != "" {
+		smEndpoint.BearerTokenFile = endpoint.BearerTokenFile
+	}
+	smEndpoint.BearerTokenSecret = endpoint.BearerTokenSecret
+	smEndpoint.ProxyURL = endpoint.ProxyURL
+	smEndpoint.RelabelConfigs = endpoint.RelabelConfigs
+	smEndpoint.MetricRelabelConfigs = endpoint.MetricRelabelConfigs
+	smEndpoint.HonorTimestamps = endpoint.HonorTimestamps
+	smEndpoint.HonorLabels = endpoint.HonorLabels
+
+	if (ba.GetManageTLS() == nil || *ba.GetManageTLS()) && endpoint.TLSConfig == nil {
+		https_scheme := prometheusv1.Scheme("https")
+		smEndpoint.Scheme = &https_scheme
+		smEndpoint.TLSConfig = &prometheusv1.TLSConfig{}
+		smEndpoint.TLSConfig.CA = prometheusv1.SecretOrConfigMap{}
+		smEndpoint.TLSConfig.CA.Secret = &corev1.SecretKeySelector{}
+		smEndpoint.TLSConfig.CA.Secret.Name = ba.GetStatus().GetReferences()[common.StatusReferenceCertSecretName]
+		smEndpoint.TLSConfig.CA.Secret.Key = "tls.crt"
+		serverName := obj.GetName() + "." + obj.GetNamespace() + ".svc"
+		smEndpoint.TLSConfig.ServerName = &serverName
 	}
+}
+
+// CustomizePodMonitor sets the PodMonitor that scrapes the pods of the instance directly, which also supports
+// Knative services. The monitoring endpoints are mapped as in CustomizeServiceMonitor
+func CustomizePodMonitor(pm *prometheusv1.PodMonitor, ba common.BaseComponent) {
+	pm.Labels = ba.GetLabels()
+	pm.Annotations = MergeMaps(pm.Annotations, ba.GetAnnotations())
+
+	pm.Spec.Selector = metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"app.kubernetes.io/instance": ba.(metav1.Object).GetName(),
+		},
+	}
+
+	endpoints := ba.GetMonitoring().GetEndpoints()
+	if len(endpoints) == 0 {
+		endpoints = []prometheusv1.Endpoint{{}}
+	}
+	for len(pm.Spec.PodMetricsEndpoints) < len(endpoints) {
+		pm.Spec.PodMetricsEndpoints = append(pm.Spec.PodMetricsEndpoints, prometheusv1.PodMetricsEndpoint{})
+	}
+	pm.Spec.PodMetricsEndpoints = pm.Spec.PodMetricsEndpoints[:len(endpoints)]
+	for i := range endpoints {
+		// Reuse the ServiceMonitor endpoint mapping, then resolve the Service port to a port of the pods
+		smEndpoint := prometheusv1.Endpoint{}
+		customizeServiceMonitorEndpoint(&smEndpoint, &endpoints[i], ba)
+		pmEndpoint := &pm.Spec.PodMetricsEndpoints[i]
+		pmEndpoint.Port, pmEndpoint.TargetPort = getPodMetricsEndpointPort(ba, &smEndpoint)
+		pmEndpoint.Path = smEndpoint.Path
+		pmEndpoint.Scheme = smEndpoint.Scheme
+		pmEndpoint.Params = smEndpoint.Params
+		pmEndpoint.Interval = smEndpoint.Interval
+		pmEndpoint.ScrapeTimeout = smEndpoint.ScrapeTimeout
+		pmEndpoint.HonorLabels = smEndpoint.HonorLabels
+		pmEndpoint.HonorTimestamps = smEndpoint.HonorTimestamps
+		pmEndpoint.RelabelConfigs = smEndpoint.RelabelConfigs
+		pmEndpoint.MetricRelabelConfigs = smEndpoint.MetricRelabelConfigs
+		pmEndpoint.HTTPConfigWithoutTLS = smEndpoint.HTTPConfigWithoutTLS
+		pmEndpoint.ProxyConfig = smEndpoint.ProxyConfig
+		pmEndpoint.TLSConfig = nil
+		if smEndpoint.TLSConfig != nil {
+			pmEndpoint.TLSConfig = smEndpoint.TLSConfig.SafeTLSConfig.DeepCopy()
+		}
+		// Knative pods serve plain HTTP, the service certificate is not generated for them
+		if endpoints[i].TLSConfig == nil && ba.GetCreateKnativeService() != nil && *ba.GetCreateKnativeService() {
+			pmEndpoint.Scheme = endpoints[i].Scheme
+			pmEndpoint.TLSConfig = nil
+		}
+	}
+
 	if len(ba.GetMonitoring().GetLabels()) > 0 {
 		for k, v := range ba.GetMonitoring().GetLabels() {
-			sm.Labels[k] = v
+			pm.Labels[k] = v
 		}
 	}
+}
 
-	if len(ba.GetMonitoring().GetEndpoints()) > 0 {
-		endpoints := ba.GetMonitoring().GetEndpoints()
-		if endpoints[0].Scheme != nil {
-			sm.Spec.Endpoints[0].Scheme = endpoints[0].Scheme
-		}
-		if endpoints[0].Interval != "" {
-			sm.Spec.Endpoints[0].Interval = endpoints[0].Interval
-		}
-		if endpoints[0].Path != "" {
-			sm.Spec.Endpoints[0].Path = endpoints[0].Path
+// getPodMetricsEndpointPort returns the port of the pods that serves the Service port of a ServiceMonitor endpoint. The
+// name of the application container port is used for the main port, and the number of the target port otherwise
+func getPodMetricsEndpointPort(ba common.BaseComponent, smEndpoint *prometheusv1.Endpoint) (*string, *intstr.IntOrString) {
+	var svcPort *corev1.ServicePort
+	if smEndpoint.Port != "" {
+		svcPort = findServicePort(ba, &intstr.IntOrString{Type: intstr.String, StrVal: smEndpoint.Port})
+	} else if smEndpoint.TargetPort != nil {
+		svcPort = findServicePort(ba, smEndpoint.TargetPort)
+	}
+	if svcPort == nil {
+		// Not a port of the Service, such as the port of a sidecar container
+		if smEndpoint.Port != "" {
+			port := smEndpoint.Port
+			return &port, nil
 		}
+		return nil, smEndpoint.TargetPort
+	}
 
-		if endpoints[0].TLSConfig != nil {
-			sm.Spec.Endpoints[0].TLSConfig = endpoints[0].TLSConfig
+	// Knative renames the container port, so its number is used instead
+	main := getServicePorts(ba)[0]
+	if svcPort.Name == main.Name && (ba.GetCreateKnativeService() == nil || !*ba.GetCreateKnativeService()) {
+		port := ba.GetService().GetPortName()
+		if port == "" {
+			port = strconv.Itoa(int(main.TargetPort.IntVal)) + "-tcp"
 		}
+		return &port, nil
+	}
+	targetPort := intstr.FromInt(int(svcPort.TargetPort.IntVal))
+	return nil, &targetPort
+}
 
-		if endpoints[0].BasicAuth != nil {
-			sm.Spec.Endpoints[0].BasicAuth = endpoints[0].BasicAuth
-		}
+// getServicePorts returns the ports of the Service generated by CustomizeService, starting with the main port
+func getServicePorts(ba common.BaseComponent) []corev1.ServicePort {
+	main := corev1.ServicePort{Port: ba.GetService().GetPort(), TargetPort: intstr.FromInt(int(ba.GetService().GetPort()))}
+	if ba.GetService().GetPortName() != "" {
+		main.Name = ba.GetService().GetPortName()
+	} else {
+		main.Name = strconv.Itoa(int(main.Port)) + "-tcp"
+	}
+	if ba.GetService().GetTargetPort() != nil {
+		main.TargetPort = intstr.FromInt(int(*ba.GetService().GetTargetPort()))
+	}
 
-		if endpoints[0].Params != nil {
-			sm.Spec.Endpoints[0].Params = endpoints[0].Params
-		}
-		if endpoints[0].ScrapeTimeout != "" {
-			sm.Spec.Endpoints[0].ScrapeTimeout = endpoints[0].ScrapeTimeout
+	ports := []corev1.ServicePort{main}
+	for _, p := range ba.GetService().GetPorts() {
+		port := corev1.ServicePort{Name: p.Name, Port: p.Port, TargetPort: intstr.FromInt(int(p.Port))}
+		if port.Name == "" {
+			port.Name = strconv.Itoa(int(p.Port)) + "-tcp"
 		}
-		if endpoints[0].BearerTokenFile != "" {
-			sm.Spec.Endpoints[0].BearerTokenFile = endpoints[0].BearerTokenFile
+		if p.TargetPort.IntValue() != 0 {
+			port.TargetPort = intstr.FromInt(p.TargetPort.IntValue())
 		}
-		sm.Spec.Endpoints[0].BearerTokenSecret = endpoints[0].BearerTokenSecret
-		sm.Spec.Endpoints[0].ProxyURL = endpoints[0].ProxyURL
-		sm.Spec.Endpoints[0].RelabelConfigs = endpoints[0].RelabelConfigs
-		sm.Spec.Endpoints[0].MetricRelabelConfigs = endpoints[0].MetricRelabelConfigs
-		sm.Spec.Endpoints[0].HonorTimestamps = endpoints[0].HonorTimestamps
-		sm.Spec.Endpoints[0].HonorLabels = endpoints[0].HonorLabels
+		ports = append(ports, port)
 	}
-	if ba.GetManageTLS() == nil || *ba.GetManageTLS() {
-		if len(ba.GetMonitoring().GetEndpoints()) == 0 || ba.GetMonitoring().GetEndpoints()[0].TLSConfig == nil {
-			https_scheme := prometheusv1.Scheme("https")
-			sm.Spec.Endpoints[0].Scheme = &https_scheme
-			if sm.Spec.Endpoints[0].TLSConfig == nil {
-				sm.Spec.Endpoints[0].TLSConfig = &prometheusv1.TLSConfig{}
-			}
-			sm.Spec.Endpoints[0].TLSConfig.CA = prometheusv1.SecretOrConfigMap{}
-			sm.Spec.Endpoints[0].TLSConfig.CA.Secret = &corev1.SecretKeySelector{}
-			sm.Spec.Endpoints[0].TLSConfig.CA.Secret.Name = ba.GetStatus().GetReferences()[common.StatusReferenceCertSecretName]
-			sm.Spec.Endpoints[0].TLSConfig.CA.Secret.Key = "tls.crt"
-			serverName := obj.GetName() + "." + obj.GetNamespace() + ".svc"
-			sm.Spec.Endpoints[0].TLSConfig.ServerName = &serverName
-		}
+	return ports
+}
 
+// findServicePort returns the port of the Service with the given name, or with the given number as its port or
+// target port. It returns nil if the Service has no such port
+func findServicePort(ba common.BaseComponent, port *intstr.IntOrString) *corev1.ServicePort {
+	ports := getServicePorts(ba)
+	for i := range ports {
+		if port.Type == intstr.String && ports[i].Name == port.StrVal {
+			return &ports[i]
+		}
+		if port.Type == intstr.Int && (ports[i].Port == port.IntVal || ports[i].TargetPort.IntVal == port.IntVal) {
+			return &ports[i]
+		}
 	}
-
+	return nil
 }
 
 // GetWatchNamespaces returns a slice of namespaces the operator should watch based on WATCH_NAMESPSCE value
@@ -1500,9 +1771,8 @@ func CustomizeIngress(ing *networkingv1.Ingress, ba common.BaseComponent) {
 	if ba.GetService().GetPortName() != "" {
 		servicePort = ba.GetService().GetPortName()
 	}
-	defaultHostName := common.LoadFromConfig(common.Config, common.OpConfigDefaultHostname)
-	if host == "" && defaultHostName != "" {
-		host = obj.GetName() + "-" + obj.GetNamespace() + "." + defaultHostName
+	if host == "" {
+		host = getHostnameOrEmpty(ba)
 	}
 
 	if host == "" {
@@ -1538,10 +1808,7 @@ func CustomizeIngress(ing *networkingv1.Ingress, ba common.BaseComponent) {
 		},
 	}
 
-	tlsSecretName := ""
-	if rt != nil && rt.GetCertificateSecretRef() != nil && *rt.GetCertificateSecretRef() != "" {
-		tlsSecretName = *rt.GetCertificateSecretRef()
-	}
+	tlsSecretName := GetRouteCertificateSecretName(ba)
 	if tlsSecretName != "" && host != "" {
 		ing.Spec.TLS = []networkingv1.IngressTLS{
 			{
@@ -1781,11 +2048,15 @@ func CustomizePodWithSVCCertificate(pts *corev1.PodTemplateSpec, ba common.BaseC
 	if ba.GetManageTLS() == nil || *ba.GetManageTLS() || ba.GetService().GetCertificateSecretRef() != nil {
 		obj := ba.(metav1.Object)
 		secretName := ba.GetStatus().GetReferences()[common.StatusReferenceCertSecretName]
-		if secretName != "" {
-			return addSecretHashAsAnnotation(pts, obj, client, secretName, ba.GetGroupName())
-		} else {
+		if secretName == "" {
 			return errors.New("Service certifcate secret name must not be empty")
 		}
+		if err := addSecretHashAsAnnotation(pts, obj, client, secretName, ba.GetGroupName()); err != nil {
+			return err
+		}
+		if clientSecretName := ba.GetStatus().GetReferences()[common.StatusReferenceClientCertSecretName]; clientSecretName != "" && IsClientCertificateEnabled(ba) {
+			return addSecretHashAsAnnotation(pts, obj, client, clientSecretName, ba.GetGroupName())
+		}
 	}
 	return nil
 }
@@ -1870,6 +2141,10 @@ func UpdateConfigMap(configMap *corev1.ConfigMap, key string) {
 }
 
 func GetIssuerResourceVersion(client client.Client, certificate *certmanagerv1.Certificate) (string, error) {
+	// ClusterIssuers cannot be read with the namespaced role of the operator
+	if certificate.Spec.IssuerRef.Kind == "ClusterIssuer" {
+		return "", nil
+	}
 	issuer := &certmanagerv1.Issuer{}
 	err := client.Get(context.Background(), types.NamespacedName{Name: certificate.Spec.IssuerRef.Name,
 		Namespace: certificate.Namespace}, issuer)
@@ -1963,15 +2238,55 @@ func ShouldDeleteRoute(ba common.BaseComponent) bool {
 	if rh != "" {
 		// The host was previously set.
 		// If the host is now empty, delete the old route
-		rt := ba.GetRoute()
-		defaultHostName := common.LoadFromConfig(common.Config, common.OpConfigDefaultHostname)
-		if rt == nil || (rt.GetHost() == "" && defaultHostName == "") {
+		if ba.GetRoute() == nil || getHostnameOrEmpty(ba) == "" {
 			return true
 		}
 	}
 	return false
 }
 
+// Returns the name of the label that marks the Service of a monitored instance
+func GetMonitoringEnabledLabelName(ba common.BaseComponent) string {
+	return "monitor." + ba.GetGroupName() + "/enabled"
+}
+
+// Returns the name of the annotation used to pause reconciliation of an instance
+func GetPausedAnnotationName(ba common.BaseComponent) string {
+	return ba.GetGroupName() + "/paused"
+}
+
+// Returns the name of the annotation recording the application that paused reconciliation of the instance
+func GetPausedByApplicationAnnotationName(ba common.BaseComponent) string {
+	return ba.GetGroupName() + "/paused-by-application"
+}
+
+// Returns the annotations of a pod template merged with the annotations of the instance, without the annotations
+// that control the operator, so that pausing, resuming or adopting does not roll out the pods
+func mergePodTemplateAnnotations(annotations map[string]string, ba common.BaseComponent) map[string]string {
+	merged := MergeMaps(annotations, ba.GetAnnotations())
+	for _, annotation := range []string{GetPausedAnnotationName(ba), GetPausedByApplicationAnnotationName(ba), GetAdoptAnnotationName(ba)} {
+		delete(merged, annotation)
+	}
+	return merged
+}
+
+// Returns the name of the annotation that restarts the pods of the instance when its value changes
+func GetRestartedAtAnnotationName(ba common.BaseComponent) string {
+	return ba.GetGroupName() + "/restartedAt"
+}
+
+// Returns true and a message describing the cause if reconciliation of the instance is paused,
+// either through the paused annotation or for all instances through the operator ConfigMap
+func IsReconcilePaused(ba common.BaseComponent) (bool, string) {
+	if common.LoadFromConfig(common.Config, common.OpConfigPauseReconciliation) == "true" {
+		return true, "Reconciliation is paused for all instances by " + common.OpConfigPauseReconciliation + " in the operator ConfigMap."
+	}
+	if ba.GetAnnotations()[GetPausedAnnotationName(ba)] == "true" {
+		return true, "Reconciliation is paused by the " + GetPausedAnnotationName(ba) + " annotation."
+	}
+	return false, ""
+}
+
 // Returns the configured operator max concurrent reconciles setting
 func GetMaxConcurrentReconciles() int {
 	envValue := os.Getenv("MAX_CONCURRENT_RECONCILES")
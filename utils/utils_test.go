@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	appstacksv1 "github.com/application-stacks/runtime-component-operator/api/v1"
@@ -14,6 +15,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cruntime "k8s.io/apimachinery/pkg/runtime"
@@ -380,6 +382,18 @@ func TestCustomizePodSpecAnnotations(t *testing.T) {
 	}
 	verifyTests(testAnnotations4, t)
 
+	// The annotations that control the operator are not set on the pods, also when they were set before
+	runtime5 := createRuntimeComponent(name, namespace, spec)
+	runtime5.Annotations = map[string]string{GetPausedAnnotationName(runtime5): "true", GetPausedByApplicationAnnotationName(runtime5): "shop",
+		GetAdoptAnnotationName(runtime5): "true", GetRestartedAtAnnotationName(runtime5): "2026-10-18T10:00:00Z"}
+	pts5 := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{GetPausedAnnotationName(runtime5): "true"}}}
+	CustomizePodSpec(pts5, runtime5)
+	testAnnotations5 := []Test{
+		{"Control annotations not set", 2, len(pts5.Annotations)},
+		{"Restarted at annotation set", "2026-10-18T10:00:00Z", pts5.Annotations[GetRestartedAtAnnotationName(runtime5)]},
+	}
+	verifyTests(testAnnotations5, t)
+
 }
 
 func TestCustomizePodSpec(t *testing.T) {
@@ -810,6 +824,99 @@ func TestCustomizeServiceMonitor(t *testing.T) {
 	verifyTests(testSM, t)
 }
 
+func TestCustomizeServiceMonitorEndpoints(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	http_scheme := prometheusv1.SchemeHTTP
+	adminTargetPort := intstr.FromInt(9080)
+	svc := &appstacksv1.RuntimeComponentService{Type: &serviceType, Port: 8443,
+		Ports: []corev1.ServicePort{{Name: "admin", Port: 9000, TargetPort: adminTargetPort}}}
+	spec := appstacksv1.RuntimeComponentSpec{Service: svc}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Status.SetReference(common.StatusReferenceCertSecretName, "my-app-svc-tls")
+	runtime.Spec.Monitoring = &appstacksv1.RuntimeComponentMonitoring{Endpoints: []prometheusv1.Endpoint{
+		{Path: "/metrics"},
+		{TargetPort: &adminTargetPort, Path: "/admin/metrics"},
+		{Port: "admin", Scheme: &http_scheme, HTTPConfigWithProxyAndTLSFiles: prometheusv1.HTTPConfigWithProxyAndTLSFiles{
+			HTTPConfigWithTLSFiles: prometheusv1.HTTPConfigWithTLSFiles{TLSConfig: &prometheusv1.TLSConfig{}}}},
+	}}
+
+	sm := &prometheusv1.ServiceMonitor{}
+	CustomizeServiceMonitor(sm, runtime)
+	endpoints := append([]prometheusv1.Endpoint{}, sm.Spec.Endpoints...)
+	valid, err := Validate(runtime)
+
+	// Removing an endpoint removes it from the ServiceMonitor
+	runtime.Spec.Monitoring.Endpoints = runtime.Spec.Monitoring.Endpoints[:1]
+	CustomizeServiceMonitor(sm, runtime)
+	remaining := len(sm.Spec.Endpoints)
+
+	runtime.Spec.Monitoring.Endpoints = []prometheusv1.Endpoint{{Port: "metrics"}}
+	_, portErr := Validate(runtime)
+	unknownTargetPort := intstr.FromInt(9999)
+	runtime.Spec.Monitoring.Endpoints = []prometheusv1.Endpoint{{TargetPort: &unknownTargetPort}}
+	_, targetPortErr := Validate(runtime)
+
+	testSME := []Test{
+		{"endpoints", 3, len(endpoints)},
+		{"default port", "8443-tcp", endpoints[0].Port},
+		{"default TLS scheme", prometheusv1.Scheme("https"), *endpoints[0].Scheme},
+		{"default TLS CA", "my-app-svc-tls", endpoints[0].TLSConfig.CA.Secret.Name},
+		{"target port resolved", "admin", endpoints[1].Port},
+		{"target port cleared", true, endpoints[1].TargetPort == nil},
+		{"TLS on every endpoint", "my-app.runtime.svc", *endpoints[1].TLSConfig.ServerName},
+		{"endpoint path", "/admin/metrics", endpoints[1].Path},
+		{"endpoint scheme", prometheusv1.SchemeHTTP, *endpoints[2].Scheme},
+		{"endpoint TLS config", true, endpoints[2].TLSConfig.CA.Secret == nil},
+		{"removed endpoint", 1, remaining},
+		{"valid", true, valid},
+		{"no validation error", nil, err},
+		{"unknown port", true, portErr != nil && strings.Contains(portErr.Error(), "spec.monitoring.endpoints[0].port")},
+		{"unknown target port", true, targetPortErr != nil && strings.Contains(targetPortErr.Error(), "spec.monitoring.endpoints[0].targetPort")},
+	}
+	verifyTests(testSME, t)
+}
+
+func TestCustomizePodMonitor(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+
+	kind := common.MonitoringKindPodMonitor
+	adminTargetPort := intstr.FromInt(9080)
+	svc := &appstacksv1.RuntimeComponentService{Type: &serviceType, Port: 8443, TargetPort: &targetPort,
+		Ports: []corev1.ServicePort{{Name: "admin", Port: 9000, TargetPort: adminTargetPort}}}
+	spec := appstacksv1.RuntimeComponentSpec{Service: svc}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	runtime.Status.SetReference(common.StatusReferenceCertSecretName, "my-app-svc-tls")
+	runtime.Spec.Monitoring = &appstacksv1.RuntimeComponentMonitoring{Kind: &kind, Labels: map[string]string{"team": "a"},
+		Endpoints: []prometheusv1.Endpoint{{}, {Port: "admin", Path: "/admin/metrics"}, {Port: "sidecar-metrics"}}}
+
+	pm := &prometheusv1.PodMonitor{}
+	CustomizePodMonitor(pm, runtime)
+	valid, _ := Validate(runtime)
+
+	runtime.Spec.CreateKnativeService = &createKNS
+	knativePM := &prometheusv1.PodMonitor{}
+	CustomizePodMonitor(knativePM, runtime)
+
+	testCPM := []Test{
+		{"selector", map[string]string{"app.kubernetes.io/instance": name}, pm.Spec.Selector.MatchLabels},
+		{"labels", "a", pm.Labels["team"]},
+		{"endpoints", 3, len(pm.Spec.PodMetricsEndpoints)},
+		{"container port", "3333-tcp", *pm.Spec.PodMetricsEndpoints[0].Port},
+		{"TLS scheme", prometheusv1.Scheme("https"), *pm.Spec.PodMetricsEndpoints[0].Scheme},
+		{"TLS CA", "my-app-svc-tls", pm.Spec.PodMetricsEndpoints[0].TLSConfig.CA.Secret.Name},
+		{"additional port", intstr.FromInt(9080), *pm.Spec.PodMetricsEndpoints[1].TargetPort},
+		{"path", "/admin/metrics", pm.Spec.PodMetricsEndpoints[1].Path},
+		{"port of another container", "sidecar-metrics", *pm.Spec.PodMetricsEndpoints[2].Port},
+		{"ports of other containers are valid", true, valid},
+		{"Knative container port", intstr.FromInt(int(targetPort)), *knativePM.Spec.PodMetricsEndpoints[0].TargetPort},
+		{"Knative without TLS", true, knativePM.Spec.PodMetricsEndpoints[0].TLSConfig == nil && knativePM.Spec.PodMetricsEndpoints[0].Scheme == nil},
+	}
+	verifyTests(testCPM, t)
+}
+
 func TestGetWatchNamespaces(t *testing.T) {
 	// Set the logger to development mode for verbose logs
 	logger := zap.New()
@@ -938,6 +1045,38 @@ func TestShouldDeleteRoute(t *testing.T) {
 	verifyTests(testCR, t)
 }
 
+func TestIsReconcilePaused(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	spec := appstacksv1.RuntimeComponentSpec{}
+	runtime := createRuntimeComponent(name, namespace, spec)
+	defaultCase, _ := IsReconcilePaused(runtime)
+
+	// Paused annotation set to a value other than "true"
+	runtime.Annotations = map[string]string{"rc.app.stacks/paused": "false"}
+	notPaused, _ := IsReconcilePaused(runtime)
+
+	// Paused by the instance annotation
+	runtime.Annotations = map[string]string{"rc.app.stacks/paused": "true"}
+	pausedByAnnotation, _ := IsReconcilePaused(runtime)
+
+	// Paused for all instances by the operator ConfigMap
+	runtime.Annotations = nil
+	common.Config.Store(common.OpConfigPauseReconciliation, "true")
+	pausedByConfig, message := IsReconcilePaused(runtime)
+	common.Config.Store(common.OpConfigPauseReconciliation, "false")
+
+	testIRP := []Test{
+		{test: "default case", expected: false, actual: defaultCase},
+		{test: "paused annotation is false", expected: false, actual: notPaused},
+		{test: "paused annotation is true", expected: true, actual: pausedByAnnotation},
+		{test: "paused by operator config", expected: true, actual: pausedByConfig},
+		{test: "paused by operator config message", expected: true, actual: strings.Contains(message, common.OpConfigPauseReconciliation)},
+	}
+
+	verifyTests(testIRP, t)
+}
+
 func TestGetEnvVarValue(t *testing.T) {
 	logger := zap.New()
 	logf.SetLogger(logger)
@@ -964,6 +1103,57 @@ func TestGetEnvVarValue(t *testing.T) {
 	verifyTests(testGEVV, t)
 }
 
+func TestCustomizeNetworkPolicy(t *testing.T) {
+	logger := zap.New()
+	logf.SetLogger(logger)
+	common.Config = common.DefaultOpConfig()
+
+	cidrPeer := networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}
+	spec := appstacksv1.RuntimeComponentSpec{ApplicationImage: appImage, ApplicationName: "my-application", Service: service,
+		NetworkPolicy: &appstacksv1.RuntimeComponentNetworkPolicy{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{cidrPeer}}},
+		}}
+	runtimecomponent := createRuntimeComponent(name, namespace, spec)
+	networkPolicy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	CustomizeNetworkPolicy(networkPolicy, false, runtimecomponent)
+	ingress := networkPolicy.Spec.Ingress
+	policyTypes := networkPolicy.Spec.PolicyTypes
+
+	runtimecomponent.Spec.NetworkPolicy.Egress = &appstacksv1.RuntimeComponentNetworkPolicyEgress{
+		Rules: []networkingv1.NetworkPolicyEgressRule{{To: []networkingv1.NetworkPolicyPeer{cidrPeer}}},
+	}
+	CustomizeNetworkPolicy(networkPolicy, false, runtimecomponent)
+	noDependencies := networkPolicy.Spec.Egress
+
+	dataNamespace := "data"
+	runtimecomponent.Spec.NetworkPolicy.Egress.Dependencies = []appstacksv1.RuntimeComponentDependency{
+		{Name: "backend"}, {Name: "postgres", Namespace: &dataNamespace},
+	}
+	CustomizeNetworkPolicy(networkPolicy, true, runtimecomponent)
+	egress := networkPolicy.Spec.Egress
+
+	runtimecomponent.Spec.NetworkPolicy.Egress = nil
+	CustomizeNetworkPolicy(networkPolicy, false, runtimecomponent)
+
+	testCNP := []Test{
+		{"additional ingress rule", []networkingv1.NetworkPolicyPeer{cidrPeer}, ingress[1].From},
+		{"Kubernetes monitoring peer", map[string]string{"kubernetes.io/metadata.name": "monitoring"}, ingress[0].From[1].NamespaceSelector.MatchLabels},
+		{"ingress only", []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policyTypes},
+		{"DNS ports", 4, len(egress[0].Ports)},
+		{"OpenShift DNS port", int32(5353), egress[0].Ports[2].Port.IntVal},
+		{"no dependencies", 2, len(noDependencies)},
+		{"application not allowed without dependency", []networkingv1.NetworkPolicyPeer{cidrPeer}, noDependencies[1].To},
+		{"dependency", map[string]string{"rc.app.stacks/name": "backend"}, egress[1].To[0].PodSelector.MatchLabels},
+		{"dependency namespace", map[string]string{"kubernetes.io/metadata.name": namespace}, egress[1].To[0].NamespaceSelector.MatchLabels},
+		{"dependency in another namespace", map[string]string{"kubernetes.io/metadata.name": "data"}, egress[1].To[1].NamespaceSelector.MatchLabels},
+		{"dependency pods in another namespace", map[string]string{"rc.app.stacks/name": "postgres"}, egress[1].To[1].PodSelector.MatchLabels},
+		{"additional egress rule", []networkingv1.NetworkPolicyPeer{cidrPeer}, egress[2].To},
+		{"egress removed", 0, len(networkPolicy.Spec.Egress)},
+		{"egress policy type removed", []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, networkPolicy.Spec.PolicyTypes},
+	}
+	verifyTests(testCNP, t)
+}
+
 // Helper Functions
 // Unconditionally set the proper tags for an enabled runtime omponent
 func createAppDefinitionTags(app *appstacksv1.RuntimeComponent) (map[string]string, map[string]string) {